@@ -2,12 +2,24 @@ package main
 
 import (
 	"log"
-	"net/http"
+	"os"
+	"time"
 
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/activities"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/admin"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/classes"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/conjugations"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/cors"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/dashboard"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/groups"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/languages"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/resets"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/schedulers"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/schemas"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/sessions"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/transfer"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/users"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/words"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
@@ -15,6 +27,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// Study sessions with no activity for this long are marked abandoned
+	sessionIdleTimeout   = 30 * time.Minute
+	sessionPausedTimeout = 24 * time.Hour
+	sessionSweepInterval = 5 * time.Minute
+
+	// Origins allowed to call the API from a browser unless ALLOWED_ORIGINS
+	// lists others, comma separated
+	defaultAllowedOrigins = "http://localhost:5173,http://127.0.0.1:5173"
+
+	// Snapshots of the database go to BACKUP_DIR, or this directory. One is
+	// taken every interval and the newest of each reason are kept.
+	defaultBackupDir = "backups"
+	snapshotInterval = 24 * time.Hour
+
+	// History resets past their retention are purged this often
+	historyResetPurgeInterval = time.Hour
+)
+
 func main() {
 	// Initialize database
 	err := storage.InitDB("words.db") // This should create and initialize the database
@@ -22,35 +53,60 @@ func main() {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	backupDir := os.Getenv("BACKUP_DIR")
+	if backupDir == "" {
+		backupDir = defaultBackupDir
+	}
+	storage.SetBackupDir(backupDir)
+
 	r := gin.Default()
 
 	// Add CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	})
+	allowedOrigins := os.Getenv("ALLOWED_ORIGINS")
+	if allowedOrigins == "" {
+		allowedOrigins = defaultAllowedOrigins
+	}
+	r.Use(cors.Middleware(cors.ParseOrigins(allowedOrigins)))
 
 	// Initialize services
+	authService := service.NewAuthService()
 	wordService := service.NewWordService()
 	groupService := service.NewGroupService()
 	sessionService := service.NewSessionService()
 	dashboardService := service.NewDashboardService()
 	activityService := service.NewActivityService()
+	schedulerService := service.NewSchedulerService()
+	userService := service.NewUserService()
+	resetService := service.NewResetService()
+	classService := service.NewClassService()
+	languageService := service.NewLanguageService()
+	schemaService := service.NewSchemaService()
+	conjugationService := service.NewConjugationService()
+	importService := service.NewImportService()
+	exportService := service.NewExportService()
+	backupService := service.NewBackupService()
+	historyResetService := service.NewHistoryResetService()
+
+	go sessionService.RunSweeper(sessionSweepInterval, sessionIdleTimeout, sessionPausedTimeout, nil)
+	go backupService.RunSnapshots(snapshotInterval, nil)
+	go historyResetService.RunPurger(historyResetPurgeInterval, nil)
 
 	// Initialize handlers
+	authHandler := auth.NewHandler(authService)
 	wordHandler := words.NewHandler(wordService)
 	groupHandler := groups.NewHandler(groupService)
 	sessionHandler := sessions.NewHandler(sessionService)
 	dashboardHandler := dashboard.NewHandler(dashboardService)
 	activityHandler := activities.NewHandler(activityService)
+	schedulerHandler := schedulers.NewHandler(schedulerService)
+	userHandler := users.NewHandler(userService)
+	adminHandler := admin.NewHandler(resetService, backupService)
+	classHandler := classes.NewHandler(classService)
+	languageHandler := languages.NewHandler(languageService)
+	schemaHandler := schemas.NewHandler(schemaService)
+	conjugationHandler := conjugations.NewHandler(conjugationService)
+	transferHandler := transfer.NewHandler(importService, exportService)
+	resetHandler := resets.NewHandler(historyResetService)
 
 	// API routes
 	api := r.Group("/api")
@@ -61,51 +117,27 @@ func main() {
 			})
 		})
 
-		// Register routes for each handler
-		wordHandler.RegisterRoutes(api)
-		groupHandler.RegisterRoutes(api)
-		sessionHandler.RegisterRoutes(api)
-		dashboardHandler.RegisterRoutes(api)
-		activityHandler.RegisterRoutes(api)
-
-		// Reset endpoints
-		api.POST("/reset_history", func(c *gin.Context) {
-			db := storage.GetDB()
-			_, err := db.Exec(`
-				DELETE FROM word_review_items;
-				DELETE FROM study_sessions;
-			`)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"message": "All study history has been reset",
-				"success": true,
-			})
-		})
+		// Everything except health and login requires a bearer token, and
+		// each route group checks the permissions of the user's role
+		protected := api.Group("", auth.Middleware(authService))
+		study := protected.Group("", auth.Require(service.PermStudy))
 
-		api.POST("/full_reset", func(c *gin.Context) {
-			db := storage.GetDB()
-			_, err := db.Exec(`
-				DELETE FROM word_review_items;
-				DELETE FROM study_sessions;
-				DELETE FROM word_groups;
-				DELETE FROM words;
-				DELETE FROM groups;
-				DELETE FROM study_activities;
-			`)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"message": "System reset complete",
-				"success": true,
-			})
-		})
+		// Register routes for each handler
+		authHandler.RegisterRoutes(api, protected)
+		wordHandler.RegisterRoutes(study)
+		groupHandler.RegisterRoutes(study)
+		sessionHandler.RegisterRoutes(study)
+		dashboardHandler.RegisterRoutes(study)
+		activityHandler.RegisterRoutes(study)
+		schedulerHandler.RegisterRoutes(study)
+		classHandler.RegisterRoutes(study)
+		languageHandler.RegisterRoutes(study)
+		schemaHandler.RegisterRoutes(study)
+		conjugationHandler.RegisterRoutes(study)
+		transferHandler.RegisterRoutes(study)
+		resetHandler.RegisterRoutes(study)
+		userHandler.RegisterRoutes(protected)
+		adminHandler.RegisterRoutes(protected)
 	}
 
 	if err := r.Run(":8080"); err != nil {
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS word_groups_after_update;
+DROP TRIGGER IF EXISTS word_groups_after_delete;
+DROP TRIGGER IF EXISTS word_groups_after_insert;
@@ -0,0 +1,31 @@
+-- Keep groups.words_count in sync with word_groups
+CREATE TRIGGER IF NOT EXISTS word_groups_after_insert
+AFTER INSERT ON word_groups
+BEGIN
+    UPDATE groups
+    SET words_count = (SELECT COUNT(*) FROM word_groups WHERE group_id = NEW.group_id)
+    WHERE id = NEW.group_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS word_groups_after_delete
+AFTER DELETE ON word_groups
+BEGIN
+    UPDATE groups
+    SET words_count = (SELECT COUNT(*) FROM word_groups WHERE group_id = OLD.group_id)
+    WHERE id = OLD.group_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS word_groups_after_update
+AFTER UPDATE OF group_id ON word_groups
+BEGIN
+    UPDATE groups
+    SET words_count = (SELECT COUNT(*) FROM word_groups WHERE group_id = OLD.group_id)
+    WHERE id = OLD.group_id;
+    UPDATE groups
+    SET words_count = (SELECT COUNT(*) FROM word_groups WHERE group_id = NEW.group_id)
+    WHERE id = NEW.group_id;
+END;
+
+-- Repair counts that drifted before the triggers existed
+UPDATE groups
+SET words_count = (SELECT COUNT(*) FROM word_groups WHERE word_groups.group_id = groups.id);
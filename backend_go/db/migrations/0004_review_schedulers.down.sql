@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS settings;
+
+ALTER TABLE study_activities DROP COLUMN scheduler;
+
+ALTER TABLE word_review_states DROP COLUMN lapses;
+ALTER TABLE word_review_states DROP COLUMN difficulty;
+ALTER TABLE word_review_states DROP COLUMN stability;
+ALTER TABLE word_review_states DROP COLUMN box;
+ALTER TABLE word_review_states DROP COLUMN scheduler;
@@ -0,0 +1,17 @@
+-- Scheduler specific state for each word
+ALTER TABLE word_review_states ADD COLUMN scheduler TEXT NOT NULL DEFAULT 'sm2';
+ALTER TABLE word_review_states ADD COLUMN box INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE word_review_states ADD COLUMN stability REAL NOT NULL DEFAULT 0;
+ALTER TABLE word_review_states ADD COLUMN difficulty REAL NOT NULL DEFAULT 0;
+ALTER TABLE word_review_states ADD COLUMN lapses INTEGER NOT NULL DEFAULT 0;
+
+-- Per activity scheduler, NULL uses the global default
+ALTER TABLE study_activities ADD COLUMN scheduler TEXT;
+
+-- Global application settings
+CREATE TABLE IF NOT EXISTS settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+INSERT OR IGNORE INTO settings (key, value) VALUES ('review_scheduler', 'sm2');
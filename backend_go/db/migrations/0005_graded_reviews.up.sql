@@ -0,0 +1,9 @@
+-- Graded review answers; correct stays as quality >= 3 for existing queries
+ALTER TABLE word_review_items ADD COLUMN quality INTEGER;
+ALTER TABLE word_review_items ADD COLUMN answer TEXT;
+ALTER TABLE word_review_items ADD COLUMN response_time_ms INTEGER;
+ALTER TABLE word_review_items ADD COLUMN direction TEXT;
+
+UPDATE word_review_items
+SET quality = CASE WHEN correct THEN 4 ELSE 1 END
+WHERE quality IS NULL;
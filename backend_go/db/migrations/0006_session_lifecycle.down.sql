@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS study_sessions_after_insert;
+
+ALTER TABLE study_sessions DROP COLUMN ended_at;
+ALTER TABLE study_sessions DROP COLUMN last_activity_at;
+ALTER TABLE study_sessions DROP COLUMN resumed_at;
+ALTER TABLE study_sessions DROP COLUMN active_seconds;
+ALTER TABLE study_sessions DROP COLUMN status;
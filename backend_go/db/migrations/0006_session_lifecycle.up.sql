@@ -0,0 +1,34 @@
+-- Explicit study session lifecycle. active_seconds holds the active time
+-- accumulated before resumed_at, the start of the current active stretch.
+ALTER TABLE study_sessions ADD COLUMN status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'paused', 'completed', 'abandoned'));
+ALTER TABLE study_sessions ADD COLUMN active_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE study_sessions ADD COLUMN resumed_at TIMESTAMP;
+ALTER TABLE study_sessions ADD COLUMN last_activity_at TIMESTAMP;
+ALTER TABLE study_sessions ADD COLUMN ended_at TIMESTAMP;
+
+-- New sessions start active from their creation time
+CREATE TRIGGER IF NOT EXISTS study_sessions_after_insert
+AFTER INSERT ON study_sessions
+WHEN NEW.status = 'active' AND NEW.resumed_at IS NULL
+BEGIN
+    UPDATE study_sessions
+    SET resumed_at = NEW.created_at,
+        last_activity_at = COALESCE(NEW.last_activity_at, NEW.created_at)
+    WHERE id = NEW.id;
+END;
+
+-- Sessions recorded before the lifecycle existed ended with their last review
+UPDATE study_sessions
+SET status = 'completed',
+    ended_at = COALESCE(
+        (SELECT MAX(created_at) FROM word_review_items WHERE study_session_id = study_sessions.id),
+        created_at
+    ),
+    last_activity_at = COALESCE(
+        (SELECT MAX(created_at) FROM word_review_items WHERE study_session_id = study_sessions.id),
+        created_at
+    );
+
+UPDATE study_sessions
+SET active_seconds = MAX(0, CAST(strftime('%s', ended_at) AS INTEGER) - CAST(strftime('%s', created_at) AS INTEGER));
@@ -0,0 +1,5 @@
+-- Client generated review ids so that retried submissions are only stored once
+ALTER TABLE word_review_items ADD COLUMN client_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_word_review_items_client_id
+ON word_review_items (study_session_id, client_id);
@@ -0,0 +1 @@
+-- Deleted orphan rows cannot be restored; nothing to revert
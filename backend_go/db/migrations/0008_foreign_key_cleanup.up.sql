@@ -0,0 +1,21 @@
+-- Foreign keys are enforced from now on; remove rows that reference data
+-- deleted while they were not
+DELETE FROM word_review_items
+WHERE (word_id IS NOT NULL AND word_id NOT IN (SELECT id FROM words))
+   OR (study_session_id IS NOT NULL AND study_session_id IN (
+        SELECT id FROM study_sessions
+        WHERE (group_id IS NOT NULL AND group_id NOT IN (SELECT id FROM groups))
+           OR (study_activity_id IS NOT NULL AND study_activity_id NOT IN (SELECT id FROM study_activities))
+      ))
+   OR (study_session_id IS NOT NULL AND study_session_id NOT IN (SELECT id FROM study_sessions));
+
+DELETE FROM study_sessions
+WHERE (group_id IS NOT NULL AND group_id NOT IN (SELECT id FROM groups))
+   OR (study_activity_id IS NOT NULL AND study_activity_id NOT IN (SELECT id FROM study_activities));
+
+DELETE FROM word_review_states
+WHERE word_id NOT IN (SELECT id FROM words);
+
+DELETE FROM word_groups
+WHERE word_id NOT IN (SELECT id FROM words)
+   OR group_id NOT IN (SELECT id FROM groups);
@@ -0,0 +1,75 @@
+-- Keep one schedule per word, preferring unowned ones then the first user's
+CREATE TABLE word_review_states_old (
+    word_id INTEGER PRIMARY KEY,
+    ease_factor REAL NOT NULL DEFAULT 2.5,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    due_at TIMESTAMP NOT NULL,
+    last_reviewed_at TIMESTAMP,
+    scheduler TEXT NOT NULL DEFAULT 'sm2',
+    box INTEGER NOT NULL DEFAULT 0,
+    stability REAL NOT NULL DEFAULT 0,
+    difficulty REAL NOT NULL DEFAULT 0,
+    lapses INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+INSERT OR IGNORE INTO word_review_states_old (
+    word_id, ease_factor, interval_days, repetitions, due_at, last_reviewed_at,
+    scheduler, box, stability, difficulty, lapses
+)
+SELECT
+    word_id, ease_factor, interval_days, repetitions, due_at, last_reviewed_at,
+    scheduler, box, stability, difficulty, lapses
+FROM word_review_states
+ORDER BY user_id IS NOT NULL, user_id;
+
+DROP TABLE word_review_states;
+
+ALTER TABLE word_review_states_old RENAME TO word_review_states;
+
+CREATE INDEX IF NOT EXISTS idx_word_review_states_due_at ON word_review_states (due_at);
+
+-- SQLite cannot drop a column with a foreign key, so rebuild study_sessions
+DROP INDEX IF EXISTS idx_study_sessions_user_id;
+
+CREATE TABLE study_sessions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_id INTEGER,
+    study_activity_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    status TEXT NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'paused', 'completed', 'abandoned')),
+    active_seconds INTEGER NOT NULL DEFAULT 0,
+    resumed_at TIMESTAMP,
+    last_activity_at TIMESTAMP,
+    ended_at TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES groups(id),
+    FOREIGN KEY (study_activity_id) REFERENCES study_activities(id)
+);
+
+INSERT INTO study_sessions_old (
+    id, group_id, study_activity_id, created_at, status, active_seconds,
+    resumed_at, last_activity_at, ended_at
+)
+SELECT
+    id, group_id, study_activity_id, created_at, status, active_seconds,
+    resumed_at, last_activity_at, ended_at
+FROM study_sessions;
+
+DROP TABLE study_sessions;
+
+ALTER TABLE study_sessions_old RENAME TO study_sessions;
+
+CREATE TRIGGER IF NOT EXISTS study_sessions_after_insert
+AFTER INSERT ON study_sessions
+WHEN NEW.status = 'active' AND NEW.resumed_at IS NULL
+BEGIN
+    UPDATE study_sessions
+    SET resumed_at = NEW.created_at,
+        last_activity_at = COALESCE(NEW.last_activity_at, NEW.created_at)
+    WHERE id = NEW.id;
+END;
+
+DROP TABLE IF EXISTS auth_tokens;
+DROP TABLE IF EXISTS users;
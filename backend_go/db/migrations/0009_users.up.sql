@@ -0,0 +1,59 @@
+-- Learner accounts
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    name TEXT NOT NULL DEFAULT '',
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bearer tokens issued at login, stored as SHA-256 hashes
+CREATE TABLE IF NOT EXISTS auth_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_tokens_user_id ON auth_tokens (user_id);
+
+-- Study history belongs to a learner. Existing sessions have no owner until
+-- the first account is registered.
+ALTER TABLE study_sessions ADD COLUMN user_id INTEGER REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_study_sessions_user_id ON study_sessions (user_id);
+
+-- Each learner has their own schedule for a word
+CREATE TABLE word_review_states_new (
+    user_id INTEGER,
+    word_id INTEGER NOT NULL,
+    scheduler TEXT NOT NULL DEFAULT 'sm2',
+    ease_factor REAL NOT NULL DEFAULT 2.5,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    lapses INTEGER NOT NULL DEFAULT 0,
+    box INTEGER NOT NULL DEFAULT 0,
+    stability REAL NOT NULL DEFAULT 0,
+    difficulty REAL NOT NULL DEFAULT 0,
+    due_at TIMESTAMP NOT NULL,
+    last_reviewed_at TIMESTAMP,
+    UNIQUE (user_id, word_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+INSERT INTO word_review_states_new (
+    user_id, word_id, scheduler, ease_factor, interval_days, repetitions,
+    lapses, box, stability, difficulty, due_at, last_reviewed_at
+)
+SELECT
+    NULL, word_id, scheduler, ease_factor, interval_days, repetitions,
+    lapses, box, stability, difficulty, due_at, last_reviewed_at
+FROM word_review_states;
+
+DROP TABLE word_review_states;
+
+ALTER TABLE word_review_states_new RENAME TO word_review_states;
+
+CREATE INDEX IF NOT EXISTS idx_word_review_states_due_at ON word_review_states (user_id, due_at);
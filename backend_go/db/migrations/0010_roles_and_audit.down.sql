@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS confirmation_tokens;
+
+ALTER TABLE users DROP COLUMN role;
@@ -0,0 +1,27 @@
+-- Roles decide what a user may do; the first account administers the portal
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'learner'
+    CHECK (role IN ('admin', 'teacher', 'learner'));
+
+UPDATE users SET role = 'admin' WHERE id = (SELECT MIN(id) FROM users);
+
+-- Single-use tokens that confirm a destructive action, stored as SHA-256 hashes
+CREATE TABLE IF NOT EXISTS confirmation_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Who did what to the data
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS class_assignments;
+DROP TABLE IF EXISTS class_members;
+DROP TABLE IF EXISTS classes;
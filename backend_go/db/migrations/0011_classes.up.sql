@@ -0,0 +1,38 @@
+-- Classes run by a teacher
+CREATE TABLE IF NOT EXISTS classes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    teacher_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (teacher_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_classes_teacher_id ON classes (teacher_id);
+
+-- Learners enrolled in a class
+CREATE TABLE IF NOT EXISTS class_members (
+    class_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    enrolled_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (class_id, user_id),
+    FOREIGN KEY (class_id) REFERENCES classes(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_class_members_user_id ON class_members (user_id);
+
+-- A group to study with an activity by a due date. Sessions of the group and
+-- activity started after the assignment was made count towards it.
+CREATE TABLE IF NOT EXISTS class_assignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    class_id INTEGER NOT NULL,
+    group_id INTEGER NOT NULL,
+    study_activity_id INTEGER NOT NULL,
+    due_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (class_id) REFERENCES classes(id),
+    FOREIGN KEY (group_id) REFERENCES groups(id),
+    FOREIGN KEY (study_activity_id) REFERENCES study_activities(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_class_assignments_class_id ON class_assignments (class_id);
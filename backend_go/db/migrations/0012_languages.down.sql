@@ -0,0 +1,61 @@
+-- SQLite cannot drop a column with a foreign key, so rebuild words and
+-- groups. The words_count triggers name groups and would break the rename.
+DROP INDEX IF EXISTS idx_words_language_pair;
+DROP INDEX IF EXISTS idx_groups_language_pair;
+
+DROP TRIGGER IF EXISTS word_groups_after_insert;
+DROP TRIGGER IF EXISTS word_groups_after_delete;
+DROP TRIGGER IF EXISTS word_groups_after_update;
+
+CREATE TABLE words_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    parts JSON NOT NULL
+);
+
+INSERT INTO words_old (id, parts) SELECT id, parts FROM words;
+
+DROP TABLE words;
+
+ALTER TABLE words_old RENAME TO words;
+
+CREATE TABLE groups_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    words_count INTEGER DEFAULT 0
+);
+
+INSERT INTO groups_old (id, name, words_count) SELECT id, name, words_count FROM groups;
+
+DROP TABLE groups;
+
+ALTER TABLE groups_old RENAME TO groups;
+
+CREATE TRIGGER IF NOT EXISTS word_groups_after_insert
+AFTER INSERT ON word_groups
+BEGIN
+    UPDATE groups
+    SET words_count = (SELECT COUNT(*) FROM word_groups WHERE group_id = NEW.group_id)
+    WHERE id = NEW.group_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS word_groups_after_delete
+AFTER DELETE ON word_groups
+BEGIN
+    UPDATE groups
+    SET words_count = (SELECT COUNT(*) FROM word_groups WHERE group_id = OLD.group_id)
+    WHERE id = OLD.group_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS word_groups_after_update
+AFTER UPDATE OF group_id ON word_groups
+BEGIN
+    UPDATE groups
+    SET words_count = (SELECT COUNT(*) FROM word_groups WHERE group_id = OLD.group_id)
+    WHERE id = OLD.group_id;
+    UPDATE groups
+    SET words_count = (SELECT COUNT(*) FROM word_groups WHERE group_id = NEW.group_id)
+    WHERE id = NEW.group_id;
+END;
+
+DROP TABLE IF EXISTS language_fields;
+DROP TABLE IF EXISTS languages;
@@ -0,0 +1,41 @@
+-- Languages words can be written in
+CREATE TABLE IF NOT EXISTS languages (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+-- The parts a word has in each language. A word's parts combine the fields
+-- of its source and target language. allowed_values is a JSON array limiting
+-- the field to a fixed set, or NULL for free text.
+CREATE TABLE IF NOT EXISTS language_fields (
+    language_code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT 0,
+    allowed_values JSON,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (language_code, name),
+    FOREIGN KEY (language_code) REFERENCES languages(code)
+);
+
+INSERT OR IGNORE INTO languages (code, name) VALUES
+    ('en', 'English'),
+    ('fr', 'French'),
+    ('ja', 'Japanese');
+
+INSERT OR IGNORE INTO language_fields (language_code, name, required, allowed_values, position) VALUES
+    ('en', 'english', 1, NULL, 0),
+    ('fr', 'french', 1, NULL, 0),
+    ('fr', 'gender', 0, '["masculine","feminine"]', 1),
+    ('ja', 'kanji', 1, NULL, 0),
+    ('ja', 'reading', 0, NULL, 1),
+    ('ja', 'romaji', 1, NULL, 2);
+
+-- Every word and group so far is French to English
+ALTER TABLE words ADD COLUMN source_language TEXT NOT NULL DEFAULT 'fr' REFERENCES languages(code);
+ALTER TABLE words ADD COLUMN target_language TEXT NOT NULL DEFAULT 'en' REFERENCES languages(code);
+
+ALTER TABLE groups ADD COLUMN source_language TEXT NOT NULL DEFAULT 'fr' REFERENCES languages(code);
+ALTER TABLE groups ADD COLUMN target_language TEXT NOT NULL DEFAULT 'en' REFERENCES languages(code);
+
+CREATE INDEX IF NOT EXISTS idx_words_language_pair ON words (source_language, target_language);
+CREATE INDEX IF NOT EXISTS idx_groups_language_pair ON groups (source_language, target_language);
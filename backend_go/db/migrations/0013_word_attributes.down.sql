@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_words_part_of_speech;
+DROP INDEX IF EXISTS idx_words_gender;
+
+INSERT OR IGNORE INTO language_fields (language_code, name, required, allowed_values, position)
+VALUES ('fr', 'gender', 0, '["masculine","feminine"]', 1);
+
+UPDATE words
+SET parts = json_set(parts, '$.gender', gender)
+WHERE source_language = 'fr' AND gender IN ('masculine', 'feminine');
+
+ALTER TABLE words DROP COLUMN notes;
+ALTER TABLE words DROP COLUMN register;
+ALTER TABLE words DROP COLUMN ipa;
+ALTER TABLE words DROP COLUMN plural;
+ALTER TABLE words DROP COLUMN gender;
+ALTER TABLE words DROP COLUMN part_of_speech;
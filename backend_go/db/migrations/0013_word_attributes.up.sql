@@ -0,0 +1,22 @@
+-- Lexical attributes of words. They apply to every language, so they are
+-- columns rather than language fields. NULL means unknown.
+ALTER TABLE words ADD COLUMN part_of_speech TEXT
+    CHECK (part_of_speech IN ('noun', 'verb', 'adjective', 'adverb', 'pronoun', 'determiner', 'preposition', 'conjunction', 'interjection', 'phrase'));
+ALTER TABLE words ADD COLUMN gender TEXT
+    CHECK (gender IN ('masculine', 'feminine', 'neuter', 'common'));
+ALTER TABLE words ADD COLUMN plural TEXT;
+ALTER TABLE words ADD COLUMN ipa TEXT;
+ALTER TABLE words ADD COLUMN register TEXT
+    CHECK (register IN ('formal', 'neutral', 'informal', 'slang', 'vulgar'));
+ALTER TABLE words ADD COLUMN notes TEXT;
+
+-- Gender was a French language field; move it out of the parts
+UPDATE words
+SET gender = json_extract(parts, '$.gender'),
+    parts = json_remove(parts, '$.gender')
+WHERE json_extract(parts, '$.gender') IN ('masculine', 'feminine');
+
+DELETE FROM language_fields WHERE language_code = 'fr' AND name = 'gender';
+
+CREATE INDEX IF NOT EXISTS idx_words_part_of_speech ON words (part_of_speech);
+CREATE INDEX IF NOT EXISTS idx_words_gender ON words (gender);
@@ -0,0 +1,2 @@
+ALTER TABLE word_review_items DROP COLUMN person;
+ALTER TABLE word_review_items DROP COLUMN tense;
@@ -0,0 +1,4 @@
+-- The tense and person practiced by conjugation drill reviews, NULL for
+-- reviews of the word itself
+ALTER TABLE word_review_items ADD COLUMN tense TEXT;
+ALTER TABLE word_review_items ADD COLUMN person TEXT;
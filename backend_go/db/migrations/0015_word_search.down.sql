@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS words_search_after_field_delete;
+DROP TRIGGER IF EXISTS words_search_after_field_insert;
+DROP TRIGGER IF EXISTS words_search_after_delete;
+DROP TRIGGER IF EXISTS words_search_after_update;
+DROP TRIGGER IF EXISTS words_search_after_insert;
+DROP TABLE IF EXISTS words_search;
+DROP VIEW IF EXISTS word_search_text;
@@ -0,0 +1,78 @@
+-- The text of each word's parts split by side: source_text holds the parts
+-- that are fields of the word's source language, target_text all others.
+-- Values are separated by " | " so search results can be ranked per part.
+CREATE VIEW IF NOT EXISTS word_search_text AS
+SELECT
+    w.id,
+    (
+        SELECT group_concat(p.value, ' | ')
+        FROM json_each(w.parts) p
+        WHERE p.key IN (SELECT name FROM language_fields WHERE language_code = w.source_language)
+    ) AS source_text,
+    (
+        SELECT group_concat(p.value, ' | ')
+        FROM json_each(w.parts) p
+        WHERE p.key NOT IN (SELECT name FROM language_fields WHERE language_code = w.source_language)
+    ) AS target_text
+FROM words w;
+
+-- Full-text index of word parts, keyed by word id. The unicode61 tokenizer
+-- folds case and removes diacritics, so etre matches être. FTS5 is not
+-- compiled into the default go-sqlite3 build, so the index uses FTS4.
+CREATE VIRTUAL TABLE IF NOT EXISTS words_search USING fts4(
+    source_text,
+    target_text,
+    tokenize=unicode61 "remove_diacritics=2",
+    prefix="2,3"
+);
+
+INSERT INTO words_search (docid, source_text, target_text)
+SELECT id, source_text, target_text FROM word_search_text;
+
+-- Keep the index in sync with words
+CREATE TRIGGER IF NOT EXISTS words_search_after_insert
+AFTER INSERT ON words
+BEGIN
+    INSERT INTO words_search (docid, source_text, target_text)
+    SELECT id, source_text, target_text FROM word_search_text WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_search_after_update
+AFTER UPDATE OF parts, source_language, target_language ON words
+BEGIN
+    DELETE FROM words_search WHERE docid = OLD.id;
+    INSERT INTO words_search (docid, source_text, target_text)
+    SELECT id, source_text, target_text FROM word_search_text WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_search_after_delete
+AFTER DELETE ON words
+BEGIN
+    DELETE FROM words_search WHERE docid = OLD.id;
+END;
+
+-- A word's parts move between sides when the fields of its source language
+-- change
+CREATE TRIGGER IF NOT EXISTS words_search_after_field_insert
+AFTER INSERT ON language_fields
+BEGIN
+    DELETE FROM words_search
+    WHERE docid IN (SELECT id FROM words WHERE source_language = NEW.language_code);
+    INSERT INTO words_search (docid, source_text, target_text)
+    SELECT t.id, t.source_text, t.target_text
+    FROM word_search_text t
+    JOIN words w ON w.id = t.id
+    WHERE w.source_language = NEW.language_code;
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_search_after_field_delete
+AFTER DELETE ON language_fields
+BEGIN
+    DELETE FROM words_search
+    WHERE docid IN (SELECT id FROM words WHERE source_language = OLD.language_code);
+    INSERT INTO words_search (docid, source_text, target_text)
+    SELECT t.id, t.source_text, t.target_text
+    FROM word_search_text t
+    JOIN words w ON w.id = t.id
+    WHERE w.source_language = OLD.language_code;
+END;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS archived_word_review_items;
+DROP TABLE IF EXISTS archived_study_sessions;
+DROP TABLE IF EXISTS history_resets;
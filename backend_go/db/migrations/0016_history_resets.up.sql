@@ -0,0 +1,57 @@
+-- Resets of a user's study history. The sessions a reset covers and their
+-- reviews move to the archive tables until the reset is undone, or until
+-- expires_at when they are purged. group_id, study_activity_id, from_time
+-- and to_time record the scope of the reset; NULL matches everything.
+CREATE TABLE IF NOT EXISTS history_resets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    group_id INTEGER,
+    study_activity_id INTEGER,
+    from_time TIMESTAMP,
+    to_time TIMESTAMP,
+    sessions_count INTEGER NOT NULL DEFAULT 0,
+    reviews_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    restored_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_history_resets_user_id ON history_resets (user_id);
+CREATE INDEX IF NOT EXISTS idx_history_resets_expires_at ON history_resets (expires_at);
+
+-- Archived rows keep their ids so undoing a reset puts them back as they
+-- were. They go with the reset, and with the word, group or activity they
+-- belong to when that is deleted.
+CREATE TABLE IF NOT EXISTS archived_study_sessions (
+    id INTEGER PRIMARY KEY,
+    reset_id INTEGER NOT NULL REFERENCES history_resets(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    group_id INTEGER REFERENCES groups(id) ON DELETE CASCADE,
+    study_activity_id INTEGER REFERENCES study_activities(id) ON DELETE CASCADE,
+    created_at TIMESTAMP,
+    status TEXT NOT NULL,
+    active_seconds INTEGER NOT NULL,
+    resumed_at TIMESTAMP,
+    last_activity_at TIMESTAMP,
+    ended_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_archived_study_sessions_reset_id ON archived_study_sessions (reset_id);
+
+CREATE TABLE IF NOT EXISTS archived_word_review_items (
+    id INTEGER PRIMARY KEY,
+    word_id INTEGER REFERENCES words(id) ON DELETE CASCADE,
+    study_session_id INTEGER REFERENCES archived_study_sessions(id) ON DELETE CASCADE,
+    correct BOOLEAN NOT NULL,
+    created_at TIMESTAMP,
+    quality INTEGER,
+    answer TEXT,
+    response_time_ms INTEGER,
+    direction TEXT,
+    client_id TEXT,
+    tense TEXT,
+    person TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_archived_word_review_items_session_id ON archived_word_review_items (study_session_id);
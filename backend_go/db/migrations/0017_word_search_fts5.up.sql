@@ -0,0 +1,68 @@
+-- Rebuild the full-text index of word parts with FTS5, keyed by rowid, so
+-- searches are ranked with bm25(). FTS5 needs the sqlite_fts5 build tag.
+DROP TRIGGER IF EXISTS words_search_after_field_delete;
+DROP TRIGGER IF EXISTS words_search_after_field_insert;
+DROP TRIGGER IF EXISTS words_search_after_delete;
+DROP TRIGGER IF EXISTS words_search_after_update;
+DROP TRIGGER IF EXISTS words_search_after_insert;
+DROP TABLE IF EXISTS words_search;
+
+-- The unicode61 tokenizer folds case and removes diacritics, so etre
+-- matches être
+CREATE VIRTUAL TABLE words_search USING fts5(
+    source_text,
+    target_text,
+    tokenize="unicode61 remove_diacritics 2",
+    prefix='2 3'
+);
+
+INSERT INTO words_search (rowid, source_text, target_text)
+SELECT id, source_text, target_text FROM word_search_text;
+
+-- Keep the index in sync with words
+CREATE TRIGGER words_search_after_insert
+AFTER INSERT ON words
+BEGIN
+    INSERT INTO words_search (rowid, source_text, target_text)
+    SELECT id, source_text, target_text FROM word_search_text WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER words_search_after_update
+AFTER UPDATE OF parts, source_language, target_language ON words
+BEGIN
+    DELETE FROM words_search WHERE rowid = OLD.id;
+    INSERT INTO words_search (rowid, source_text, target_text)
+    SELECT id, source_text, target_text FROM word_search_text WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER words_search_after_delete
+AFTER DELETE ON words
+BEGIN
+    DELETE FROM words_search WHERE rowid = OLD.id;
+END;
+
+-- A word's parts move between sides when the fields of its source language
+-- change
+CREATE TRIGGER words_search_after_field_insert
+AFTER INSERT ON language_fields
+BEGIN
+    DELETE FROM words_search
+    WHERE rowid IN (SELECT id FROM words WHERE source_language = NEW.language_code);
+    INSERT INTO words_search (rowid, source_text, target_text)
+    SELECT t.id, t.source_text, t.target_text
+    FROM word_search_text t
+    JOIN words w ON w.id = t.id
+    WHERE w.source_language = NEW.language_code;
+END;
+
+CREATE TRIGGER words_search_after_field_delete
+AFTER DELETE ON language_fields
+BEGIN
+    DELETE FROM words_search
+    WHERE rowid IN (SELECT id FROM words WHERE source_language = OLD.language_code);
+    INSERT INTO words_search (rowid, source_text, target_text)
+    SELECT t.id, t.source_text, t.target_text
+    FROM word_search_text t
+    JOIN words w ON w.id = t.id
+    WHERE w.source_language = OLD.language_code;
+END;
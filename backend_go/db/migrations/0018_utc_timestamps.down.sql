@@ -0,0 +1,2 @@
+-- The local offsets the timestamps were written with are not kept; nothing to
+-- revert
@@ -0,0 +1,43 @@
+-- Sessions and reviews used to be written with the server's local time and
+-- its offset, such as 2025-01-01 10:00:00.123+02:00. Convert them to UTC like
+-- every other timestamp so they compare correctly as text.
+
+UPDATE study_sessions
+SET created_at = strftime('%Y-%m-%d %H:%M:%f+00:00', created_at)
+WHERE substr(created_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(created_at, -6) != '+00:00';
+
+UPDATE study_sessions
+SET resumed_at = strftime('%Y-%m-%d %H:%M:%f+00:00', resumed_at)
+WHERE substr(resumed_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(resumed_at, -6) != '+00:00';
+
+UPDATE study_sessions
+SET last_activity_at = strftime('%Y-%m-%d %H:%M:%f+00:00', last_activity_at)
+WHERE substr(last_activity_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(last_activity_at, -6) != '+00:00';
+
+UPDATE study_sessions
+SET ended_at = strftime('%Y-%m-%d %H:%M:%f+00:00', ended_at)
+WHERE substr(ended_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(ended_at, -6) != '+00:00';
+
+UPDATE word_review_items
+SET created_at = strftime('%Y-%m-%d %H:%M:%f+00:00', created_at)
+WHERE substr(created_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(created_at, -6) != '+00:00';
+
+UPDATE archived_study_sessions
+SET created_at = strftime('%Y-%m-%d %H:%M:%f+00:00', created_at)
+WHERE substr(created_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(created_at, -6) != '+00:00';
+
+UPDATE archived_study_sessions
+SET resumed_at = strftime('%Y-%m-%d %H:%M:%f+00:00', resumed_at)
+WHERE substr(resumed_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(resumed_at, -6) != '+00:00';
+
+UPDATE archived_study_sessions
+SET last_activity_at = strftime('%Y-%m-%d %H:%M:%f+00:00', last_activity_at)
+WHERE substr(last_activity_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(last_activity_at, -6) != '+00:00';
+
+UPDATE archived_study_sessions
+SET ended_at = strftime('%Y-%m-%d %H:%M:%f+00:00', ended_at)
+WHERE substr(ended_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(ended_at, -6) != '+00:00';
+
+UPDATE archived_word_review_items
+SET created_at = strftime('%Y-%m-%d %H:%M:%f+00:00', created_at)
+WHERE substr(created_at, -6) GLOB '[+-][0-9][0-9]:[0-9][0-9]' AND substr(created_at, -6) != '+00:00';
@@ -0,0 +1,395 @@
+// Package anki reads and writes Anki deck packages (.apkg). A package is a
+// zip archive holding a collection, an SQLite database in the schema 11
+// layout, and a media index. Only the notes, cards, decks, note types and
+// review log of the collection are read; media files are ignored.
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// The files of a package holding the collection. Packages written by Anki
+// 2.1 hold the collection in collection.anki21 and a placeholder asking to
+// upgrade in collection.anki2. collection.anki21b is compressed with zstd
+// and not supported.
+const (
+	legacyCollection     = "collection.anki2"
+	collection21         = "collection.anki21"
+	compressedCollection = "collection.anki21b"
+)
+
+// MaxCollectionSize bounds the size of a package's collection once
+// uncompressed, so a small package can't expand to fill the disk
+var MaxCollectionSize int64 = 256 << 20
+
+// fieldSeparator separates the fields of a note
+const fieldSeparator = "\x1f"
+
+// The types and queues of cards
+const (
+	CardNew      = 0
+	CardLearning = 1
+	CardReview   = 2
+
+	QueueNew      = 0
+	QueueLearning = 1
+	QueueReview   = 2
+)
+
+// The review log types
+const (
+	ReviewLearning = 0
+	ReviewReview   = 1
+	ReviewRelearn  = 2
+	ReviewCram     = 3
+	ReviewManual   = 4
+)
+
+// Day is the length of the days card due dates count
+const Day = 24 * time.Hour
+
+// FormatError reports a file that is not a package this package can read
+type FormatError struct {
+	Message string
+}
+
+func (e *FormatError) Error() string {
+	return e.Message
+}
+
+// Model is a note type: the fields of its notes and the cards made of them
+type Model struct {
+	ID        int64
+	Name      string
+	Fields    []string
+	Templates []Template
+}
+
+// Template makes a card of a note. Front and Back are Anki templates such as
+// {{Front}}.
+type Template struct {
+	Name  string
+	Front string
+	Back  string
+}
+
+type Deck struct {
+	ID   int64
+	Name string
+}
+
+// Note holds the fields of a word, in the order of its model's fields.
+// Fields are HTML.
+type Note struct {
+	ID      int64
+	GUID    string
+	ModelID int64
+	Fields  []string
+	Tags    []string
+}
+
+// Card is a note studied in a deck. Due counts days from the collection's
+// creation for review cards, seconds since the epoch for learning cards and
+// is the position of new cards. Factor is the ease in permille.
+type Card struct {
+	ID       int64
+	NoteID   int64
+	DeckID   int64
+	Ord      int
+	Type     int
+	Queue    int
+	Due      int64
+	Interval int
+	Factor   int
+	Reps     int
+	Lapses   int
+}
+
+// Review is an answer to a card. ID is the time of the review in
+// milliseconds since the epoch. Ease is the button pressed, from 1 (again)
+// to 4 (easy), or 0 for cards rescheduled by hand. Time is the time taken to
+// answer in milliseconds.
+type Review struct {
+	ID           int64
+	CardID       int64
+	Ease         int
+	Interval     int
+	LastInterval int
+	Factor       int
+	Time         int
+	Type         int
+}
+
+// Collection is the content of a package
+type Collection struct {
+	// Created is the start of the day card due dates count from
+	Created time.Time
+	Models  map[int64]Model
+	Decks   map[int64]Deck
+	Notes   []Note
+	Cards   []Card
+	Reviews []Review
+}
+
+// ReviewTime returns the time of a review
+func (r Review) ReviewTime() time.Time {
+	return time.UnixMilli(r.ID)
+}
+
+// Read returns the collection of a package
+func Read(data []byte) (*Collection, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, &FormatError{Message: "the file is not an Anki package: " + err.Error()}
+	}
+
+	files := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	file := files[collection21]
+	if file == nil {
+		file = files[legacyCollection]
+	}
+	if file == nil {
+		if files[compressedCollection] != nil {
+			return nil, &FormatError{Message: "packages in the latest Anki format are not supported; export with support for older Anki versions"}
+		}
+		return nil, &FormatError{Message: "the package has no collection"}
+	}
+
+	// SQLite only opens files, so the collection is copied out of the zip
+	path, err := extract(file)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	collection, err := readCollection(db)
+	if err != nil {
+		return nil, &FormatError{Message: "the package's collection cannot be read: " + err.Error()}
+	}
+	return collection, nil
+}
+
+func extract(file *zip.File) (string, error) {
+	tooLarge := &FormatError{Message: fmt.Sprintf("the package's collection is larger than %d MB", MaxCollectionSize>>20)}
+	if file.UncompressedSize64 > uint64(MaxCollectionSize) {
+		return "", tooLarge
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", &FormatError{Message: "the package's collection cannot be read: " + err.Error()}
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", "anki-*.sqlite")
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	// The size in the zip header is not trusted: one byte more than the
+	// limit is copied to tell a collection at the limit from a larger one
+	n, err := io.Copy(dst, io.LimitReader(src, MaxCollectionSize+1))
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", &FormatError{Message: "the package's collection cannot be read: " + err.Error()}
+	}
+	if n > MaxCollectionSize {
+		os.Remove(dst.Name())
+		return "", tooLarge
+	}
+	return dst.Name(), nil
+}
+
+// modelJSON and deckJSON are the parts of the col table's models and decks
+// that are read
+type modelJSON struct {
+	Name   string `json:"name"`
+	Fields []struct {
+		Name string `json:"name"`
+		Ord  int    `json:"ord"`
+	} `json:"flds"`
+	Templates []struct {
+		Name  string `json:"name"`
+		Ord   int    `json:"ord"`
+		Front string `json:"qfmt"`
+		Back  string `json:"afmt"`
+	} `json:"tmpls"`
+}
+
+type deckJSON struct {
+	Name string `json:"name"`
+}
+
+func readCollection(db *sql.DB) (*Collection, error) {
+	var created int64
+	var models, decks string
+	if err := db.QueryRow("SELECT crt, models, decks FROM col").Scan(&created, &models, &decks); err != nil {
+		return nil, err
+	}
+
+	collection := &Collection{
+		Created: time.Unix(created, 0),
+		Models:  make(map[int64]Model),
+		Decks:   make(map[int64]Deck),
+	}
+
+	var modelsByID map[string]modelJSON
+	if err := json.Unmarshal([]byte(models), &modelsByID); err != nil {
+		return nil, err
+	}
+	for key, m := range modelsByID {
+		id, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(m.Fields, func(i, j int) bool { return m.Fields[i].Ord < m.Fields[j].Ord })
+		sort.Slice(m.Templates, func(i, j int) bool { return m.Templates[i].Ord < m.Templates[j].Ord })
+
+		model := Model{ID: id, Name: m.Name}
+		for _, field := range m.Fields {
+			model.Fields = append(model.Fields, field.Name)
+		}
+		for _, template := range m.Templates {
+			model.Templates = append(model.Templates, Template{Name: template.Name, Front: template.Front, Back: template.Back})
+		}
+		collection.Models[id] = model
+	}
+
+	var decksByID map[string]deckJSON
+	if err := json.Unmarshal([]byte(decks), &decksByID); err != nil {
+		return nil, err
+	}
+	for key, d := range decksByID {
+		id, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		collection.Decks[id] = Deck{ID: id, Name: d.Name}
+	}
+
+	if err := readNotes(db, collection); err != nil {
+		return nil, err
+	}
+	if err := readCards(db, collection); err != nil {
+		return nil, err
+	}
+	if err := readReviews(db, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func readNotes(db *sql.DB, collection *Collection) error {
+	rows, err := db.Query("SELECT id, guid, mid, tags, flds FROM notes ORDER BY id")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note Note
+		var tags, fields string
+		if err := rows.Scan(&note.ID, &note.GUID, &note.ModelID, &tags, &fields); err != nil {
+			return err
+		}
+		if len(strings.Fields(tags)) > 0 {
+			note.Tags = strings.Fields(tags)
+		}
+		note.Fields = strings.Split(fields, fieldSeparator)
+		collection.Notes = append(collection.Notes, note)
+	}
+	return rows.Err()
+}
+
+func readCards(db *sql.DB, collection *Collection) error {
+	rows, err := db.Query(`
+		SELECT id, nid, did, ord, type, queue, due, ivl, factor, reps, lapses
+		FROM cards
+		ORDER BY id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var card Card
+		err := rows.Scan(
+			&card.ID, &card.NoteID, &card.DeckID, &card.Ord, &card.Type, &card.Queue,
+			&card.Due, &card.Interval, &card.Factor, &card.Reps, &card.Lapses,
+		)
+		if err != nil {
+			return err
+		}
+		collection.Cards = append(collection.Cards, card)
+	}
+	return rows.Err()
+}
+
+func readReviews(db *sql.DB, collection *Collection) error {
+	rows, err := db.Query(`
+		SELECT id, cid, ease, ivl, lastIvl, factor, time, type
+		FROM revlog
+		ORDER BY id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review Review
+		err := rows.Scan(
+			&review.ID, &review.CardID, &review.Ease, &review.Interval, &review.LastInterval,
+			&review.Factor, &review.Time, &review.Type,
+		)
+		if err != nil {
+			return err
+		}
+		collection.Reviews = append(collection.Reviews, review)
+	}
+	return rows.Err()
+}
+
+var (
+	lineBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</?(div|p|li)[^>]*>`)
+	tags       = regexp.MustCompile(`<[^>]*>`)
+	sounds     = regexp.MustCompile(`\[sound:[^\]]*\]`)
+)
+
+// Text returns the text of a field, without its HTML markup and sounds
+func Text(field string) string {
+	text := lineBreaks.ReplaceAllString(field, " ")
+	text = tags.ReplaceAllString(text, "")
+	text = sounds.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+// HTML returns text as the HTML of a field
+func HTML(text string) string {
+	return html.EscapeString(text)
+}
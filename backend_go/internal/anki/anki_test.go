@@ -0,0 +1,130 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	collection := &Collection{
+		Created: created,
+		Models: map[int64]Model{
+			1700000000000: {
+				ID:        1700000000000,
+				Name:      "French to English",
+				Fields:    []string{"french", "english"},
+				Templates: []Template{{Name: "Card 1", Front: "{{french}}", Back: "{{FrontSide}}<hr id=answer>{{english}}"}},
+			},
+		},
+		Decks: map[int64]Deck{1700000000001: {ID: 1700000000001, Name: "Animals"}},
+		Notes: []Note{
+			{ID: 1, GUID: "lp-1", ModelID: 1700000000000, Fields: []string{"chat", "cat"}, Tags: []string{"animal"}},
+			{ID: 2, GUID: "lp-2", ModelID: 1700000000000, Fields: []string{"chien", "dog"}},
+		},
+		Cards: []Card{
+			{ID: 10, NoteID: 1, DeckID: 1700000000001, Type: CardReview, Queue: QueueReview, Due: 30, Interval: 6, Factor: 2500, Reps: 2},
+			{ID: 11, NoteID: 2, DeckID: 1700000000001, Type: CardNew, Queue: QueueNew, Due: 1},
+		},
+		Reviews: []Review{
+			{ID: created.Add(24 * time.Hour).UnixMilli(), CardID: 10, Ease: 3, Interval: 1, Factor: 2500, Time: 2000, Type: ReviewLearning},
+		},
+	}
+
+	data, err := collection.Write()
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	read, err := Read(data)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !read.Created.Equal(created) {
+		t.Errorf("Expected the collection to be created %v, got %v", created, read.Created)
+	}
+	if !reflect.DeepEqual(read.Models, collection.Models) {
+		t.Errorf("Expected models %+v, got %+v", collection.Models, read.Models)
+	}
+	if read.Decks[1700000000001].Name != "Animals" || read.Decks[defaultDeckID].Name != "Default" {
+		t.Errorf("Expected the Animals and Default decks, got %+v", read.Decks)
+	}
+	if !reflect.DeepEqual(read.Notes, collection.Notes) {
+		t.Errorf("Expected notes %+v, got %+v", collection.Notes, read.Notes)
+	}
+	if !reflect.DeepEqual(read.Cards, collection.Cards) {
+		t.Errorf("Expected cards %+v, got %+v", collection.Cards, read.Cards)
+	}
+	if !reflect.DeepEqual(read.Reviews, collection.Reviews) {
+		t.Errorf("Expected reviews %+v, got %+v", collection.Reviews, read.Reviews)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	archive.Create("collection.anki21b")
+	archive.Close()
+
+	for name, data := range map[string][]byte{
+		"not a zip":     []byte("french,english"),
+		"no collection": emptyZip(t),
+		"compressed":    buf.Bytes(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Read(data)
+			if _, ok := err.(*FormatError); !ok {
+				t.Errorf("Expected a FormatError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReadTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	file, err := archive.Create("collection.anki21")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write(make([]byte, 2048))
+	archive.Close()
+
+	defer func(max int64) { MaxCollectionSize = max }(MaxCollectionSize)
+	MaxCollectionSize = 1024
+
+	_, err = Read(buf.Bytes())
+	if err, ok := err.(*FormatError); !ok || !strings.Contains(err.Message, "larger than") {
+		t.Errorf("Expected a FormatError for the collection's size, got %v", err)
+	}
+}
+
+func emptyZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := zip.NewWriter(&buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"chat", "chat"},
+		{"<b>le</b> chat<br>the cat", "le chat the cat"},
+		{"<div>être</div><div>to be</div>", "être to be"},
+		{"rock &amp; roll&nbsp;[sound:rock.mp3]", "rock & roll"},
+	}
+
+	for _, tt := range tests {
+		if got := Text(tt.field); got != tt.want {
+			t.Errorf("Text(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
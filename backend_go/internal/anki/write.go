@@ -0,0 +1,327 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schema creates the tables of a schema 11 collection
+const schema = `
+CREATE TABLE col (
+    id INTEGER PRIMARY KEY,
+    crt INTEGER NOT NULL,
+    mod INTEGER NOT NULL,
+    scm INTEGER NOT NULL,
+    ver INTEGER NOT NULL,
+    dty INTEGER NOT NULL,
+    usn INTEGER NOT NULL,
+    ls INTEGER NOT NULL,
+    conf TEXT NOT NULL,
+    models TEXT NOT NULL,
+    decks TEXT NOT NULL,
+    dconf TEXT NOT NULL,
+    tags TEXT NOT NULL
+);
+CREATE TABLE notes (
+    id INTEGER PRIMARY KEY,
+    guid TEXT NOT NULL,
+    mid INTEGER NOT NULL,
+    mod INTEGER NOT NULL,
+    usn INTEGER NOT NULL,
+    tags TEXT NOT NULL,
+    flds TEXT NOT NULL,
+    sfld INTEGER NOT NULL,
+    csum INTEGER NOT NULL,
+    flags INTEGER NOT NULL,
+    data TEXT NOT NULL
+);
+CREATE TABLE cards (
+    id INTEGER PRIMARY KEY,
+    nid INTEGER NOT NULL,
+    did INTEGER NOT NULL,
+    ord INTEGER NOT NULL,
+    mod INTEGER NOT NULL,
+    usn INTEGER NOT NULL,
+    type INTEGER NOT NULL,
+    queue INTEGER NOT NULL,
+    due INTEGER NOT NULL,
+    ivl INTEGER NOT NULL,
+    factor INTEGER NOT NULL,
+    reps INTEGER NOT NULL,
+    lapses INTEGER NOT NULL,
+    left INTEGER NOT NULL,
+    odue INTEGER NOT NULL,
+    odid INTEGER NOT NULL,
+    flags INTEGER NOT NULL,
+    data TEXT NOT NULL
+);
+CREATE TABLE revlog (
+    id INTEGER PRIMARY KEY,
+    cid INTEGER NOT NULL,
+    usn INTEGER NOT NULL,
+    ease INTEGER NOT NULL,
+    ivl INTEGER NOT NULL,
+    lastIvl INTEGER NOT NULL,
+    factor INTEGER NOT NULL,
+    time INTEGER NOT NULL,
+    type INTEGER NOT NULL
+);
+CREATE TABLE graves (
+    usn INTEGER NOT NULL,
+    oid INTEGER NOT NULL,
+    type INTEGER NOT NULL
+);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+// defaultDeckID is the deck every collection has
+const defaultDeckID = 1
+
+// The collection and deck options of new collections, as Anki writes them
+const (
+	collectionConf = `{"activeDecks":[1],"curDeck":1,"newSpread":0,"collapseTime":1200,"timeLim":0,` +
+		`"estTimes":true,"dueCounts":true,"curModel":null,"nextPos":1,"sortType":"noteFld","sortBackwards":false,"addToCur":true}`
+	deckConf = `{"1":{"id":1,"name":"Default","mod":0,"usn":0,"maxTaken":60,"autoplay":true,"timer":0,"replayq":true,"dyn":false,` +
+		`"new":{"bury":true,"delays":[1,10],"initialFactor":2500,"ints":[1,4,7],"order":1,"perDay":20,"separate":true},` +
+		`"rev":{"bury":true,"ease4":1.3,"fuzz":0.05,"ivlFct":1,"maxIvl":36500,"minSpace":1,"perDay":100},` +
+		`"lapse":{"delays":[10],"leechAction":0,"leechFails":8,"minInt":1,"mult":0}}}`
+	modelCSS = ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n color: black;\n background-color: white;\n}\n"
+)
+
+// Write returns the collection as a package
+func (c *Collection) Write() ([]byte, error) {
+	file, err := os.CreateTemp("", "anki-*.sqlite")
+	if err != nil {
+		return nil, err
+	}
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.writeCollection(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{
+		{legacyCollection, collection},
+		{"media", []byte("{}")},
+	} {
+		w, err := archive.Create(entry.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(entry.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *Collection) writeCollection(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(schema); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	models, decks, err := c.colJSON(now.Unix())
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO col (id, crt, mod, scm, ver, dty, usn, ls, conf, models, decks, dconf, tags)
+		VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')
+	`, c.Created.Unix(), now.UnixMilli(), now.UnixMilli(), collectionConf, models, decks, deckConf)
+	if err != nil {
+		return err
+	}
+
+	for _, note := range c.Notes {
+		tags := ""
+		if len(note.Tags) > 0 {
+			tags = " " + strings.Join(note.Tags, " ") + " "
+		}
+		sortField := ""
+		if len(note.Fields) > 0 {
+			sortField = Text(note.Fields[0])
+		}
+		_, err := tx.Exec(`
+			INSERT INTO notes (id, guid, mid, mod, usn, tags, flds, sfld, csum, flags, data)
+			VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')
+		`, note.ID, note.GUID, note.ModelID, now.Unix(), tags, strings.Join(note.Fields, fieldSeparator), sortField, checksum(sortField))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, card := range c.Cards {
+		_, err := tx.Exec(`
+			INSERT INTO cards (
+				id, nid, did, ord, mod, usn, type, queue, due, ivl, factor, reps, lapses,
+				left, odue, odid, flags, data
+			) VALUES (?, ?, ?, ?, ?, -1, ?, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, '')
+		`, card.ID, card.NoteID, card.DeckID, card.Ord, now.Unix(), card.Type, card.Queue, card.Due,
+			card.Interval, card.Factor, card.Reps, card.Lapses)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, review := range c.Reviews {
+		_, err := tx.Exec(`
+			INSERT INTO revlog (id, cid, usn, ease, ivl, lastIvl, factor, time, type)
+			VALUES (?, ?, -1, ?, ?, ?, ?, ?, ?)
+		`, review.ID, review.CardID, review.Ease, review.Interval, review.LastInterval,
+			review.Factor, review.Time, review.Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// colJSON returns the models and decks columns of the col table
+func (c *Collection) colJSON(mod int64) (string, string, error) {
+	decks := map[string]interface{}{
+		strconv.Itoa(defaultDeckID): deckObject(Deck{ID: defaultDeckID, Name: "Default"}, mod),
+	}
+	// New notes of the models go to the first deck
+	deckID := int64(defaultDeckID)
+	for id, deck := range c.Decks {
+		decks[strconv.FormatInt(id, 10)] = deckObject(deck, mod)
+		if deckID == defaultDeckID || id < deckID {
+			deckID = id
+		}
+	}
+
+	models := make(map[string]interface{})
+	for id, model := range c.Models {
+		models[strconv.FormatInt(id, 10)] = modelObject(model, deckID, mod)
+	}
+
+	modelsJSON, err := json.Marshal(models)
+	if err != nil {
+		return "", "", err
+	}
+	decksJSON, err := json.Marshal(decks)
+	if err != nil {
+		return "", "", err
+	}
+	return string(modelsJSON), string(decksJSON), nil
+}
+
+func deckObject(deck Deck, mod int64) map[string]interface{} {
+	return map[string]interface{}{
+		"id":               deck.ID,
+		"name":             deck.Name,
+		"mod":              mod,
+		"usn":              -1,
+		"lrnToday":         []int{0, 0},
+		"revToday":         []int{0, 0},
+		"newToday":         []int{0, 0},
+		"timeToday":        []int{0, 0},
+		"collapsed":        false,
+		"browserCollapsed": false,
+		"desc":             "",
+		"dyn":              0,
+		"conf":             1,
+		"extendNew":        0,
+		"extendRev":        0,
+	}
+}
+
+func modelObject(model Model, deckID, mod int64) map[string]interface{} {
+	fields := make([]map[string]interface{}, len(model.Fields))
+	for i, name := range model.Fields {
+		fields[i] = map[string]interface{}{
+			"name":   name,
+			"ord":    i,
+			"sticky": false,
+			"rtl":    false,
+			"font":   "Arial",
+			"size":   20,
+			"media":  []string{},
+		}
+	}
+
+	templates := make([]map[string]interface{}, len(model.Templates))
+	requirements := make([][]interface{}, len(model.Templates))
+	for i, template := range model.Templates {
+		templates[i] = map[string]interface{}{
+			"name":  template.Name,
+			"ord":   i,
+			"qfmt":  template.Front,
+			"afmt":  template.Back,
+			"bqfmt": "",
+			"bafmt": "",
+			"did":   nil,
+		}
+		requirements[i] = []interface{}{i, "any", []int{0}}
+	}
+
+	return map[string]interface{}{
+		"id":        model.ID,
+		"name":      model.Name,
+		"type":      0,
+		"mod":       mod,
+		"usn":       -1,
+		"sortf":     0,
+		"did":       deckID,
+		"tmpls":     templates,
+		"flds":      fields,
+		"css":       modelCSS,
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"req":       requirements,
+		"tags":      []string{},
+		"vers":      []interface{}{},
+	}
+}
+
+// checksum is the csum of a note: the first 8 hex digits of the SHA-1 of
+// its sort field, used by Anki to find duplicates
+func checksum(field string) int64 {
+	sum := sha1.Sum([]byte(field))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
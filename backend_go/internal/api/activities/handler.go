@@ -4,6 +4,11 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/listing"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/pagination"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -19,61 +24,184 @@ func NewHandler(activityService *service.ActivityService) *Handler {
 	}
 }
 
-// RegisterRoutes registers all routes for study activities
+// RegisterRoutes registers all routes for study activities. Changing
+// activities requires the manage content permission.
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	activities := r.Group("/study_activities")
 	{
+		activities.GET("", h.List)
 		activities.GET("/:id", h.Get)
 		activities.GET("/:id/study_sessions", h.ListSessions)
+		activities.POST("/:id/launch", h.Launch)
 	}
+
+	manage := r.Group("/study_activities", auth.Require(service.PermManageContent))
+	{
+		manage.POST("", h.Create)
+		manage.PUT("/:id", h.Update)
+		manage.DELETE("/:id", h.Delete)
+	}
+}
+
+type activityRequest struct {
+	Name         string `json:"name" binding:"required"`
+	URL          string `json:"url" binding:"required"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Description  string `json:"description"`
+	Scheduler    string `json:"scheduler"`
+}
+
+func (r activityRequest) toModel() models.StudyActivity {
+	return models.StudyActivity{
+		Name:         r.Name,
+		URL:          r.URL,
+		ThumbnailURL: r.ThumbnailURL,
+		Description:  r.Description,
+		Scheduler:    r.Scheduler,
+	}
+}
+
+// List returns a paginated list of study activities
+func (h *Handler) List(c *gin.Context) {
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	activities, total, err := h.activityService.List(listing.Options(c), page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, activities, total, page)
 }
 
 // Get returns a single study activity
 func (h *Handler) Get(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		apierror.BadRequest(c, "invalid id")
 		return
 	}
 
 	activity, err := h.activityService.Get(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
 	if activity == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "activity not found"})
+		apierror.NotFound(c, "activity not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, activity)
 }
 
-// ListSessions returns study sessions for an activity
+// ListSessions returns study sessions for an activity, optionally of one
+// language pair
 func (h *Handler) ListSessions(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pair := service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")}
+	sessions, total, err := h.activityService.ListSessions(auth.UserID(c), id, pair, listing.Options(c), page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, sessions, total, page)
+}
+
+// Create registers a new study activity
+func (h *Handler) Create(c *gin.Context) {
+	var req activityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	activity, err := h.activityService.Create(req.toModel())
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, activity)
+}
+
+// Update replaces a study activity
+func (h *Handler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req activityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	activity, err := h.activityService.Update(id, req.toModel())
+	if err != nil {
+		apierror.Respond(c, err)
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	c.JSON(http.StatusOK, activity)
+}
+
+// Delete removes a study activity along with its study sessions
+func (h *Handler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	if err := h.activityService.Delete(id); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Launch starts a study session for a group and returns the activity URL
+func (h *Handler) Launch(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req struct {
+		GroupID int64 `json:"group_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
 
-	sessions, total, err := h.activityService.ListSessions(id, page, perPage)
+	launch, err := h.activityService.Launch(auth.UserID(c), id, req.GroupID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": sessions,
-		"pagination": gin.H{
-			"current_page":   page,
-			"total_pages":    (total + perPage - 1) / perPage,
-			"total_items":    total,
-			"items_per_page": perPage,
-		},
-	})
+	c.JSON(http.StatusCreated, launch)
 }
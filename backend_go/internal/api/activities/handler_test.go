@@ -0,0 +1,198 @@
+package activities
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	activityService := service.NewActivityService()
+	handler := NewHandler(activityService)
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, "admin@example.com", service.RoleAdmin)))
+	handler.RegisterRoutes(api)
+
+	return r, db
+}
+
+func TestListActivities(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Insert test data
+	_, err := db.Exec(`
+		INSERT INTO study_activities (name, url) VALUES
+		('Vocabulary Quiz', 'http://localhost:3000/activities/quiz'),
+		('Memory Game', 'http://localhost:3000/activities/memory');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/study_activities", nil)
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"items"`
+		Pagination struct {
+			TotalItems int `json:"total_items"`
+		} `json:"pagination"`
+	}
+
+	testutil.ParseResponse(t, w, &response)
+
+	if len(response.Items) != 2 || response.Pagination.TotalItems != 2 {
+		t.Errorf("Expected 2 activities, got %d (total %d)", len(response.Items), response.Pagination.TotalItems)
+	}
+}
+
+func TestListActivitySessionsFiltered(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name, source_language) VALUES ('Greetings', 'fr'), ('Verbs', 'ja');
+		INSERT INTO study_activities (name, url) VALUES ('Flashcards', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES
+		(1, 1, 1, '2025-01-10 09:00:00'),
+		(1, 2, 1, '2025-01-20 09:00:00'),
+		(1, 1, 1, '2025-02-01 09:00:00');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	tests := []struct {
+		query    string
+		expected []int64
+	}{
+		{"", []int64{3, 2, 1}},
+		{"group_id=1&sort_by=id&order=asc", []int64{1, 3}},
+		{"from=2025-01-11&to=2025-01-31", []int64{2}},
+		{"source_language=ja", []int64{2}},
+		{"source_language=fr&target_language=en&sort_by=id&order=asc", []int64{1, 3}},
+		{"target_language=fr", []int64{}},
+	}
+
+	for _, tt := range tests {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/study_activities/1/study_sessions?"+tt.query, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Items []struct {
+				ID int64 `json:"id"`
+			} `json:"items"`
+		}
+		testutil.ParseResponse(t, w, &response)
+
+		if len(response.Items) != len(tt.expected) {
+			t.Fatalf("List %q: expected %v, got %+v", tt.query, tt.expected, response.Items)
+		}
+		for i, item := range response.Items {
+			if item.ID != tt.expected[i] {
+				t.Errorf("List %q: expected %v, got %+v", tt.query, tt.expected, response.Items)
+			}
+		}
+	}
+
+	w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/study_activities/1/study_sessions?activity_id=1", nil))
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCreateActivity(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	body := []byte(`{"name":"Writing Practice","url":"http://localhost:3000/activities/writing"}`)
+	req := httptest.NewRequest("POST", "/api/study_activities", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	body = []byte(`{"name":"Writing Practice"}`)
+	req = httptest.NewRequest("POST", "/api/study_activities", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestLaunchActivity(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Insert test data
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES
+		('Vocabulary Quiz', 'http://localhost:3000/activities/quiz'),
+		('Memory Game', 'http://localhost:3000/memory/{group_id}?session={study_session_id}'),
+		('Broken', 'http://[::1');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	body := []byte(`{"group_id":1}`)
+	req := httptest.NewRequest("POST", "/api/study_activities/1/launch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var launch service.LaunchResponse
+	testutil.ParseResponse(t, w, &launch)
+
+	if launch.LaunchURL != "http://localhost:3000/activities/quiz?group_id=1&study_session_id=1" {
+		t.Errorf("Unexpected launch URL %s", launch.LaunchURL)
+	}
+
+	req = httptest.NewRequest("POST", "/api/study_activities/2/launch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+	testutil.ParseResponse(t, w, &launch)
+
+	if launch.LaunchURL != "http://localhost:3000/memory/1?session=2" {
+		t.Errorf("Unexpected launch URL %s", launch.LaunchURL)
+	}
+
+	// No session is left behind when the URL cannot be built
+	req = httptest.NewRequest("POST", "/api/study_activities/3/launch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	var sessions int
+	db.QueryRow("SELECT COUNT(*) FROM study_sessions").Scan(&sessions)
+	if sessions != 2 {
+		t.Errorf("Expected 2 study sessions, got %d", sessions)
+	}
+
+	// Unknown activity
+	req = httptest.NewRequest("POST", "/api/study_activities/99/launch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,173 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/pagination"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	resetService  *service.ResetService
+	backupService *service.BackupService
+}
+
+func NewHandler(resetService *service.ResetService, backupService *service.BackupService) *Handler {
+	return &Handler{
+		resetService:  resetService,
+		backupService: backupService,
+	}
+}
+
+// RegisterRoutes registers the reset and backup endpoints and the audit
+// log, which require the reset data permission
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	admin := r.Group("", auth.Require(service.PermResetData))
+	{
+		admin.POST("/reset_confirmations", h.RequestConfirmation)
+		admin.POST("/reset_history", h.ResetHistory)
+		admin.POST("/full_reset", h.FullReset)
+		admin.GET("/audit_log", h.AuditLog)
+		admin.GET("/backups", h.ListBackups)
+		admin.POST("/backups", h.CreateBackup)
+		admin.POST("/backups/:name/restore", h.RestoreBackup)
+	}
+}
+
+// RequestConfirmation issues a token that confirms one reset
+func (h *Handler) RequestConfirmation(c *gin.Context) {
+	var req struct {
+		Action string `json:"action" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	confirmation, err := h.resetService.RequestConfirmation(auth.UserID(c), req.Action)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, confirmation)
+}
+
+// ResetHistory archives a user's study history, the caller's own unless
+// user_id is given. group_id, study_activity_id, from and to narrow the
+// reset to some of their sessions.
+func (h *Handler) ResetHistory(c *gin.Context) {
+	var req struct {
+		service.HistoryResetScope
+		ConfirmationToken string `json:"confirmation_token"`
+		UserID            *int64 `json:"user_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	target := auth.UserID(c)
+	if req.UserID != nil {
+		target = *req.UserID
+	}
+
+	reset, err := h.resetService.ResetHistory(auth.UserID(c), target, req.HistoryResetScope, req.ConfirmationToken)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Study history has been reset",
+		"success": true,
+		"reset":   reset,
+	})
+}
+
+// FullReset deletes all study history, vocabulary and activities
+func (h *Handler) FullReset(c *gin.Context) {
+	var req struct {
+		ConfirmationToken string `json:"confirmation_token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	if err := h.resetService.FullReset(auth.UserID(c), req.ConfirmationToken); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "System reset complete",
+		"success": true,
+	})
+}
+
+// AuditLog returns a paginated list of audit entries, newest first
+func (h *Handler) AuditLog(c *gin.Context) {
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	entries, total, err := h.resetService.AuditLog(page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, entries, total, page)
+}
+
+// ListBackups returns the database snapshots, newest first
+func (h *Handler) ListBackups(c *gin.Context) {
+	snapshots, err := h.backupService.List()
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": snapshots})
+}
+
+// CreateBackup takes a snapshot of the database
+func (h *Handler) CreateBackup(c *gin.Context) {
+	snapshot, err := h.backupService.Snapshot(service.SnapshotManual)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// RestoreBackup replaces the database with a snapshot
+func (h *Handler) RestoreBackup(c *gin.Context) {
+	var req struct {
+		ConfirmationToken string `json:"confirmation_token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	previous, err := h.backupService.Restore(auth.UserID(c), c.Param("name"), req.ConfirmationToken)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Database restored",
+		"success":           true,
+		"previous_snapshot": previous.Name,
+	})
+}
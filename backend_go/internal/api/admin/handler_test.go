@@ -0,0 +1,329 @@
+package admin
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T, role string) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	resetService := service.NewResetService()
+	handler := NewHandler(resetService, service.NewBackupService())
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, role+"@example.com", role)))
+	handler.RegisterRoutes(api)
+
+	return r, db
+}
+
+func confirm(t *testing.T, r *gin.Engine, action string) string {
+	t.Helper()
+
+	w := testutil.PostJSON(r, "/api/reset_confirmations", map[string]string{"action": action})
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var confirmation service.Confirmation
+	testutil.ParseResponse(t, w, &confirmation)
+	return confirmation.Token
+}
+
+func TestResetRequiresConfirmation(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	testutil.CreateTestUser(t, db, "marie@example.com", service.RoleLearner)
+	testutil.InsertHistory(t, db, 1, 2)
+
+	historyToken := confirm(t, r, service.ActionResetHistory)
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"missing token", map[string]interface{}{}},
+		{"unknown token", map[string]interface{}{"confirmation_token": "nope"}},
+		{"token for another action", map[string]interface{}{"confirmation_token": historyToken}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutil.PostJSON(r, "/api/full_reset", tt.body)
+			testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+			var response apierror.Body
+			testutil.ParseResponse(t, w, &response)
+			if response.Code != apierror.CodeValidation {
+				t.Errorf("Expected code %s, got %s", apierror.CodeValidation, response.Code)
+			}
+		})
+	}
+
+	if words := testutil.Count(t, db, "SELECT COUNT(*) FROM words"); words != 2 {
+		t.Fatalf("Expected nothing to be deleted, got %d words", words)
+	}
+
+	w := testutil.PostJSON(r, "/api/reset_confirmations", map[string]string{"action": "drop_database"})
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	// A token confirms exactly one reset
+	body := map[string]interface{}{"confirmation_token": historyToken, "user_id": 2}
+	w = testutil.PostJSON(r, "/api/reset_history", body)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	w = testutil.PostJSON(r, "/api/reset_history", body)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	if sessions := testutil.Count(t, db, "SELECT COUNT(*) FROM study_sessions WHERE user_id = 2"); sessions != 0 {
+		t.Errorf("Expected the learner's sessions to be deleted, got %d", sessions)
+	}
+	if sessions := testutil.Count(t, db, "SELECT COUNT(*) FROM study_sessions WHERE user_id = 1"); sessions != 3 {
+		t.Errorf("Expected the admin's sessions to be kept, got %d", sessions)
+	}
+	if sessions := testutil.Count(t, db, "SELECT COUNT(*) FROM archived_study_sessions WHERE user_id = 2"); sessions != 3 {
+		t.Errorf("Expected the learner's sessions to be archived, got %d", sessions)
+	}
+
+	w = testutil.PostJSON(r, "/api/full_reset", map[string]interface{}{"confirmation_token": confirm(t, r, service.ActionFullReset)})
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	if words := testutil.Count(t, db, "SELECT COUNT(*) FROM words"); words != 0 {
+		t.Errorf("Expected all words to be deleted, got %d", words)
+	}
+	if resets := testutil.Count(t, db, "SELECT COUNT(*) FROM history_resets"); resets != 0 {
+		t.Errorf("Expected history resets to be deleted, got %d", resets)
+	}
+	if users := testutil.Count(t, db, "SELECT COUNT(*) FROM users"); users != 2 {
+		t.Errorf("Expected accounts to be kept, got %d", users)
+	}
+}
+
+func TestResetIsAudited(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	w := testutil.PostJSON(r, "/api/reset_history", map[string]interface{}{"confirmation_token": confirm(t, r, service.ActionResetHistory)})
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	w = testutil.PostJSON(r, "/api/full_reset", map[string]interface{}{"confirmation_token": confirm(t, r, service.ActionFullReset)})
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	w = testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/audit_log", nil))
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []models.AuditEntry `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &response)
+
+	if len(response.Items) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(response.Items))
+	}
+	if response.Items[0].Action != service.ActionFullReset || response.Items[1].Action != service.ActionResetHistory {
+		t.Errorf("Expected the resets newest first, got %s and %s", response.Items[0].Action, response.Items[1].Action)
+	}
+	for _, entry := range response.Items {
+		if entry.UserID != 1 || entry.UserEmail != "admin@example.com" {
+			t.Errorf("Expected the admin to be recorded, got %d %s", entry.UserID, entry.UserEmail)
+		}
+	}
+	if string(response.Items[1].Details) != `{"reset_id":1,"user_id":1}` {
+		t.Errorf("Expected the reset user and history reset to be recorded, got %s", response.Items[1].Details)
+	}
+}
+
+func TestResetRequiresAdmin(t *testing.T) {
+	for _, role := range []string{service.RoleTeacher, service.RoleLearner} {
+		r, db := setupTestRouter(t, role)
+
+		for _, path := range []string{"/api/reset_confirmations", "/api/reset_history", "/api/full_reset", "/api/backups"} {
+			w := testutil.PostJSON(r, path, map[string]string{"action": service.ActionFullReset})
+			if w.Code != http.StatusForbidden {
+				t.Errorf("%s %s: expected %d, got %d", role, path, http.StatusForbidden, w.Code)
+			}
+		}
+
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/audit_log", nil))
+		testutil.CheckResponseCode(t, http.StatusForbidden, w.Code)
+
+		db.Close()
+	}
+}
+
+func setupBackupRouter(t *testing.T) (*gin.Engine, *sql.DB, string) {
+	dir := t.TempDir()
+	storage.SetBackupDir(dir)
+	t.Cleanup(func() { storage.SetBackupDir("") })
+
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	return r, db, dir
+}
+
+func listBackups(t *testing.T, r *gin.Engine) []service.Snapshot {
+	t.Helper()
+
+	w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/backups", nil))
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []service.Snapshot `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &response)
+	return response.Items
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	r, db, _ := setupBackupRouter(t)
+	defer db.Close()
+
+	testutil.CreateTestUser(t, db, "marie@example.com", service.RoleLearner)
+	testutil.InsertHistory(t, db, 1, 2)
+
+	w := testutil.PostJSON(r, "/api/backups", nil)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+	var backup service.Snapshot
+	testutil.ParseResponse(t, w, &backup)
+	if backup.Reason != service.SnapshotManual || backup.Size == 0 {
+		t.Fatalf("Expected a manual snapshot, got %+v", backup)
+	}
+
+	// Resets snapshot the database first and name the snapshot in the
+	// audit log
+	w = testutil.PostJSON(r, "/api/reset_history", map[string]interface{}{"confirmation_token": confirm(t, r, service.ActionResetHistory), "user_id": 2})
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	w = testutil.PostJSON(r, "/api/full_reset", map[string]interface{}{"confirmation_token": confirm(t, r, service.ActionFullReset)})
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	snapshots := listBackups(t, r)
+	if len(snapshots) != 3 || snapshots[0].Reason != service.ActionFullReset || snapshots[1].Reason != service.ActionResetHistory {
+		t.Fatalf("Expected a snapshot before each reset, newest first, got %+v", snapshots)
+	}
+	var details []byte
+	if err := db.QueryRow("SELECT details FROM audit_log WHERE action = ?", service.ActionFullReset).Scan(&details); err != nil {
+		t.Fatalf("Failed to read the audit log: %v", err)
+	}
+	if want := `{"snapshot":"` + snapshots[0].Name + `"}`; string(details) != want {
+		t.Errorf("Expected %s, got %s", want, details)
+	}
+
+	path := "/api/backups/" + backup.Name + "/restore"
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.PostJSON(r, path, map[string]string{}).Code)
+
+	token := confirm(t, r, service.ActionRestoreBackup)
+	w = testutil.PostJSON(r, "/api/backups/snapshot-20200101T000000.000Z-manual.db/restore", map[string]string{"confirmation_token": token})
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+
+	w = testutil.PostJSON(r, path, map[string]string{"confirmation_token": token})
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	if words := testutil.Count(t, db, "SELECT COUNT(*) FROM words"); words != 2 {
+		t.Errorf("Expected the words to be restored, got %d words", words)
+	}
+	if sessions := testutil.Count(t, db, "SELECT COUNT(*) FROM study_sessions"); sessions != 6 {
+		t.Errorf("Expected every session to be restored, got %d", sessions)
+	}
+	if restores := testutil.Count(t, db, "SELECT COUNT(*) FROM audit_log WHERE action = 'restore_backup'"); restores != 1 {
+		t.Errorf("Expected the restore to be audited, got %d entries", restores)
+	}
+	if snapshots := listBackups(t, r); snapshots[0].Reason != service.SnapshotPreRestore {
+		t.Errorf("Expected a snapshot before the restore, got %+v", snapshots[0])
+	}
+
+	// The token was used up
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.PostJSON(r, path, map[string]string{"confirmation_token": token}).Code)
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	r, db, dir := setupBackupRouter(t)
+	defer db.Close()
+
+	keep := service.SnapshotRetention[service.SnapshotManual]
+	service.SnapshotRetention[service.SnapshotManual] = 2
+	defer func() { service.SnapshotRetention[service.SnapshotManual] = keep }()
+
+	for _, name := range []string{
+		"snapshot-20250101T000000.000Z-manual.db",
+		"snapshot-20250102T000000.000Z-manual.db",
+		"snapshot-20250103T000000.000Z-manual.db",
+		"snapshot-20250101T000000.000Z-scheduled.db",
+		"snapshot-20250102T000000.000Z-full_reset.db",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("old"), 0o644); err != nil {
+			t.Fatalf("Failed to write the snapshot: %v", err)
+		}
+	}
+
+	w := testutil.PostJSON(r, "/api/backups", nil)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+	var backup service.Snapshot
+	testutil.ParseResponse(t, w, &backup)
+
+	var names []string
+	for _, snapshot := range listBackups(t, r) {
+		names = append(names, snapshot.Name)
+	}
+	expected := []string{
+		backup.Name,
+		"snapshot-20250103T000000.000Z-manual.db",
+		"snapshot-20250102T000000.000Z-full_reset.db",
+		"snapshot-20250101T000000.000Z-scheduled.db",
+	}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected only the newest 2 manual snapshots to be kept, got %v", names)
+	}
+}
+
+func TestRestoreChecksSchemaVersion(t *testing.T) {
+	r, db, dir := setupBackupRouter(t)
+	defer db.Close()
+
+	testutil.CreateTestUser(t, db, "marie@example.com", service.RoleLearner)
+	testutil.InsertHistory(t, db, 1, 2)
+
+	// A snapshot from a newer server
+	name := "snapshot-20260101T000000.000Z-manual.db"
+	if err := storage.Snapshot(db, filepath.Join(dir, name)); err != nil {
+		t.Fatalf("Failed to take a snapshot: %v", err)
+	}
+	newer, err := storage.Open(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("Failed to open the snapshot: %v", err)
+	}
+	_, err = newer.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES (9999, 'future', '')")
+	newer.Close()
+	if err != nil {
+		t.Fatalf("Failed to change the snapshot: %v", err)
+	}
+
+	// Not a database at all
+	if err := os.WriteFile(filepath.Join(dir, "snapshot-20260102T000000.000Z-manual.db"), []byte("not a database"), 0o644); err != nil {
+		t.Fatalf("Failed to write the snapshot: %v", err)
+	}
+
+	for _, name := range []string{name, "snapshot-20260102T000000.000Z-manual.db"} {
+		token := confirm(t, r, service.ActionRestoreBackup)
+		w := testutil.PostJSON(r, "/api/backups/"+name+"/restore", map[string]string{"confirmation_token": token})
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	}
+
+	if snapshots := listBackups(t, r); len(snapshots) != 2 {
+		t.Errorf("Expected failed restores to leave the snapshots as they were, got %+v", snapshots)
+	}
+	if words := testutil.Count(t, db, "SELECT COUNT(*) FROM words"); words != 2 {
+		t.Errorf("Expected the database to be kept, got %d words", words)
+	}
+}
@@ -0,0 +1,110 @@
+// Package apierror writes error responses in the format shared by every
+// handler:
+//
+//	{"error": "group 12 not found", "code": "not_found"}
+//
+// Validation errors about individual fields list them in fields.
+//
+// Respond maps the service error types onto HTTP statuses: UnauthorizedError
+// to 401, ForbiddenError to 403, NotFoundError to 404, ValidationError to 422, ConflictError to 409
+// and anything else to 500.
+// Requests that cannot be parsed at all are answered with BadRequest (400).
+package apierror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Error codes returned in the code field
+const (
+	CodeBadRequest   = "bad_request"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeValidation   = "validation_failed"
+	CodeConflict     = "conflict"
+	CodeInternal     = "internal_error"
+)
+
+// Body is the JSON body of every error response
+type Body struct {
+	Error  string               `json:"error"`
+	Code   string               `json:"code"`
+	Fields []service.FieldError `json:"fields,omitempty"`
+}
+
+// Respond writes the response matching err's type
+func Respond(c *gin.Context, err error) {
+	var unauthorizedErr *service.UnauthorizedError
+	var forbiddenErr *service.ForbiddenError
+	var notFoundErr *service.NotFoundError
+	var validationErr *service.ValidationError
+	var conflictErr *service.ConflictError
+	var sqliteErr sqlite3.Error
+
+	switch {
+	case errors.As(err, &unauthorizedErr):
+		Unauthorized(c, unauthorizedErr.Message)
+	case errors.As(err, &forbiddenErr):
+		write(c, http.StatusForbidden, CodeForbidden, forbiddenErr.Message)
+	case errors.As(err, &notFoundErr):
+		write(c, http.StatusNotFound, CodeNotFound, notFoundErr.Message)
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, Body{
+			Error:  validationErr.Message,
+			Code:   CodeValidation,
+			Fields: validationErr.Fields,
+		})
+	case errors.As(err, &conflictErr):
+		write(c, http.StatusConflict, CodeConflict, conflictErr.Message)
+	case errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey:
+		// Existence is checked by the services; this only catches races
+		write(c, http.StatusUnprocessableEntity, CodeValidation, "a referenced resource does not exist")
+	default:
+		write(c, http.StatusInternalServerError, CodeInternal, err.Error())
+	}
+}
+
+// Binding reports an error from binding the request body: a body that
+// parsed but failed its binding rules is a validation error, anything else
+// a bad request
+func Binding(c *gin.Context, err error) {
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		Validation(c, err.Error())
+		return
+	}
+
+	BadRequest(c, err.Error())
+}
+
+// BadRequest reports a request that could not be parsed
+func BadRequest(c *gin.Context, message string) {
+	write(c, http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Unauthorized reports a request without valid credentials
+func Unauthorized(c *gin.Context, message string) {
+	write(c, http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// NotFound reports a missing resource found by a read
+func NotFound(c *gin.Context, message string) {
+	write(c, http.StatusNotFound, CodeNotFound, message)
+}
+
+// Validation reports a request that parsed but is not acceptable
+func Validation(c *gin.Context, message string) {
+	write(c, http.StatusUnprocessableEntity, CodeValidation, message)
+}
+
+func write(c *gin.Context, status int, code, message string) {
+	c.JSON(status, Body{Error: message, Code: code})
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userKey is the gin context key holding the authenticated user
+const userKey = "auth.user"
+
+type Handler struct {
+	authService *service.AuthService
+}
+
+func NewHandler(authService *service.AuthService) *Handler {
+	return &Handler{
+		authService: authService,
+	}
+}
+
+// RegisterRoutes registers the account routes. Register and login are
+// public; logout and me go through protected, which must use Middleware.
+func (h *Handler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	auth := public.Group("/auth")
+	{
+		auth.POST("/register", h.Register)
+		auth.POST("/login", h.Login)
+	}
+
+	account := protected.Group("/auth")
+	{
+		account.POST("/logout", h.Logout)
+		account.GET("/me", h.Me)
+	}
+}
+
+// Middleware rejects requests without a valid bearer token and stores the
+// token's user in the context for UserID
+func Middleware(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			apierror.Unauthorized(c, "missing bearer token")
+			c.Abort()
+			return
+		}
+
+		user, err := authService.Authenticate(token)
+		if err != nil {
+			apierror.Respond(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(userKey, user)
+		c.Next()
+	}
+}
+
+// Require rejects requests from users whose role lacks perm. It must run
+// after Middleware.
+func Require(perm service.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := CurrentUser(c)
+		if !service.HasPermission(user.Role, perm) {
+			apierror.Respond(c, &service.ForbiddenError{
+				Message: fmt.Sprintf("role %s is not allowed to %s", user.Role, strings.ReplaceAll(string(perm), "_", " ")),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SetUser stores the authenticated user in the context. Tests use it to
+// stand in for Middleware.
+func SetUser(c *gin.Context, user *models.User) {
+	c.Set(userKey, user)
+}
+
+// CurrentUser returns the user authenticated by Middleware
+func CurrentUser(c *gin.Context) *models.User {
+	user, _ := c.MustGet(userKey).(*models.User)
+	return user
+}
+
+// UserID returns the id of the user authenticated by Middleware
+func UserID(c *gin.Context) int64 {
+	return CurrentUser(c).ID
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+type credentials struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// Register creates an account and returns it with a bearer token
+func (h *Handler) Register(c *gin.Context) {
+	var req struct {
+		credentials
+		Name string `json:"name"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	response, err := h.authService.Register(req.Email, req.Password, req.Name)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// Login exchanges an email and password for a bearer token
+func (h *Handler) Login(c *gin.Context) {
+	var req credentials
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	response, err := h.authService.Login(req.Email, req.Password)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Logout revokes the bearer token the request was made with
+func (h *Handler) Logout(c *gin.Context) {
+	token, _ := bearerToken(c)
+	if err := h.authService.Logout(token); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Me returns the authenticated user
+func (h *Handler) Me(c *gin.Context) {
+	c.JSON(http.StatusOK, CurrentUser(c))
+}
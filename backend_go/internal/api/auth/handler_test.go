@@ -0,0 +1,223 @@
+// External test package: testutil imports auth
+package auth_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	authService := service.NewAuthService()
+	handler := auth.NewHandler(authService)
+
+	r := gin.New()
+	api := r.Group("/api")
+	protected := api.Group("", auth.Middleware(authService))
+	handler.RegisterRoutes(api, protected)
+
+	return r, db
+}
+
+func withToken(req *http.Request, token string) *http.Request {
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	w := testutil.Request(r, "POST", "/api/auth/register", `{"email":"marie@example.com","password":"correct horse","name":"Marie"}`)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var registered service.AuthResponse
+	testutil.ParseResponse(t, w, &registered)
+	if registered.Token == "" || registered.User.Email != "marie@example.com" || registered.User.Name != "Marie" {
+		t.Fatalf("Unexpected registration response %+v", registered)
+	}
+
+	var hash string
+	db.QueryRow("SELECT password_hash FROM users WHERE id = ?", registered.User.ID).Scan(&hash)
+	if hash == "" || hash == "correct horse" {
+		t.Errorf("Expected the password to be stored hashed, got %q", hash)
+	}
+
+	w = testutil.ExecuteRequest(r, withToken(httptest.NewRequest("GET", "/api/auth/me", nil), registered.Token))
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var me models.User
+	testutil.ParseResponse(t, w, &me)
+	if me.ID != registered.User.ID {
+		t.Errorf("Expected user %d, got %d", registered.User.ID, me.ID)
+	}
+
+	// Emails are matched case-insensitively
+	w = testutil.Request(r, "POST", "/api/auth/login", `{"email":"Marie@Example.com","password":"correct horse"}`)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var loggedIn service.AuthResponse
+	testutil.ParseResponse(t, w, &loggedIn)
+	if loggedIn.Token == "" || loggedIn.Token == registered.Token {
+		t.Errorf("Expected a new token, got %q", loggedIn.Token)
+	}
+
+	// Logging out revokes only the token used
+	w = testutil.ExecuteRequest(r, withToken(httptest.NewRequest("POST", "/api/auth/logout", nil), loggedIn.Token))
+	testutil.CheckResponseCode(t, http.StatusNoContent, w.Code)
+
+	w = testutil.ExecuteRequest(r, withToken(httptest.NewRequest("GET", "/api/auth/me", nil), loggedIn.Token))
+	testutil.CheckResponseCode(t, http.StatusUnauthorized, w.Code)
+
+	w = testutil.ExecuteRequest(r, withToken(httptest.NewRequest("GET", "/api/auth/me", nil), registered.Token))
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+}
+
+func TestRegisterErrors(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	w := testutil.Request(r, "POST", "/api/auth/register", `{"email":"marie@example.com","password":"correct horse"}`)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+		code     string
+	}{
+		{"missing password", `{"email":"paul@example.com"}`, http.StatusUnprocessableEntity, apierror.CodeValidation},
+		{"invalid email", `{"email":"paul","password":"correct horse"}`, http.StatusUnprocessableEntity, apierror.CodeValidation},
+		{"short password", `{"email":"paul@example.com","password":"short"}`, http.StatusUnprocessableEntity, apierror.CodeValidation},
+		{"email taken", `{"email":"MARIE@example.com","password":"correct horse"}`, http.StatusConflict, apierror.CodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutil.Request(r, "POST", "/api/auth/register", tt.body)
+			testutil.CheckResponseCode(t, tt.expected, w.Code)
+
+			var response apierror.Body
+			testutil.ParseResponse(t, w, &response)
+			if response.Code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, response.Code)
+			}
+		})
+	}
+}
+
+func TestUnauthorizedRequests(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	w := testutil.Request(r, "POST", "/api/auth/register", `{"email":"marie@example.com","password":"correct horse"}`)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	w = testutil.Request(r, "POST", "/api/auth/login", `{"email":"marie@example.com","password":"wrong password"}`)
+	testutil.CheckResponseCode(t, http.StatusUnauthorized, w.Code)
+
+	w = testutil.Request(r, "POST", "/api/auth/login", `{"email":"paul@example.com","password":"correct horse"}`)
+	testutil.CheckResponseCode(t, http.StatusUnauthorized, w.Code)
+
+	for _, header := range []string{"", "Bearer", "Basic abc", "Bearer unknown"} {
+		req := httptest.NewRequest("GET", "/api/auth/me", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := testutil.ExecuteRequest(r, req)
+		testutil.CheckResponseCode(t, http.StatusUnauthorized, w.Code)
+
+		var response apierror.Body
+		testutil.ParseResponse(t, w, &response)
+		if response.Code != apierror.CodeUnauthorized {
+			t.Errorf("Expected code %s, got %s", apierror.CodeUnauthorized, response.Code)
+		}
+	}
+
+	// Expired tokens are rejected
+	var login service.AuthResponse
+	w = testutil.Request(r, "POST", "/api/auth/login", `{"email":"marie@example.com","password":"correct horse"}`)
+	testutil.ParseResponse(t, w, &login)
+	if _, err := db.Exec("UPDATE auth_tokens SET expires_at = datetime('now', '-1 minute')"); err != nil {
+		t.Fatalf("Failed to expire tokens: %v", err)
+	}
+
+	w = testutil.ExecuteRequest(r, withToken(httptest.NewRequest("GET", "/api/auth/me", nil), login.Token))
+	testutil.CheckResponseCode(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestFirstUserAdoptsExistingHistory(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"test","english":"test"}');
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (group_id, study_activity_id) VALUES (1, 1);
+		INSERT INTO word_review_states (word_id, due_at) VALUES (1, CURRENT_TIMESTAMP);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	var first, second service.AuthResponse
+	testutil.ParseResponse(t, testutil.Request(r, "POST", "/api/auth/register", `{"email":"marie@example.com","password":"correct horse"}`), &first)
+	testutil.ParseResponse(t, testutil.Request(r, "POST", "/api/auth/register", `{"email":"paul@example.com","password":"correct horse"}`), &second)
+
+	if first.User.Role != service.RoleAdmin || second.User.Role != service.RoleLearner {
+		t.Errorf("Expected the first user to be an admin and the second a learner, got %s and %s", first.User.Role, second.User.Role)
+	}
+
+	for _, table := range []string{"study_sessions", "word_review_states"} {
+		var owner sql.NullInt64
+		db.QueryRow("SELECT user_id FROM " + table).Scan(&owner)
+		if owner.Int64 != first.User.ID {
+			t.Errorf("Expected %s to belong to user %d, got %v", table, first.User.ID, owner)
+		}
+	}
+}
+
+func TestRequire(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	r := gin.New()
+	for _, role := range service.Roles() {
+		user := testutil.CreateTestUser(t, db, role+"@example.com", role)
+		group := r.Group("/"+role, testutil.AsUser(user))
+		group.GET("/content", auth.Require(service.PermManageContent), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+	}
+
+	expected := map[string]int{
+		service.RoleAdmin:   http.StatusNoContent,
+		service.RoleTeacher: http.StatusNoContent,
+		service.RoleLearner: http.StatusForbidden,
+	}
+	for role, code := range expected {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/"+role+"/content", nil))
+		if w.Code != code {
+			t.Errorf("%s: expected %d, got %d", role, code, w.Code)
+		}
+		if code == http.StatusForbidden {
+			var response apierror.Body
+			testutil.ParseResponse(t, w, &response)
+			if response.Code != apierror.CodeForbidden {
+				t.Errorf("Expected code %s, got %s", apierror.CodeForbidden, response.Code)
+			}
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package classes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/pagination"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	classService *service.ClassService
+}
+
+func NewHandler(classService *service.ClassService) *Handler {
+	return &Handler{
+		classService: classService,
+	}
+}
+
+// RegisterRoutes registers all routes for classes. Students can see their
+// classes and assignments; running a class requires the teach permission.
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	classes := r.Group("/classes")
+	{
+		classes.GET("", h.List)
+		classes.GET("/:id", h.Get)
+		classes.GET("/:id/assignments", h.ListAssignments)
+	}
+
+	manage := r.Group("/classes", auth.Require(service.PermTeach))
+	{
+		manage.POST("", h.Create)
+		manage.PUT("/:id", h.Rename)
+		manage.DELETE("/:id", h.Delete)
+		manage.GET("/:id/students", h.ListStudents)
+		manage.POST("/:id/students", h.AddStudents)
+		manage.DELETE("/:id/students/:user_id", h.RemoveStudent)
+		manage.POST("/:id/assignments", h.CreateAssignment)
+		manage.DELETE("/:id/assignments/:assignment_id", h.DeleteAssignment)
+		manage.GET("/:id/assignments/:assignment_id/progress", h.AssignmentProgress)
+		manage.GET("/:id/dashboard", h.Dashboard)
+	}
+}
+
+type classRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type studentsRequest struct {
+	UserIDs []int64 `json:"user_ids" binding:"required"`
+}
+
+type assignmentRequest struct {
+	GroupID         int64     `json:"group_id" binding:"required"`
+	StudyActivityID int64     `json:"study_activity_id" binding:"required"`
+	DueAt           time.Time `json:"due_at" binding:"required"`
+}
+
+// List returns a paginated list of the classes the user teaches or attends
+func (h *Handler) List(c *gin.Context) {
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	classes, total, err := h.classService.List(auth.CurrentUser(c), page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, classes, total, page)
+}
+
+// Get returns a single class by ID
+func (h *Handler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	class, err := h.classService.Get(auth.CurrentUser(c), id)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	if class == nil {
+		apierror.NotFound(c, "class not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, class)
+}
+
+// Create starts a class taught by the user
+func (h *Handler) Create(c *gin.Context) {
+	var req classRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	class, err := h.classService.Create(auth.CurrentUser(c), req.Name)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, class)
+}
+
+// Rename changes the name of a class
+func (h *Handler) Rename(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req classRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	class, err := h.classService.Rename(auth.CurrentUser(c), id, req.Name)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, class)
+}
+
+// Delete removes a class along with its enrollments and assignments
+func (h *Handler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	if err := h.classService.Delete(auth.CurrentUser(c), id); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListStudents returns a paginated list of the students in a class
+func (h *Handler) ListStudents(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	students, total, err := h.classService.ListStudents(auth.CurrentUser(c), id, page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, students, total, page)
+}
+
+// AddStudents enrolls users in a class
+func (h *Handler) AddStudents(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req studentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	class, err := h.classService.AddStudents(auth.CurrentUser(c), id, req.UserIDs)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, class)
+}
+
+// RemoveStudent unenrolls a user from a class
+func (h *Handler) RemoveStudent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid user id")
+		return
+	}
+
+	if err := h.classService.RemoveStudent(auth.CurrentUser(c), id, userID); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListAssignments returns the assignments of a class
+func (h *Handler) ListAssignments(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	assignments, err := h.classService.ListAssignments(auth.CurrentUser(c), id)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": assignments})
+}
+
+// CreateAssignment asks a class to study a group with an activity by a due
+// date
+func (h *Handler) CreateAssignment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req assignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	assignment, err := h.classService.CreateAssignment(auth.CurrentUser(c), id, req.GroupID, req.StudyActivityID, req.DueAt)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// DeleteAssignment removes an assignment from a class
+func (h *Handler) DeleteAssignment(c *gin.Context) {
+	id, assignmentID, ok := assignmentIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.classService.DeleteAssignment(auth.CurrentUser(c), id, assignmentID); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AssignmentProgress returns each student's progress with an assignment
+func (h *Handler) AssignmentProgress(c *gin.Context) {
+	id, assignmentID, ok := assignmentIDs(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.classService.AssignmentProgress(auth.CurrentUser(c), id, assignmentID)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// Dashboard returns the dashboard metrics of every student in a class
+func (h *Handler) Dashboard(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	dashboard, err := h.classService.Dashboard(auth.CurrentUser(c), id)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
+
+func assignmentIDs(c *gin.Context) (int64, int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return 0, 0, false
+	}
+
+	assignmentID, err := strconv.ParseInt(c.Param("assignment_id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid assignment id")
+		return 0, 0, false
+	}
+
+	return id, assignmentID, true
+}
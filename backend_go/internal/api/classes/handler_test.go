@@ -0,0 +1,220 @@
+package classes
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+// classroom holds a router per user sharing one database: a teacher, two
+// learners enrolled by the tests and a second teacher
+type classroom struct {
+	db       *sql.DB
+	teacher  *gin.Engine
+	marie    *gin.Engine
+	paul     *gin.Engine
+	outsider *gin.Engine
+}
+
+func setupClassroom(t *testing.T) *classroom {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	handler := NewHandler(service.NewClassService())
+	router := func(user *models.User) *gin.Engine {
+		r := gin.New()
+		handler.RegisterRoutes(r.Group("/api", testutil.AsUser(user)))
+		return r
+	}
+
+	return &classroom{
+		db:       db,
+		teacher:  router(testutil.CreateTestUser(t, db, "teacher@example.com", service.RoleTeacher)),
+		marie:    router(testutil.CreateTestUser(t, db, "marie@example.com", service.RoleLearner)),
+		paul:     router(testutil.CreateTestUser(t, db, "paul@example.com", service.RoleLearner)),
+		outsider: router(testutil.CreateTestUser(t, db, "other@example.com", service.RoleTeacher)),
+	}
+}
+
+func TestClassMembership(t *testing.T) {
+	c := setupClassroom(t)
+	defer c.db.Close()
+
+	w := testutil.Request(c.teacher, "POST", "/api/classes", `{"name":"French 1"}`)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var class models.Class
+	testutil.ParseResponse(t, w, &class)
+	if class.ID != 1 || class.TeacherID != 1 {
+		t.Fatalf("Expected class 1 taught by user 1, got %+v", class)
+	}
+
+	w = testutil.Request(c.teacher, "POST", "/api/classes/1/students", `{"user_ids":[2,3]}`)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &class)
+	if class.StudentsCount != 2 {
+		t.Errorf("Expected 2 students, got %d", class.StudentsCount)
+	}
+
+	w = testutil.Request(c.teacher, "POST", "/api/classes/1/students", `{"user_ids":[99]}`)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	// Only learners can be enrolled
+	w = testutil.Request(c.teacher, "POST", "/api/classes/1/students", `{"user_ids":[4]}`)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	w = testutil.Request(c.teacher, "GET", "/api/classes/1/students", "")
+	var students struct {
+		Items []models.User `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &students)
+	if len(students.Items) != 2 {
+		t.Errorf("Expected the teacher not to be enrolled, got %+v", students.Items)
+	}
+
+	// Students see their class but cannot run it
+	w = testutil.Request(c.marie, "GET", "/api/classes", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var list struct {
+		Items []models.Class `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &list)
+	if len(list.Items) != 1 || list.Items[0].Name != "French 1" {
+		t.Errorf("Expected the student to see their class, got %+v", list.Items)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusOK, testutil.Request(c.marie, "GET", "/api/classes/1", "").Code)
+	testutil.CheckResponseCode(t, http.StatusForbidden, testutil.Request(c.marie, "GET", "/api/classes/1/students", "").Code)
+
+	// Other teachers do not see it at all
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(c.outsider, "GET", "/api/classes/1", "").Code)
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(c.outsider, "PUT", "/api/classes/1", `{"name":"Mine"}`).Code)
+
+	w = testutil.Request(c.outsider, "GET", "/api/classes", "")
+	testutil.ParseResponse(t, w, &list)
+	if len(list.Items) != 0 {
+		t.Errorf("Expected no classes for another teacher, got %+v", list.Items)
+	}
+
+	w = testutil.Request(c.teacher, "DELETE", "/api/classes/1/students/3", "")
+	testutil.CheckResponseCode(t, http.StatusNoContent, w.Code)
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(c.paul, "GET", "/api/classes/1", "").Code)
+
+	w = testutil.Request(c.teacher, "DELETE", "/api/classes/1", "")
+	testutil.CheckResponseCode(t, http.StatusNoContent, w.Code)
+
+	var members int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM class_members").Scan(&members); err != nil || members != 0 {
+		t.Errorf("Expected enrollments to be deleted with the class, got %d (%v)", members, err)
+	}
+}
+
+func TestAssignmentProgress(t *testing.T) {
+	c := setupClassroom(t)
+	defer c.db.Close()
+
+	_, err := c.db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"chat","english":"cat"}');
+		INSERT INTO words (parts) VALUES ('{"french":"chien","english":"dog"}');
+		INSERT INTO groups (name) VALUES ('Animals');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1), (2, 1);
+		INSERT INTO study_activities (name, url, thumbnail_url, description)
+		VALUES ('Flashcards', 'http://test.com', 'http://test.com/thumb.jpg', 'Test Description');
+		INSERT INTO classes (name, teacher_id) VALUES ('French 1', 1);
+		INSERT INTO class_members (class_id, user_id) VALUES (1, 2), (1, 3);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	w := testutil.Request(c.teacher, "POST", "/api/classes/1/assignments",
+		`{"group_id":1,"study_activity_id":1,"due_at":"2000-01-01T00:00:00Z"}`)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	due := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	w = testutil.Request(c.teacher, "POST", "/api/classes/1/assignments",
+		`{"group_id":1,"study_activity_id":1,"due_at":"`+due+`"}`)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var assignment service.AssignmentResponse
+	testutil.ParseResponse(t, w, &assignment)
+	if assignment.GroupName != "Animals" || assignment.TotalStudents != 2 || assignment.CompletedStudents != 0 {
+		t.Errorf("Expected a new assignment for 2 students, got %+v", assignment)
+	}
+
+	// Marie finishes the assignment with one mistake, Paul does not start
+	now := time.Now().UTC().Add(time.Second)
+	_, err = c.db.Exec(`
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, status, created_at, ended_at)
+		VALUES (2, 1, 1, 'completed', ?, ?);
+		INSERT INTO word_review_items (word_id, study_session_id, correct, created_at)
+		VALUES (1, 1, 1, ?), (2, 1, 0, ?), (2, 1, 1, ?);
+	`, now, now, now, now, now)
+	if err != nil {
+		t.Fatalf("Failed to insert study session: %v", err)
+	}
+
+	w = testutil.Request(c.teacher, "GET", "/api/classes/1/assignments/1/progress", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var progress service.AssignmentProgress
+	testutil.ParseResponse(t, w, &progress)
+	if len(progress.Students) != 2 {
+		t.Fatalf("Expected progress for 2 students, got %d", len(progress.Students))
+	}
+
+	marie, paul := progress.Students[0], progress.Students[1]
+	if !marie.Completed || marie.Late || marie.WordsReviewed != 2 || marie.WordsTotal != 2 || marie.ReviewsCount != 3 {
+		t.Errorf("Expected Marie to have completed the assignment on time, got %+v", marie)
+	}
+	if marie.Accuracy < 66 || marie.Accuracy > 67 {
+		t.Errorf("Expected Marie's accuracy to be 2 of 3, got %f", marie.Accuracy)
+	}
+	if paul.Completed || paul.CompletedAt != nil || paul.ReviewsCount != 0 {
+		t.Errorf("Expected Paul not to have started, got %+v", paul)
+	}
+
+	// Students see the assignments but not each other's progress
+	w = testutil.Request(c.paul, "GET", "/api/classes/1/assignments", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var list struct {
+		Items []service.AssignmentResponse `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &list)
+	if len(list.Items) != 1 || list.Items[0].CompletedStudents != 1 {
+		t.Errorf("Expected one assignment completed by one student, got %+v", list.Items)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusForbidden, testutil.Request(c.paul, "GET", "/api/classes/1/assignments/1/progress", "").Code)
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(c.teacher, "GET", "/api/classes/1/assignments/2/progress", "").Code)
+
+	w = testutil.Request(c.teacher, "GET", "/api/classes/1/dashboard", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var dashboard service.ClassDashboard
+	testutil.ParseResponse(t, w, &dashboard)
+	if len(dashboard.Students) != 2 {
+		t.Fatalf("Expected 2 students on the dashboard, got %d", len(dashboard.Students))
+	}
+
+	first := dashboard.Students[0]
+	if first.User.ID != 2 || first.AssignmentsCompleted != 1 || first.AssignmentsTotal != 1 {
+		t.Errorf("Expected Marie to have completed 1 of 1 assignments, got %+v", first)
+	}
+	if first.LastStudySession == nil || first.QuickStats.TotalStudySessions != 1 {
+		t.Errorf("Expected Marie's study history on the dashboard, got %+v", first)
+	}
+	if dashboard.Students[1].LastStudySession != nil {
+		t.Errorf("Expected Paul to have no study sessions, got %+v", dashboard.Students[1].LastStudySession)
+	}
+
+	w = testutil.Request(c.teacher, "DELETE", "/api/classes/1/assignments/1", "")
+	testutil.CheckResponseCode(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,128 @@
+package conjugations
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	conjugationService *service.ConjugationService
+}
+
+func NewHandler(conjugationService *service.ConjugationService) *Handler {
+	return &Handler{
+		conjugationService: conjugationService,
+	}
+}
+
+// RegisterRoutes registers the conjugation table of words and the
+// conjugation drill of groups and study sessions
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/words/:id/conjugations", h.Conjugations)
+	r.GET("/groups/:id/conjugation_drill", h.Drill)
+	r.POST("/study_sessions/:id/conjugation_reviews", h.Review)
+}
+
+type reviewRequest struct {
+	WordID         int64  `json:"word_id" binding:"required"`
+	Tense          string `json:"tense" binding:"required"`
+	Person         string `json:"person" binding:"required"`
+	Answer         string `json:"answer"`
+	ResponseTimeMs *int   `json:"response_time_ms"`
+}
+
+// tenses returns the comma separated tenses of the tenses query parameter
+func tenses(c *gin.Context) []string {
+	var names []string
+	for _, name := range strings.Split(c.Query("tenses"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Conjugations returns the conjugation table of a French verb
+func (h *Handler) Conjugations(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	conjugations, err := h.conjugationService.Conjugations(id, tenses(c))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	if conjugations == nil {
+		apierror.NotFound(c, "word not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, conjugations)
+}
+
+// Drill returns random person and tense prompts for the verbs of a group
+func (h *Handler) Drill(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	count, err := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(service.DefaultDrillSize)))
+	if err != nil {
+		apierror.Respond(c, &service.ValidationError{
+			Message: "count must be a whole number",
+			Fields:  []service.FieldError{{Field: "count", Message: "must be a whole number"}},
+		})
+		return
+	}
+
+	prompts, err := h.conjugationService.Drill(id, tenses(c), count)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": prompts})
+}
+
+// Review grades an answer to a drill prompt and records it in the study
+// session
+func (h *Handler) Review(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req reviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	review, err := h.conjugationService.Review(auth.UserID(c), id, service.DrillAnswer{
+		WordID:         req.WordID,
+		Tense:          req.Tense,
+		Person:         req.Person,
+		Answer:         req.Answer,
+		ResponseTimeMs: req.ResponseTimeMs,
+	})
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
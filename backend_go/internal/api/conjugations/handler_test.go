@@ -0,0 +1,162 @@
+package conjugations
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/groups"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/sessions"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/words"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+// setupTestRouter registers the conjugation routes next to the word, group
+// and session routes they share paths with
+func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, "learner@example.com", service.RoleLearner)))
+	words.NewHandler(service.NewWordService()).RegisterRoutes(api)
+	groups.NewHandler(service.NewGroupService()).RegisterRoutes(api)
+	sessions.NewHandler(service.NewSessionService()).RegisterRoutes(api)
+	NewHandler(service.NewConjugationService()).RegisterRoutes(api)
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts, part_of_speech) VALUES
+		('{"french":"aller","english":"to go"}', 'verb'),
+		('{"french":"parler","english":"to speak"}', NULL),
+		('{"french":"bonjour","english":"hello"}', NULL),
+		('{"french":"grand","english":"big"}', 'adjective'),
+		('{"french":"noir","english":"black"}', NULL);
+		INSERT INTO groups (name) VALUES ('Verbs');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1), (2, 1), (3, 1), (4, 1), (5, 1);
+		INSERT INTO study_activities (name, url) VALUES ('Drill', 'http://localhost:8080');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id) VALUES (1, 1, 1);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	return r, db
+}
+
+func TestConjugations(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	w := testutil.Request(r, "GET", "/api/words/1/conjugations?tenses=future,passe_compose", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response service.ConjugationsResponse
+	testutil.ParseResponse(t, w, &response)
+	if response.Infinitive != "aller" || response.Auxiliary != "être" || !response.Irregular || len(response.Tenses) != 2 {
+		t.Fatalf("Expected aller in two tenses, got %+v", response)
+	}
+	if form := response.Tenses[0].Forms[3]; form.Form != "irons" || form.Text != "nous irons" {
+		t.Errorf("Expected nous irons, got %+v", form)
+	}
+	if form := response.Tenses[1].Forms[0]; form.Form != "suis allé(e)" {
+		t.Errorf("Expected suis allé(e), got %+v", form)
+	}
+
+	w = testutil.Request(r, "GET", "/api/words/2/conjugations", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Tenses) != 5 || response.Tenses[0].Forms[0].Form != "parle" {
+		t.Errorf("Expected parler in every tense, got %+v", response)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(r, "GET", "/api/words/99/conjugations", "").Code)
+	for _, path := range []string{"/api/words/3/conjugations", "/api/words/4/conjugations", "/api/words/1/conjugations?tenses=subjunctive"} {
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "GET", path, "").Code)
+	}
+}
+
+func TestDrill(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	w := testutil.Request(r, "GET", "/api/groups/1/conjugation_drill?tenses=present&count=50", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []service.DrillPrompt `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &response)
+
+	// Six persons of aller. Untagged words are left out even when they end
+	// like an infinitive, as noir does, and so is grand.
+	if len(response.Items) != 6 {
+		t.Fatalf("Expected 6 prompts, got %d", len(response.Items))
+	}
+	for _, prompt := range response.Items {
+		if prompt.WordID != 1 || prompt.Tense != "present" || prompt.Answer == "" {
+			t.Errorf("Unexpected prompt %+v", prompt)
+		}
+	}
+
+	w = testutil.Request(r, "GET", "/api/groups/1/conjugation_drill?count=3", "")
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Items) != 3 {
+		t.Errorf("Expected 3 prompts, got %d", len(response.Items))
+	}
+
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(r, "GET", "/api/groups/9/conjugation_drill", "").Code)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "GET", "/api/groups/1/conjugation_drill?count=500", "").Code)
+
+	w = testutil.Request(r, "GET", "/api/groups/1/conjugation_drill?count=abc", "")
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	var body apierror.Body
+	testutil.ParseResponse(t, w, &body)
+	if len(body.Fields) != 1 || body.Fields[0].Field != "count" {
+		t.Errorf("Expected count to be rejected, got %+v", body)
+	}
+}
+
+func TestDrillReview(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	tests := []struct {
+		body    string
+		quality int
+	}{
+		{`{"word_id":1,"tense":"future","person":"nous","answer":"nous irons"}`, 4},
+		{`{"word_id":1,"tense":"passe_compose","person":"ils","answer":"sont alles"}`, 3},
+		{`{"word_id":2,"tense":"imperfect","person":"je","answer":"parle"}`, 1},
+	}
+
+	for _, tt := range tests {
+		w := testutil.Request(r, "POST", "/api/study_sessions/1/conjugation_reviews", tt.body)
+		testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+		var review service.DrillReviewResponse
+		testutil.ParseResponse(t, w, &review)
+		if review.Quality != tt.quality || review.Tense == "" || review.Person == "" || review.Direction != service.DirectionProduction {
+			t.Errorf("Expected a %s review with quality %d, got %+v", tt.body, tt.quality, review)
+		}
+	}
+
+	var tense, person string
+	err := db.QueryRow("SELECT tense, person FROM word_review_items WHERE word_id = 2").Scan(&tense, &person)
+	if err != nil || tense != "imperfect" || person != "je" {
+		t.Errorf("Expected the review to be tagged imperfect/je, got %s/%s, %v", tense, person, err)
+	}
+
+	for _, body := range []string{
+		`{"word_id":1,"tense":"past","person":"nous","answer":"allions"}`,
+		`{"word_id":1,"tense":"present","person":"on","answer":"va"}`,
+		`{"word_id":3,"tense":"present","person":"je","answer":"bonjour"}`,
+		`{"word_id":99,"tense":"present","person":"je","answer":"vais"}`,
+	} {
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "POST", "/api/study_sessions/1/conjugation_reviews", body).Code)
+	}
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(r, "POST", "/api/study_sessions/9/conjugation_reviews",
+		`{"word_id":1,"tense":"present","person":"je","answer":"vais"}`).Code)
+}
@@ -0,0 +1,58 @@
+// Package cors answers cross-origin requests from an explicit list of
+// allowed origins. Requests from other origins get no CORS headers, so
+// browsers refuse to hand the response to the calling page.
+package cors
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	allowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	allowedHeaders = "Content-Type, Authorization"
+)
+
+// ParseOrigins splits a comma separated list of origins
+func ParseOrigins(list string) []string {
+	var origins []string
+	for _, origin := range strings.Split(list, ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// Middleware allows cross-origin requests from the given origins only
+func Middleware(origins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if origin != "" && allowed[origin] {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+			c.Writer.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			if origin != "" && !allowed[origin] {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
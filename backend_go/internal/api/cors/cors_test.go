@@ -0,0 +1,65 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter() *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+
+	r := gin.New()
+	r.Use(Middleware([]string{"http://localhost:5173"}))
+	r.GET("/api/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	r := setupTestRouter()
+
+	tests := []struct {
+		name         string
+		method       string
+		origin       string
+		expectedCode int
+		allowOrigin  string
+	}{
+		{"allowed origin", "GET", "http://localhost:5173", http.StatusOK, "http://localhost:5173"},
+		{"allowed preflight", "OPTIONS", "http://localhost:5173", http.StatusNoContent, "http://localhost:5173"},
+		{"other origin", "GET", "https://evil.example", http.StatusOK, ""},
+		{"other origin preflight", "OPTIONS", "https://evil.example", http.StatusForbidden, ""},
+		{"same origin", "GET", "", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/health", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.allowOrigin, got)
+			}
+		})
+	}
+}
+
+func TestParseOrigins(t *testing.T) {
+	got := ParseOrigins(" http://localhost:5173/, ,https://portal.example.com")
+	expected := []string{"http://localhost:5173", "https://portal.example.com"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -3,6 +3,8 @@ package dashboard
 import (
 	"net/http"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -30,31 +32,34 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 
 // LastStudySession returns the most recent study session
 func (h *Handler) LastStudySession(c *gin.Context) {
-	session, err := h.dashboardService.GetLastStudySession()
+	session, err := h.dashboardService.GetLastStudySession(auth.UserID(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, session)
 }
 
-// StudyProgress returns the overall study progress
+// StudyProgress returns the study progress, optionally in one language pair
 func (h *Handler) StudyProgress(c *gin.Context) {
-	progress, err := h.dashboardService.GetStudyProgress()
+	pair := service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")}
+	progress, err := h.dashboardService.GetStudyProgress(auth.UserID(c), pair)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, progress)
 }
 
-// QuickStats returns quick statistics about the user's learning
+// QuickStats returns quick statistics about the user's learning, optionally
+// in one language pair
 func (h *Handler) QuickStats(c *gin.Context) {
-	stats, err := h.dashboardService.GetQuickStats()
+	pair := service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")}
+	stats, err := h.dashboardService.GetQuickStats(auth.UserID(c), pair)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
@@ -20,7 +20,7 @@ func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
 	handler := NewHandler(dashboardService)
 
 	r := gin.New()
-	api := r.Group("/api")
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, "admin@example.com", service.RoleAdmin)))
 	handler.RegisterRoutes(api)
 
 	return r, db
@@ -34,9 +34,9 @@ func TestGetQuickStats(t *testing.T) {
 	_, err := db.Exec(`
 		INSERT INTO groups (name) VALUES ('Test Group');
 		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
-		INSERT INTO study_sessions (group_id, study_activity_id, created_at) VALUES 
-			(1, 1, ?),
-			(1, 1, ?);
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES 
+			(1, 1, 1, ?),
+			(1, 1, 1, ?);
 		INSERT INTO words (parts) VALUES ('{"french":"test","english":"test"}');
 		INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES 
 			(1, 1, true),
@@ -46,6 +46,16 @@ func TestGetQuickStats(t *testing.T) {
 		t.Fatalf("Failed to insert test data: %v", err)
 	}
 
+	// Another learner's history does not count towards the stats
+	other := testutil.CreateTestUser(t, db, "other@example.com", service.RoleLearner)
+	_, err = db.Exec(`
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES (?, 1, 1, ?);
+		INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 3, false);
+	`, other.ID, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
 	req := httptest.NewRequest("GET", "/api/dashboard/quick_stats", nil)
 	w := testutil.ExecuteRequest(r, req)
 
@@ -67,3 +77,84 @@ func TestGetQuickStats(t *testing.T) {
 		t.Errorf("Expected 2 study sessions, got %d", response.TotalStudySessions)
 	}
 }
+
+// insertLanguagePairs adds a French and a Japanese group with a word and a
+// session each. Only the French word is answered correctly.
+func insertLanguagePairs(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name, source_language) VALUES ('Greetings', 'fr'), ('Animals', 'ja');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO words (parts, source_language) VALUES
+			('{"french":"bonjour","english":"hello"}', 'fr'),
+			('{"kanji":"猫","romaji":"neko","english":"cat"}', 'ja'),
+			('{"kanji":"犬","romaji":"inu","english":"dog"}', 'ja');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES
+			(1, 1, 1, ?),
+			(1, 2, 1, ?);
+		INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES
+			(1, 1, true),
+			(2, 2, false);
+	`, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+}
+
+func TestGetStudyProgressByLanguagePair(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	insertLanguagePairs(t, db)
+
+	tests := []struct {
+		query     string
+		studied   int
+		available int
+	}{
+		{"", 2, 3},
+		{"source_language=ja", 1, 2},
+		{"source_language=fr&target_language=en", 1, 1},
+		{"target_language=fr", 0, 0},
+	}
+	for _, tt := range tests {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/dashboard/study_progress?"+tt.query, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var progress service.StudyProgress
+		testutil.ParseResponse(t, w, &progress)
+		if progress.TotalWordsStudied != tt.studied || progress.TotalAvailableWords != tt.available {
+			t.Errorf("Progress %q: expected %d of %d words studied, got %+v", tt.query, tt.studied, tt.available, progress)
+		}
+	}
+}
+
+func TestGetQuickStatsByLanguagePair(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	insertLanguagePairs(t, db)
+
+	tests := []struct {
+		query       string
+		successRate float64
+		sessions    int
+		streak      int
+	}{
+		{"", 50, 2, 1},
+		{"source_language=fr", 100, 1, 1},
+		{"source_language=ja&target_language=en", 0, 1, 1},
+		{"target_language=fr", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/dashboard/quick_stats?"+tt.query, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var stats service.QuickStats
+		testutil.ParseResponse(t, w, &stats)
+		if stats.SuccessRate != tt.successRate || stats.TotalStudySessions != tt.sessions || stats.TotalActiveGroups != tt.sessions || stats.StudyStreakDays != tt.streak {
+			t.Errorf("Stats %q: expected %.0f%% success in %d sessions with a %d day streak, got %+v", tt.query, tt.successRate, tt.sessions, tt.streak, stats)
+		}
+	}
+}
@@ -4,6 +4,11 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/listing"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/pagination"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -19,55 +24,77 @@ func NewHandler(groupService *service.GroupService) *Handler {
 	}
 }
 
-// RegisterRoutes registers all routes for groups
+// RegisterRoutes registers all routes for groups. Changing groups requires
+// the manage content permission.
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	groups := r.Group("/groups")
 	{
 		groups.GET("", h.List)
 		groups.GET("/:id", h.Get)
 		groups.GET("/:id/words", h.ListWords)
+		groups.GET("/:id/due_words", h.ListDueWords)
 		groups.GET("/:id/study_sessions", h.ListStudySessions)
 	}
+
+	manage := r.Group("/groups", auth.Require(service.PermManageContent))
+	{
+		manage.POST("", h.Create)
+		manage.PUT("/:id", h.Rename)
+		manage.DELETE("/:id", h.Delete)
+		manage.POST("/:id/words", h.AddWords)
+		manage.DELETE("/:id/words", h.RemoveWords)
+	}
 }
 
-// List returns a paginated list of groups
+type groupRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type createGroupRequest struct {
+	groupRequest
+	SourceLanguage string `json:"source_language"`
+	TargetLanguage string `json:"target_language"`
+}
+
+type groupWordsRequest struct {
+	WordIDs []int64 `json:"word_ids" binding:"required"`
+}
+
+// List returns a paginated list of groups, optionally of one language pair
 func (h *Handler) List(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pair := service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")}
 
-	groups, total, err := h.groupService.List(page, perPage)
+	groups, total, err := h.groupService.List(pair, listing.Options(c), page.Number, page.PerPage)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": groups,
-		"pagination": gin.H{
-			"current_page":   page,
-			"total_pages":    (total + perPage - 1) / perPage,
-			"total_items":    total,
-			"items_per_page": perPage,
-		},
-	})
+	pagination.Respond(c, groups, total, page)
 }
 
 // Get returns a single group by ID
 func (h *Handler) Get(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		apierror.BadRequest(c, "invalid id")
 		return
 	}
 
 	group, err := h.groupService.Get(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
 	if group == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		apierror.NotFound(c, "group not found")
 		return
 	}
 
@@ -78,54 +105,161 @@ func (h *Handler) Get(c *gin.Context) {
 func (h *Handler) ListWords(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	words, total, err := h.groupService.ListWords(id, listing.Options(c), page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, words, total, page)
+}
+
+// ListDueWords returns the group's words whose spaced repetition review is
+// due now
+func (h *Handler) ListDueWords(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
 
-	words, total, err := h.groupService.ListWords(id, page, perPage)
+	includeNew, _ := strconv.ParseBool(c.DefaultQuery("include_new", "true"))
+
+	words, total, err := h.groupService.ListDueWords(auth.UserID(c), id, includeNew, page.Number, page.PerPage)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": words,
-		"pagination": gin.H{
-			"current_page":   page,
-			"total_pages":    (total + perPage - 1) / perPage,
-			"total_items":    total,
-			"items_per_page": perPage,
-		},
-	})
+	pagination.Respond(c, words, total, page)
 }
 
-// ListStudySessions returns study sessions for a group
+// ListStudySessions returns study sessions for a group, optionally of one
+// language pair
 func (h *Handler) ListStudySessions(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pair := service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")}
+	sessions, total, err := h.groupService.ListStudySessions(auth.UserID(c), id, pair, listing.Options(c), page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, sessions, total, page)
+}
+
+// Create adds a new group for a language pair
+func (h *Handler) Create(c *gin.Context) {
+	var req createGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	pair := service.LanguagePair{Source: req.SourceLanguage, Target: req.TargetLanguage}
+	group, err := h.groupService.Create(req.Name, pair)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// Rename changes the name of a group
+func (h *Handler) Rename(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req groupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	group, err := h.groupService.Rename(id, req.Name)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// Delete removes a group along with its word links and study sessions
+func (h *Handler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	if err := h.groupService.Delete(id); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddWords adds existing words to a group
+func (h *Handler) AddWords(c *gin.Context) {
+	h.changeWords(c, h.groupService.AddWords)
+}
+
+// RemoveWords removes words from a group
+func (h *Handler) RemoveWords(c *gin.Context) {
+	h.changeWords(c, h.groupService.RemoveWords)
+}
+
+func (h *Handler) changeWords(c *gin.Context, change func(groupID int64, wordIDs []int64) (*models.Group, error)) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	var req groupWordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
 
-	sessions, total, err := h.groupService.ListStudySessions(id, page, perPage)
+	group, err := change(id, req.WordIDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": sessions,
-		"pagination": gin.H{
-			"current_page":   page,
-			"total_pages":    (total + perPage - 1) / perPage,
-			"total_items":    total,
-			"items_per_page": perPage,
-		},
-	})
+	c.JSON(http.StatusOK, group)
 }
@@ -1,12 +1,15 @@
 package groups
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
 	"github.com/gin-gonic/gin"
@@ -20,7 +23,7 @@ func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
 	handler := NewHandler(groupService)
 
 	r := gin.New()
-	api := r.Group("/api")
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, "admin@example.com", service.RoleAdmin)))
 	handler.RegisterRoutes(api)
 
 	return r, db
@@ -99,3 +102,268 @@ func TestListGroupWords(t *testing.T) {
 		t.Errorf("Expected 1 word, got %d", len(response.Items))
 	}
 }
+
+func TestListGroupsSorted(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('verbs'), ('Animals'), ('Greetings');
+		INSERT INTO words (parts) VALUES
+		('{"french":"chat","english":"cat"}'),
+		('{"french":"chien","english":"dog"}'),
+		('{"french":"bonjour","english":"hello"}');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 2), (2, 2), (3, 3);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/api/groups", "[1 2 3]"},
+		{"/api/groups?sort_by=name", "[2 3 1]"},
+		{"/api/groups?sort_by=words_count&order=desc", "[2 3 1]"},
+		{"/api/groups/2/words?sort_by=english&order=desc", "[2 1]"},
+	}
+
+	for _, tt := range tests {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", tt.path, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Items []struct {
+				ID int64 `json:"id"`
+			} `json:"items"`
+		}
+		testutil.ParseResponse(t, w, &response)
+
+		ids := []int64{}
+		for _, item := range response.Items {
+			ids = append(ids, item.ID)
+		}
+		if fmt.Sprint(ids) != tt.expected {
+			t.Errorf("GET %s: expected %s, got %v", tt.path, tt.expected, ids)
+		}
+	}
+
+	for _, path := range []string{"/api/groups?sort_by=french", "/api/groups?group_id=1", "/api/groups/2/study_sessions?group_id=1"} {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", path, nil))
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestGroupWordsCount(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES
+		('{"french":"bonjour","english":"hello"}'),
+		('{"french":"merci","english":"thank you"}');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	body := []byte(`{"name":"Greetings"}`)
+	req := httptest.NewRequest("POST", "/api/groups", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var group struct {
+		ID         int64  `json:"id"`
+		Name       string `json:"name"`
+		WordsCount int    `json:"words_count"`
+	}
+	testutil.ParseResponse(t, w, &group)
+
+	body = []byte(`{"word_ids":[1,2]}`)
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/groups/%d/words", group.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &group)
+
+	if group.WordsCount != 2 {
+		t.Errorf("Expected words_count 2 after adding, got %d", group.WordsCount)
+	}
+
+	body = []byte(`{"word_ids":[1]}`)
+	req = httptest.NewRequest("DELETE", fmt.Sprintf("/api/groups/%d/words", group.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &group)
+
+	if group.WordsCount != 1 {
+		t.Errorf("Expected words_count 1 after removing, got %d", group.WordsCount)
+	}
+
+	// Unknown word
+	body = []byte(`{"word_ids":[99]}`)
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/groups/%d/words", group.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestRenameAndDeleteGroup(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Old Name');
+		INSERT INTO words (parts) VALUES ('{"french":"bonjour","english":"hello"}');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	body := []byte(`{"name":"New Name"}`)
+	req := httptest.NewRequest("PUT", "/api/groups/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("DELETE", "/api/groups/1", nil)
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusNoContent, w.Code)
+
+	var words, links int
+	db.QueryRow("SELECT COUNT(*) FROM words").Scan(&words)
+	db.QueryRow("SELECT COUNT(*) FROM word_groups").Scan(&links)
+	if words != 1 || links != 0 {
+		t.Errorf("Expected the word to be kept and unlinked, got %d words and %d links", words, links)
+	}
+
+	req = httptest.NewRequest("GET", "/api/groups/1", nil)
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteGroupReschedulesWords(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Word 1 is also reviewed in Pets, word 2 only in Animals
+	testutil.InsertHistory(t, db, 1)
+	_, err := db.Exec(`
+		INSERT INTO word_review_states (user_id, word_id, repetitions, interval_days, due_at, last_reviewed_at) VALUES
+		(1, 1, 2, 6, '2025-01-09 10:00:00', '2025-01-03 10:00:00'),
+		(1, 2, 0, 1, '2025-01-03 10:00:00', '2025-01-02 10:00:00');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	req := httptest.NewRequest("DELETE", "/api/groups/1", nil)
+	testutil.CheckResponseCode(t, http.StatusNoContent, testutil.ExecuteRequest(r, req).Code)
+
+	var repetitions, intervalDays int
+	err = db.QueryRow("SELECT repetitions, interval_days FROM word_review_states WHERE user_id = 1 AND word_id = 1").Scan(&repetitions, &intervalDays)
+	if err != nil || repetitions != 1 || intervalDays != 1 {
+		t.Errorf("Expected word 1 to be scheduled from its one review left, got %d repetitions and %d days (%v)", repetitions, intervalDays, err)
+	}
+	if states := testutil.Count(t, db, "SELECT COUNT(*) FROM word_review_states WHERE word_id = 2"); states != 0 {
+		t.Errorf("Expected word 2 to have no schedule left, got %d", states)
+	}
+}
+
+func TestGroupLanguages(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Greetings');
+		INSERT INTO words (parts) VALUES ('{"french":"bonjour","english":"hello"}');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	body := []byte(`{"name":"Animals","source_language":"ja","target_language":"en"}`)
+	req := httptest.NewRequest("POST", "/api/groups", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/groups?source_language=ja", nil)
+	w = testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []models.Group `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Items) != 1 || response.Items[0].Name != "Animals" || response.Items[0].TargetLanguage != "en" {
+		t.Errorf("Expected only the Japanese group, got %+v", response.Items)
+	}
+
+	// French words cannot join a Japanese group
+	body = []byte(`{"word_ids":[1]}`)
+	req = httptest.NewRequest("POST", "/api/groups/2/words", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	body = []byte(`{"name":"Verbs","source_language":"xx"}`)
+	req = httptest.NewRequest("POST", "/api/groups", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestListGroupSessionsByLanguagePair(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name, source_language) VALUES ('Animals', 'ja');
+		INSERT INTO study_activities (name, url) VALUES ('Flashcards', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES
+		(1, 1, 1, '2025-01-10 09:00:00'),
+		(1, 1, 1, '2025-01-20 09:00:00');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"", "[2 1]"},
+		{"source_language=ja&target_language=en", "[2 1]"},
+		{"source_language=fr", "[]"},
+		{"target_language=fr", "[]"},
+	}
+	for _, tt := range tests {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/groups/1/study_sessions?"+tt.query, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Items []models.StudySession `json:"items"`
+		}
+		testutil.ParseResponse(t, w, &response)
+
+		ids := []int64{}
+		for _, item := range response.Items {
+			ids = append(ids, item.ID)
+		}
+		if fmt.Sprint(ids) != tt.expected {
+			t.Errorf("Sessions %q: expected %s, got %v", tt.query, tt.expected, ids)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package languages
+
+import (
+	"net/http"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	languageService *service.LanguageService
+}
+
+func NewHandler(languageService *service.LanguageService) *Handler {
+	return &Handler{
+		languageService: languageService,
+	}
+}
+
+// RegisterRoutes registers all routes for languages. Changing languages
+// requires the manage content permission.
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	languages := r.Group("/languages")
+	{
+		languages.GET("", h.List)
+		languages.GET("/:code", h.Get)
+	}
+
+	manage := r.Group("/languages", auth.Require(service.PermManageContent))
+	{
+		manage.POST("", h.Create)
+		manage.PUT("/:code", h.Update)
+		manage.DELETE("/:code", h.Delete)
+	}
+}
+
+type languageRequest struct {
+	Name   string                 `json:"name" binding:"required"`
+	Fields []models.LanguageField `json:"fields" binding:"required"`
+}
+
+// List returns every language with the fields of its words
+func (h *Handler) List(c *gin.Context) {
+	languages, err := h.languageService.List()
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": languages})
+}
+
+// Get returns a single language by code
+func (h *Handler) Get(c *gin.Context) {
+	language, err := h.languageService.Get(c.Param("code"))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	if language == nil {
+		apierror.NotFound(c, "language not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, language)
+}
+
+// Create adds a new language
+func (h *Handler) Create(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+		languageRequest
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	language, err := h.languageService.Create(models.Language{Code: req.Code, Name: req.Name, Fields: req.Fields})
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, language)
+}
+
+// Update replaces a language's name and fields
+func (h *Handler) Update(c *gin.Context) {
+	var req languageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	language, err := h.languageService.Update(c.Param("code"), models.Language{Name: req.Name, Fields: req.Fields})
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, language)
+}
+
+// Delete removes a language no word or group uses
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.languageService.Delete(c.Param("code")); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,99 @@
+package languages
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T, role string) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	languageService := service.NewLanguageService()
+	handler := NewHandler(languageService)
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, role+"@example.com", role)))
+	handler.RegisterRoutes(api)
+
+	return r, db
+}
+
+func TestListLanguages(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleLearner)
+	defer db.Close()
+
+	w := testutil.Request(r, "GET", "/api/languages", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []models.Language `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Items) != 3 {
+		t.Fatalf("Expected English, French and Japanese, got %+v", response.Items)
+	}
+
+	french := response.Items[1]
+	if french.Code != "fr" || len(french.Fields) != 1 || french.Fields[0].Name != "french" || !french.Fields[0].Required {
+		t.Errorf("Expected French with a required french field, got %+v", french)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(r, "GET", "/api/languages/xx", "").Code)
+	testutil.CheckResponseCode(t, http.StatusForbidden, testutil.Request(r, "DELETE", "/api/languages/ja", "").Code)
+}
+
+func TestCreateLanguage(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	body := `{"code":"de","name":"German","fields":[{"name":"german","required":true},{"name":"gender","values":["masculine","feminine","neuter"]}]}`
+	w := testutil.Request(r, "POST", "/api/languages", body)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var language models.Language
+	testutil.ParseResponse(t, w, &language)
+	if language.Code != "de" || len(language.Fields) != 2 || !language.Fields[0].Required {
+		t.Errorf("Expected German with two fields, got %+v", language)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusConflict, testutil.Request(r, "POST", "/api/languages", body).Code)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"bad code", `{"code":"German","name":"German","fields":[{"name":"german"}]}`},
+		{"no fields", `{"code":"it","name":"Italian","fields":[]}`},
+		{"bad field name", `{"code":"it","name":"Italian","fields":[{"name":"Italian word"}]}`},
+		{"duplicate field", `{"code":"it","name":"Italian","fields":[{"name":"italian"},{"name":"italian"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "POST", "/api/languages", tt.body).Code)
+		})
+	}
+
+	w = testutil.Request(r, "PUT", "/api/languages/de", `{"name":"Deutsch","fields":[{"name":"german","required":true}]}`)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &language)
+	if language.Name != "Deutsch" || len(language.Fields) != 1 {
+		t.Errorf("Expected the fields to be replaced, got %+v", language)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusNoContent, testutil.Request(r, "DELETE", "/api/languages/de", "").Code)
+
+	// French is used by words
+	_, err := db.Exec(`INSERT INTO words (parts) VALUES ('{"french":"chat","english":"cat"}')`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+	testutil.CheckResponseCode(t, http.StatusConflict, testutil.Request(r, "DELETE", "/api/languages/fr", "").Code)
+}
@@ -0,0 +1,22 @@
+// Package listing reads the sort and filter query parameters shared by the
+// list endpoints.
+package listing
+
+import (
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Options returns the sort_by, order, group_id, activity_id, from and to
+// query parameters of a list request. The service of the list checks them.
+func Options(c *gin.Context) service.ListOptions {
+	return service.ListOptions{
+		SortBy:     c.Query("sort_by"),
+		Order:      c.Query("order"),
+		GroupID:    c.Query("group_id"),
+		ActivityID: c.Query("activity_id"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+	}
+}
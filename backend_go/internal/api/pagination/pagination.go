@@ -0,0 +1,101 @@
+// Package pagination reads the page, per_page and cursor query parameters
+// of list endpoints and writes the envelope lists are returned in:
+//
+//	{"items": [...], "pagination": {"current_page": 1, "total_pages": 3, "total_items": 250, "items_per_page": 100}}
+//
+// Lists that grow without bound can also be paginated by cursor. Their
+// envelope links to the next page instead of counting pages:
+//
+//	{"items": [...], "pagination": {"items_per_page": 100, "next_cursor": "eyJhdCI6..."}}
+package pagination
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultPerPage is the page size when per_page is not given
+	DefaultPerPage = 100
+	// MaxPerPage bounds per_page
+	MaxPerPage = 500
+)
+
+// Page is the page of a list a request asks for
+type Page struct {
+	// Number counts pages from 1
+	Number  int
+	PerPage int
+	// ByCursor is set when the cursor query parameter is given, even if
+	// empty, to page by cursor instead of by number. Cursor is empty for
+	// the first page.
+	ByCursor bool
+	Cursor   string
+}
+
+// Parse returns the page a request asks for. page and per_page must be whole
+// numbers; pages before the first are clamped to 1 and per_page to between
+// 1 and MaxPerPage.
+func Parse(c *gin.Context) (Page, error) {
+	var fields []service.FieldError
+
+	number, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		fields = append(fields, service.FieldError{Field: "page", Message: "must be a whole number"})
+	}
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(DefaultPerPage)))
+	if err != nil {
+		fields = append(fields, service.FieldError{Field: "per_page", Message: "must be a whole number"})
+	}
+
+	if len(fields) > 0 {
+		messages := make([]string, len(fields))
+		for i, field := range fields {
+			messages[i] = field.Field + " " + field.Message
+		}
+		return Page{}, &service.ValidationError{Message: strings.Join(messages, "; "), Fields: fields}
+	}
+
+	cursor, byCursor := c.GetQuery("cursor")
+	return Page{
+		Number:   max(number, 1),
+		PerPage:  min(max(perPage, 1), MaxPerPage),
+		ByCursor: byCursor,
+		Cursor:   cursor,
+	}, nil
+}
+
+// Respond writes a page of items out of total in the pagination envelope
+func Respond(c *gin.Context, items interface{}, total int, page Page) {
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"pagination": gin.H{
+			"current_page":   page.Number,
+			"total_pages":    (total + page.PerPage - 1) / page.PerPage,
+			"total_items":    total,
+			"items_per_page": page.PerPage,
+		},
+	})
+}
+
+// RespondCursor writes a page of items paginated by cursor. next is the
+// cursor of the following page, or empty on the last page.
+func RespondCursor(c *gin.Context, items interface{}, next string, page Page) {
+	var nextCursor *string
+	if next != "" {
+		nextCursor = &next
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"pagination": gin.H{
+			"items_per_page": page.PerPage,
+			"next_cursor":    nextCursor,
+		},
+	})
+}
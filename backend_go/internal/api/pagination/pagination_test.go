@@ -0,0 +1,56 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parse(query string) (Page, error) {
+	gin.SetMode(gin.ReleaseMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/words?"+query, nil)
+	return Parse(c)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected Page
+	}{
+		{"", Page{Number: 1, PerPage: DefaultPerPage}},
+		{"page=3&per_page=20", Page{Number: 3, PerPage: 20}},
+		{"page=0&per_page=0", Page{Number: 1, PerPage: 1}},
+		{"page=-4&per_page=-1", Page{Number: 1, PerPage: 1}},
+		{"per_page=100000", Page{Number: 1, PerPage: MaxPerPage}},
+		{"cursor=", Page{Number: 1, PerPage: DefaultPerPage, ByCursor: true}},
+		{"cursor=abc&per_page=10", Page{Number: 1, PerPage: 10, ByCursor: true, Cursor: "abc"}},
+	}
+
+	for _, tt := range tests {
+		page, err := parse(tt.query)
+		if err != nil || page != tt.expected {
+			t.Errorf("Parse(%q): expected %+v, got %+v, %v", tt.query, tt.expected, page, err)
+		}
+	}
+
+	for _, query := range []string{"page=two", "per_page=1.5", "page=&per_page=10"} {
+		if _, err := parse(query); err == nil {
+			t.Errorf("Parse(%q): expected an error", query)
+		}
+	}
+}
+
+func TestRespond(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	Respond(c, []int{1, 2}, 5, Page{Number: 1, PerPage: 2})
+
+	expected := `{"items":[1,2],"pagination":{"current_page":1,"items_per_page":2,"total_items":5,"total_pages":3}}`
+	if w.Body.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, w.Body.String())
+	}
+}
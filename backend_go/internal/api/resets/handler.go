@@ -0,0 +1,87 @@
+package resets
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/pagination"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	historyResetService *service.HistoryResetService
+}
+
+func NewHandler(historyResetService *service.HistoryResetService) *Handler {
+	return &Handler{
+		historyResetService: historyResetService,
+	}
+}
+
+// RegisterRoutes registers the routes resetting and restoring the caller's
+// study history
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	resets := r.Group("/history_resets")
+	{
+		resets.GET("", h.List)
+		resets.POST("", h.Create)
+		resets.POST("/:id/restore", h.Restore)
+	}
+}
+
+// List returns a paginated list of the caller's history resets, newest
+// first
+func (h *Handler) List(c *gin.Context) {
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	resets, total, err := h.historyResetService.List(auth.UserID(c), page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, resets, total, page)
+}
+
+// Create archives the caller's study sessions in the scope of the body,
+// all of them when it is empty, with their reviews
+func (h *Handler) Create(c *gin.Context) {
+	var scope service.HistoryResetScope
+	if err := c.ShouldBindJSON(&scope); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	reset, err := h.historyResetService.Reset(auth.UserID(c), auth.UserID(c), scope)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reset)
+}
+
+// Restore puts the sessions and reviews archived by a reset back
+func (h *Handler) Restore(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	reset, err := h.historyResetService.Restore(auth.UserID(c), id)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reset)
+}
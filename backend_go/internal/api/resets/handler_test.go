@@ -0,0 +1,195 @@
+package resets
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	r := routerAs(testutil.CreateTestUser(t, db, "learner@example.com", service.RoleLearner))
+	return r, db
+}
+
+func routerAs(user *models.User) *gin.Engine {
+	handler := NewHandler(service.NewHistoryResetService())
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(user))
+	handler.RegisterRoutes(api)
+	return r
+}
+
+// insertHistory adds the history of testutil.InsertHistory for the learner
+// and schedules the words reviewed in it
+func insertHistory(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	testutil.InsertHistory(t, db, 1)
+	if _, err := service.NewSchedulerService().Replay(1, service.DefaultSchedulerName, nil); err != nil {
+		t.Fatalf("Failed to schedule the reviews: %v", err)
+	}
+}
+
+func TestResetAndRestore(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	insertHistory(t, db)
+	var repetitions int
+	db.QueryRow("SELECT repetitions FROM word_review_states WHERE word_id = 1").Scan(&repetitions)
+
+	w := testutil.PostJSON(r, "/api/history_resets", map[string]interface{}{"group_id": 1})
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var reset models.HistoryReset
+	testutil.ParseResponse(t, w, &reset)
+	if reset.SessionsCount != 2 || reset.ReviewsCount != 3 || reset.Status != service.ResetArchived {
+		t.Errorf("Expected 2 sessions and 3 reviews archived, got %+v", reset)
+	}
+	if reset.GroupID == nil || *reset.GroupID != 1 || reset.StudyActivityID != nil {
+		t.Errorf("Expected the group to be the scope, got %+v", reset)
+	}
+	if want := reset.CreatedAt.Add(service.HistoryResetRetention); !reset.ExpiresAt.Equal(want) {
+		t.Errorf("Expected the reset to expire at %s, got %s", want, reset.ExpiresAt)
+	}
+
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM study_sessions"); n != 1 {
+		t.Errorf("Expected the session of the other group to be kept, got %d sessions", n)
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM word_review_states WHERE word_id = 2"); n != 0 {
+		t.Errorf("Expected the schedule of a word with no reviews left to be deleted")
+	}
+	if n := testutil.Count(t, db, "SELECT repetitions FROM word_review_states WHERE word_id = 1"); n != 1 {
+		t.Errorf("Expected word 1 to be rescheduled from its remaining review, got %d repetitions", n)
+	}
+
+	w = testutil.PostJSON(r, fmt.Sprintf("/api/history_resets/%d/restore", reset.ID), nil)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &reset)
+	if reset.Status != service.ResetRestored || reset.RestoredAt == nil {
+		t.Errorf("Expected the reset to be restored, got %+v", reset)
+	}
+
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM word_review_items WHERE id IN (1, 2, 3) AND client_id IS NOT NULL"); n != 3 {
+		t.Errorf("Expected the reviews to be restored with their ids, got %d", n)
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM archived_study_sessions"); n != 0 {
+		t.Errorf("Expected the archive to be emptied, got %d sessions", n)
+	}
+	if n := testutil.Count(t, db, "SELECT repetitions FROM word_review_states WHERE word_id = 1"); n != repetitions {
+		t.Errorf("Expected word 1 to be rescheduled from all its reviews, got %d repetitions", n)
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM word_review_states WHERE word_id = 2"); n != 1 {
+		t.Errorf("Expected word 2 to be scheduled again")
+	}
+
+	w = testutil.PostJSON(r, fmt.Sprintf("/api/history_resets/%d/restore", reset.ID), nil)
+	testutil.CheckResponseCode(t, http.StatusConflict, w.Code)
+
+	// Someone else can't restore the learner's reset
+	other := routerAs(testutil.CreateTestUser(t, db, "other@example.com", service.RoleLearner))
+	w = testutil.PostJSON(r, "/api/history_resets", map[string]interface{}{})
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+	testutil.ParseResponse(t, w, &reset)
+	if reset.SessionsCount != 3 || reset.ReviewsCount != 4 {
+		t.Errorf("Expected an empty scope to archive the whole history, got %+v", reset)
+	}
+	w = testutil.PostJSON(other, fmt.Sprintf("/api/history_resets/%d/restore", reset.ID), nil)
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+
+	w = testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/history_resets", nil))
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	var response struct {
+		Items []models.HistoryReset `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Items) != 2 || response.Items[0].ID != reset.ID {
+		t.Errorf("Expected both resets newest first, got %+v", response.Items)
+	}
+}
+
+func TestResetScope(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	insertHistory(t, db)
+
+	w := testutil.PostJSON(r, "/api/history_resets", map[string]interface{}{"study_activity_id": 1, "from": "2025-01-02", "to": "2025-01-03"})
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var reset models.HistoryReset
+	testutil.ParseResponse(t, w, &reset)
+	if reset.SessionsCount != 1 || reset.ReviewsCount != 1 {
+		t.Errorf("Expected only the session of the activity on the last day, got %+v", reset)
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM study_sessions WHERE id = 3"); n != 0 {
+		t.Errorf("Expected session 3 to be archived")
+	}
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"unknown group", map[string]interface{}{"group_id": 99}},
+		{"unknown activity", map[string]interface{}{"study_activity_id": 99}},
+		{"invalid date", map[string]interface{}{"from": "yesterday"}},
+		{"empty range", map[string]interface{}{"from": "2025-01-03", "to": "2025-01-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testutil.PostJSON(r, "/api/history_resets", tt.body)
+			testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+		})
+	}
+
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM history_resets"); n != 1 {
+		t.Errorf("Expected invalid resets not to be recorded, got %d resets", n)
+	}
+}
+
+func TestPurgeExpired(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	insertHistory(t, db)
+
+	w := testutil.PostJSON(r, "/api/history_resets", map[string]interface{}{})
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+	var reset models.HistoryReset
+	testutil.ParseResponse(t, w, &reset)
+
+	resets := service.NewHistoryResetService()
+	if purged, err := resets.PurgeExpired(); err != nil || purged != 0 {
+		t.Fatalf("Expected nothing to be purged within the retention, got %d, %v", purged, err)
+	}
+
+	if _, err := db.Exec("UPDATE history_resets SET expires_at = ?", time.Now().UTC().Add(-time.Minute)); err != nil {
+		t.Fatalf("Failed to expire the reset: %v", err)
+	}
+	w = testutil.PostJSON(r, fmt.Sprintf("/api/history_resets/%d/restore", reset.ID), nil)
+	testutil.CheckResponseCode(t, http.StatusConflict, w.Code)
+
+	if purged, err := resets.PurgeExpired(); err != nil || purged != 1 {
+		t.Fatalf("Expected the expired reset to be purged, got %d, %v", purged, err)
+	}
+	for _, table := range []string{"history_resets", "archived_study_sessions", "archived_word_review_items"} {
+		if n := testutil.Count(t, db, "SELECT COUNT(*) FROM "+table); n != 0 {
+			t.Errorf("Expected %s to be empty, got %d rows", table, n)
+		}
+	}
+
+	w = testutil.PostJSON(r, fmt.Sprintf("/api/history_resets/%d/restore", reset.ID), nil)
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,92 @@
+package schedulers
+
+import (
+	"net/http"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	schedulerService *service.SchedulerService
+}
+
+func NewHandler(schedulerService *service.SchedulerService) *Handler {
+	return &Handler{
+		schedulerService: schedulerService,
+	}
+}
+
+// RegisterRoutes registers all routes for review schedulers. Changing the
+// global default requires the manage content permission.
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	schedulers := r.Group("/schedulers")
+	{
+		schedulers.GET("", h.List)
+		schedulers.POST("/:name/replay", h.Replay)
+	}
+
+	manage := r.Group("/schedulers", auth.Require(service.PermManageContent))
+	{
+		manage.PUT("/default", h.SetDefault)
+	}
+}
+
+// List returns the available schedulers and the global default
+func (h *Handler) List(c *gin.Context) {
+	schedulers, err := h.schedulerService.List()
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedulers)
+}
+
+// SetDefault changes the global default scheduler
+func (h *Handler) SetDefault(c *gin.Context) {
+	var req struct {
+		Scheduler string `json:"scheduler" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	schedulers, err := h.schedulerService.SetDefault(req.Scheduler)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedulers)
+}
+
+// Replay rebuilds word schedules from their review history with a scheduler
+func (h *Handler) Replay(c *gin.Context) {
+	var req struct {
+		WordIDs []int64 `json:"word_ids"`
+	}
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierror.Binding(c, err)
+			return
+		}
+	}
+
+	replayed, err := h.schedulerService.Replay(auth.UserID(c), c.Param("name"), req.WordIDs)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scheduler":      c.Param("name"),
+		"words_replayed": replayed,
+	})
+}
@@ -0,0 +1,141 @@
+package schedulers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	schedulerService := service.NewSchedulerService()
+	handler := NewHandler(schedulerService)
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, "admin@example.com", service.RoleAdmin)))
+	handler.RegisterRoutes(api)
+
+	return r, db
+}
+
+func TestSetDefaultScheduler(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	req := httptest.NewRequest("GET", "/api/schedulers", nil)
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response service.SchedulersResponse
+	testutil.ParseResponse(t, w, &response)
+
+	if response.Default != "sm2" || len(response.Items) != 3 {
+		t.Errorf("Expected sm2 default among 3 schedulers, got %+v", response)
+	}
+
+	body := []byte(`{"scheduler":"fsrs"}`)
+	req = httptest.NewRequest("PUT", "/api/schedulers/default", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &response)
+
+	if response.Default != "fsrs" {
+		t.Errorf("Expected fsrs default, got %s", response.Default)
+	}
+
+	body = []byte(`{"scheduler":"anki"}`)
+	req = httptest.NewRequest("PUT", "/api/schedulers/default", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestReplay(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Three passes followed by a fail
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"bonjour","english":"hello"}');
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id) VALUES (1, 1, 1);
+		INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) VALUES
+		(1, 1, true, '2025-01-01 10:00:00'),
+		(1, 1, true, '2025-01-02 10:00:00'),
+		(1, 1, true, '2025-01-04 10:00:00'),
+		(1, 1, false, '2025-01-08 10:00:00');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	// FSRS keeps some of the stability built up before the lapse
+	expectedInterval := map[string]int{"leitner": 1, "sm2": 1, "fsrs": 3}
+
+	for _, name := range []string{"leitner", "sm2", "fsrs"} {
+		req := httptest.NewRequest("POST", "/api/schedulers/"+name+"/replay", nil)
+		w := testutil.ExecuteRequest(r, req)
+
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var response struct {
+			WordsReplayed int `json:"words_replayed"`
+		}
+		testutil.ParseResponse(t, w, &response)
+
+		if response.WordsReplayed != 1 {
+			t.Errorf("%s: expected 1 word replayed, got %d", name, response.WordsReplayed)
+		}
+
+		var scheduler string
+		var box, lapses, intervalDays int
+		err := db.QueryRow(`
+			SELECT scheduler, box, lapses, interval_days FROM word_review_states WHERE word_id = 1
+		`).Scan(&scheduler, &box, &lapses, &intervalDays)
+		if err != nil {
+			t.Fatalf("%s: expected a review state: %v", name, err)
+		}
+
+		if scheduler != name || lapses != 1 || intervalDays != expectedInterval[name] {
+			t.Errorf("%s: expected 1 lapse and a %d day interval, got scheduler %s, %d lapses, %d days", name, expectedInterval[name], scheduler, lapses, intervalDays)
+		}
+		if name == "leitner" && box != 1 {
+			t.Errorf("Expected the word back in box 1, got box %d", box)
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/api/schedulers/anki/replay", nil)
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+
+	// Unknown words are listed rather than skipped
+	req = httptest.NewRequest("POST", "/api/schedulers/sm2/replay", bytes.NewBufferString(`{"word_ids":[1,99,100]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	var body apierror.Body
+	testutil.ParseResponse(t, w, &body)
+	fields, _ := json.Marshal(body.Fields)
+	expected := `[{"field":"word_ids[1]","message":"word 99 does not exist"},` +
+		`{"field":"word_ids[2]","message":"word 100 does not exist"}]`
+	if string(fields) != expected {
+		t.Errorf("Expected field errors %s, got %s", expected, fields)
+	}
+}
@@ -0,0 +1,43 @@
+package schemas
+
+import (
+	"net/http"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	schemaService *service.SchemaService
+}
+
+func NewHandler(schemaService *service.SchemaService) *Handler {
+	return &Handler{
+		schemaService: schemaService,
+	}
+}
+
+// RegisterRoutes registers the schema routes
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/schemas/:name", h.Get)
+}
+
+// Get returns the JSON Schema of word parts with the given name: a language
+// code such as fr, a language pair such as fr_en or a group such as group_12
+func (h *Handler) Get(c *gin.Context) {
+	schema, err := h.schemaService.Get(c.Param("name"))
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	if schema == nil {
+		apierror.NotFound(c, "schema not found")
+		return
+	}
+
+	c.Header("Content-Type", "application/schema+json")
+	c.JSON(http.StatusOK, schema)
+}
@@ -0,0 +1,77 @@
+package schemas
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	schemaService := service.NewSchemaService()
+	handler := NewHandler(schemaService)
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, "admin@example.com", service.RoleAdmin)))
+	handler.RegisterRoutes(api)
+
+	return r, db
+}
+
+func getSchema(t *testing.T, r *gin.Engine, name string) (*httptest.ResponseRecorder, service.JSONSchema) {
+	t.Helper()
+
+	w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/schemas/"+name, nil))
+
+	var schema service.JSONSchema
+	if w.Code == http.StatusOK {
+		testutil.ParseResponse(t, w, &schema)
+	}
+	return w, schema
+}
+
+func TestGetSchema(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO groups (name, source_language, target_language) VALUES ('Animals', 'ja', 'en')`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	w, schema := getSchema(t, r, "fr")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	if schema.Type != "object" || len(schema.Required) != 1 || schema.Required[0] != "french" {
+		t.Errorf("Expected french to be the only required field, got %+v", schema)
+	}
+	if french := schema.Properties["french"]; french == nil || french.Pattern == "" || french.Language != "fr" {
+		t.Errorf("Expected french to be a required French field, got %+v", french)
+	}
+
+	w, schema = getSchema(t, r, "fr_en")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	if len(schema.Properties) != 2 || len(schema.Required) != 2 || *schema.AdditionalProperties {
+		t.Errorf("Expected the fields of both languages and nothing else, got %+v", schema)
+	}
+
+	w, schema = getSchema(t, r, "group_1")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	order, _ := json.Marshal(schema.Order)
+	if schema.ID != "/api/schemas/group_1" || string(order) != `["kanji","reading","romaji","english"]` {
+		t.Errorf("Expected the Japanese to English fields in order, got %s %s", schema.ID, order)
+	}
+
+	for _, name := range []string{"xx", "fr_xx", "en_en", "group_2", "group_x"} {
+		w, _ := getSchema(t, r, name)
+		testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+	}
+}
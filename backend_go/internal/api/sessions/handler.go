@@ -1,9 +1,16 @@
 package sessions
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/listing"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/pagination"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -19,7 +26,8 @@ func NewHandler(sessionService *service.SessionService) *Handler {
 	}
 }
 
-// RegisterRoutes registers all routes for sessions
+// RegisterRoutes registers all routes for sessions and the word reviews
+// recorded in them
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	sessions := r.Group("/study_sessions")
 	{
@@ -28,29 +36,144 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 		sessions.GET("/:id/words", h.ListWords)
 		sessions.POST("", h.Create)
 		sessions.POST("/:id/word/:word_id/review", h.ReviewWord)
+		sessions.POST("/:id/reviews", h.ReviewBatch)
+		sessions.POST("/:id/end", h.End)
+		sessions.POST("/:id/pause", h.Pause)
+		sessions.POST("/:id/resume", h.Resume)
 	}
+
+	r.GET("/word_review_items", h.ListReviews)
+}
+
+// reviewRequest is the body of a word review. Exactly one of quality (0-5),
+// grade (again/hard/good/easy) or the legacy correct flag must be given.
+type reviewRequest struct {
+	Quality        *int   `json:"quality"`
+	Grade          string `json:"grade"`
+	Correct        *bool  `json:"correct"`
+	Answer         string `json:"answer"`
+	ResponseTimeMs *int   `json:"response_time_ms"`
+	Direction      string `json:"direction"`
 }
 
-// List returns a paginated list of study sessions
+func (r reviewRequest) toInput() (service.ReviewInput, error) {
+	input := service.ReviewInput{
+		Answer:         r.Answer,
+		ResponseTimeMs: r.ResponseTimeMs,
+		Direction:      r.Direction,
+	}
+
+	given := 0
+	if r.Quality != nil {
+		input.Quality = *r.Quality
+		given++
+	}
+	if r.Grade != "" {
+		quality, ok := service.QualityFromGrade(r.Grade)
+		if !ok {
+			return input, errors.New("grade must be one of again, hard, good or easy")
+		}
+		input.Quality = quality
+		given++
+	}
+	if r.Correct != nil {
+		input.Quality = 1
+		if *r.Correct {
+			input.Quality = 4
+		}
+		given++
+	}
+
+	if given != 1 {
+		return input, errors.New("exactly one of quality, grade or correct is required")
+	}
+
+	return input, nil
+}
+
+// batchReviewRequest is the body of a batch review submission. Each review
+// carries a client generated id so that resent batches are only stored once.
+type batchReviewRequest struct {
+	Reviews []struct {
+		ID         string     `json:"id"`
+		WordID     int64      `json:"word_id"`
+		ReviewedAt *time.Time `json:"reviewed_at"`
+		reviewRequest
+	} `json:"reviews" binding:"required"`
+}
+
+// List returns a paginated list of study sessions, optionally of one
+// language pair
 func (h *Handler) List(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pair := service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")}
+
+	if page.ByCursor {
+		sessions, next, err := h.sessionService.ListAfter(auth.UserID(c), pair, listing.Options(c), page.Cursor, page.PerPage)
+		if err != nil {
+			apierror.Respond(c, err)
+			return
+		}
+
+		pagination.RespondCursor(c, sessions, next, page)
+		return
+	}
 
-	sessions, total, err := h.sessionService.List(page, perPage)
+	sessions, total, err := h.sessionService.List(auth.UserID(c), pair, listing.Options(c), page.Number, page.PerPage)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": sessions,
-		"pagination": gin.H{
-			"current_page":   page,
-			"total_pages":    (total + perPage - 1) / perPage,
-			"total_items":    total,
-			"items_per_page": perPage,
-		},
-	})
+	pagination.Respond(c, sessions, total, page)
+}
+
+// ListReviews returns the caller's word reviews, newest first, optionally
+// of one word or study session. Reviews can be paginated by cursor.
+func (h *Handler) ListReviews(c *gin.Context) {
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	var filter service.ReviewFilter
+	if value := c.Query("word_id"); value != "" {
+		if filter.WordID, err = strconv.ParseInt(value, 10, 64); err != nil {
+			apierror.BadRequest(c, "invalid word_id")
+			return
+		}
+	}
+	if value := c.Query("study_session_id"); value != "" {
+		if filter.StudySessionID, err = strconv.ParseInt(value, 10, 64); err != nil {
+			apierror.BadRequest(c, "invalid study_session_id")
+			return
+		}
+	}
+
+	if page.ByCursor {
+		reviews, next, err := h.sessionService.ListReviewsAfter(auth.UserID(c), filter, page.Cursor, page.PerPage)
+		if err != nil {
+			apierror.Respond(c, err)
+			return
+		}
+
+		pagination.RespondCursor(c, reviews, next, page)
+		return
+	}
+
+	reviews, total, err := h.sessionService.ListReviews(auth.UserID(c), filter, page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, reviews, total, page)
 }
 
 // Create creates a new study session
@@ -61,13 +184,13 @@ func (h *Handler) Create(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Binding(c, err)
 		return
 	}
 
-	session, err := h.sessionService.Create(req.GroupID, req.StudyActivityID)
+	session, err := h.sessionService.Create(auth.UserID(c), req.GroupID, req.StudyActivityID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
@@ -78,50 +201,123 @@ func (h *Handler) Create(c *gin.Context) {
 func (h *Handler) ReviewWord(c *gin.Context) {
 	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		apierror.BadRequest(c, "invalid session id")
 		return
 	}
 
 	wordID, err := strconv.ParseInt(c.Param("word_id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+		apierror.BadRequest(c, "invalid word id")
 		return
 	}
 
-	var req struct {
-		Correct bool `json:"correct" binding:"required"`
+	var req reviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	input, err := req.toInput()
+	if err != nil {
+		apierror.Validation(c, err.Error())
 		return
 	}
 
-	review, err := h.sessionService.ReviewWord(sessionID, wordID, req.Correct)
+	review, err := h.sessionService.ReviewWord(auth.UserID(c), sessionID, wordID, input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, review)
 }
 
+// ReviewBatch records several word reviews of a study session at once
+func (h *Handler) ReviewBatch(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid session id")
+		return
+	}
+
+	var req batchReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	reviews := make([]service.BatchReview, len(req.Reviews))
+	for i, review := range req.Reviews {
+		input, err := review.toInput()
+		if err != nil {
+			apierror.Validation(c, fmt.Sprintf("reviews[%d]: %v", i, err))
+			return
+		}
+
+		reviews[i] = service.BatchReview{
+			ClientID:   review.ID,
+			WordID:     review.WordID,
+			ReviewedAt: review.ReviewedAt,
+			Input:      input,
+		}
+	}
+
+	response, err := h.sessionService.ReviewBatch(auth.UserID(c), sessionID, reviews)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// End completes a study session
+func (h *Handler) End(c *gin.Context) {
+	h.transition(c, h.sessionService.End)
+}
+
+// Pause pauses an active study session
+func (h *Handler) Pause(c *gin.Context) {
+	h.transition(c, h.sessionService.Pause)
+}
+
+// Resume resumes a paused study session
+func (h *Handler) Resume(c *gin.Context) {
+	h.transition(c, h.sessionService.Resume)
+}
+
+func (h *Handler) transition(c *gin.Context, change func(userID, id int64) (*service.SessionResponse, error)) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	session, err := change(auth.UserID(c), id)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
 // Get returns a single study session
 func (h *Handler) Get(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		apierror.BadRequest(c, "invalid id")
 		return
 	}
 
-	session, err := h.sessionService.Get(id)
+	session, err := h.sessionService.Get(auth.UserID(c), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
 	if session == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		apierror.NotFound(c, "session not found")
 		return
 	}
 
@@ -132,26 +328,21 @@ func (h *Handler) Get(c *gin.Context) {
 func (h *Handler) ListWords(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		apierror.BadRequest(c, "invalid id")
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
 
-	words, total, err := h.sessionService.ListWords(id, page, perPage)
+	words, total, err := h.sessionService.ListWords(auth.UserID(c), id, listing.Options(c), page.Number, page.PerPage)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": words,
-		"pagination": gin.H{
-			"current_page":   page,
-			"total_pages":    (total + perPage - 1) / perPage,
-			"total_items":    total,
-			"items_per_page": perPage,
-		},
-	})
+	pagination.Respond(c, words, total, page)
 }
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
 	"github.com/gin-gonic/gin"
@@ -22,7 +26,7 @@ func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
 	handler := NewHandler(sessionService)
 
 	r := gin.New()
-	api := r.Group("/api")
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, "admin@example.com", service.RoleAdmin)))
 	handler.RegisterRoutes(api)
 
 	return r, db
@@ -65,6 +69,41 @@ func TestCreateSession(t *testing.T) {
 	}
 }
 
+func TestSessionTimestampsAreUTC(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	local := time.Local
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+	defer func() { time.Local = local }()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/study_sessions", bytes.NewBufferString(`{"group_id":1,"study_activity_id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	testutil.CheckResponseCode(t, http.StatusCreated, testutil.ExecuteRequest(r, req).Code)
+
+	req = httptest.NewRequest("POST", "/api/study_sessions/1/pause", nil)
+	testutil.CheckResponseCode(t, http.StatusOK, testutil.ExecuteRequest(r, req).Code)
+
+	var createdAt, lastActivityAt string
+	err = db.QueryRow("SELECT CAST(created_at AS TEXT), CAST(last_activity_at AS TEXT) FROM study_sessions WHERE id = 1").Scan(&createdAt, &lastActivityAt)
+	if err != nil {
+		t.Fatalf("Failed to read the session: %v", err)
+	}
+	for _, stored := range []string{createdAt, lastActivityAt} {
+		if !strings.HasSuffix(stored, "+00:00") {
+			t.Errorf("Expected a UTC timestamp, got %s", stored)
+		}
+	}
+}
+
 func TestReviewWord(t *testing.T) {
 	r, db := setupTestRouter(t)
 	defer db.Close()
@@ -75,8 +114,8 @@ func TestReviewWord(t *testing.T) {
 		INSERT INTO groups (name) VALUES ('Test Group');
 		INSERT INTO study_activities (name, url, thumbnail_url, description) 
 		VALUES ('Test Activity', 'http://test.com', 'http://test.com/thumb.jpg', 'Test Description');
-		INSERT INTO study_sessions (group_id, study_activity_id, created_at) 
-		VALUES (1, 1, CURRENT_TIMESTAMP);
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) 
+		VALUES (1, 1, 1, CURRENT_TIMESTAMP);
 	`)
 	if err != nil {
 		t.Fatalf("Failed to insert test data: %v", err)
@@ -108,6 +147,17 @@ func TestReviewWord(t *testing.T) {
 	if !response.Correct {
 		t.Error("Expected review to be correct")
 	}
+
+	var repetitions, intervalDays int
+	err = db.QueryRow(`
+		SELECT repetitions, interval_days FROM word_review_states WHERE word_id = 1
+	`).Scan(&repetitions, &intervalDays)
+	if err != nil {
+		t.Fatalf("Expected a review state for the word: %v", err)
+	}
+	if repetitions != 1 || intervalDays != 1 {
+		t.Errorf("Expected 1 repetition with a 1 day interval, got %d and %d", repetitions, intervalDays)
+	}
 }
 
 func TestListSessionWords(t *testing.T) {
@@ -120,8 +170,8 @@ func TestListSessionWords(t *testing.T) {
 		INSERT INTO groups (name) VALUES ('Test Group');
 		INSERT INTO study_activities (name, url, thumbnail_url, description) 
 		VALUES ('Test Activity', 'http://test.com', 'http://test.com/thumb.jpg', 'Test Description');
-		INSERT INTO study_sessions (group_id, study_activity_id, created_at) 
-		VALUES (1, 1, datetime('now'));
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) 
+		VALUES (1, 1, 1, datetime('now'));
 		INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) 
 		VALUES (1, 1, true, datetime('now'));
 	`)
@@ -147,3 +197,649 @@ func TestListSessionWords(t *testing.T) {
 		t.Errorf("Expected 1 word, got %d", len(response.Items))
 	}
 }
+
+func TestReviewWordUsesActivityScheduler(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// The word was previously scheduled with SM-2
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"test","english":"test"}');
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url, scheduler) VALUES ('Test Activity', 'http://test.com', 'leitner');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES (1, 1, 1, datetime('now'));
+		INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) VALUES (1, 1, true, datetime('now', '-1 day'));
+		INSERT INTO word_review_states (user_id, word_id, scheduler, repetitions, interval_days, due_at) VALUES (1, 1, 'sm2', 1, 1, datetime('now'));
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"correct": true})
+	req := httptest.NewRequest("POST", "/api/study_sessions/1/word/1/review", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	// Both reviews are replayed through Leitner
+	var scheduler string
+	var box int
+	err = db.QueryRow("SELECT scheduler, box FROM word_review_states WHERE word_id = 1").Scan(&scheduler, &box)
+	if err != nil {
+		t.Fatalf("Expected a review state for the word: %v", err)
+	}
+	if scheduler != "leitner" || box != 2 {
+		t.Errorf("Expected leitner box 2, got %s box %d", scheduler, box)
+	}
+}
+
+func TestReviewWordGraded(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Insert prerequisite data
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"test","english":"test"}');
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES (1, 1, 1, CURRENT_TIMESTAMP);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		code       int
+		correct    bool
+		quality    int
+		answer     string
+		responseMs int
+		direction  string
+	}{
+		{"wrong legacy answer", `{"correct":false}`, http.StatusOK, false, 1, "", 0, ""},
+		{"grade", `{"grade":"hard","answer":"tset","response_time_ms":2300,"direction":"production"}`, http.StatusOK, true, 3, "tset", 2300, "production"},
+		{"quality", `{"quality":0}`, http.StatusOK, false, 0, "", 0, ""},
+		{"quality out of range", `{"quality":7}`, http.StatusUnprocessableEntity, false, 0, "", 0, ""},
+		{"unknown grade", `{"grade":"meh"}`, http.StatusUnprocessableEntity, false, 0, "", 0, ""},
+		{"unknown direction", `{"grade":"good","direction":"sideways"}`, http.StatusUnprocessableEntity, false, 0, "", 0, ""},
+		{"no outcome", `{"answer":"test"}`, http.StatusUnprocessableEntity, false, 0, "", 0, ""},
+		{"two outcomes", `{"grade":"good","correct":true}`, http.StatusUnprocessableEntity, false, 0, "", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/study_sessions/1/word/1/review", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := testutil.ExecuteRequest(r, req)
+
+			testutil.CheckResponseCode(t, tt.code, w.Code)
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Correct        bool   `json:"correct"`
+				Quality        int    `json:"quality"`
+				Answer         string `json:"answer"`
+				ResponseTimeMs int    `json:"response_time_ms"`
+				Direction      string `json:"direction"`
+			}
+			testutil.ParseResponse(t, w, &response)
+
+			if response.Correct != tt.correct || response.Quality != tt.quality {
+				t.Errorf("Expected correct=%v quality=%d, got correct=%v quality=%d", tt.correct, tt.quality, response.Correct, response.Quality)
+			}
+			if response.Answer != tt.answer || response.ResponseTimeMs != tt.responseMs || response.Direction != tt.direction {
+				t.Errorf("Unexpected answer details %+v", response)
+			}
+		})
+	}
+
+	var reviews int
+	db.QueryRow("SELECT COUNT(*) FROM word_review_items").Scan(&reviews)
+	if reviews != 3 {
+		t.Errorf("Expected 3 recorded reviews, got %d", reviews)
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Insert prerequisite data
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"test","english":"test"}');
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES (1, 1, 1, datetime('now', '-10 minutes'));
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return testutil.ExecuteRequest(r, req)
+	}
+
+	w := post("/api/study_sessions/1/pause", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var session service.SessionResponse
+	testutil.ParseResponse(t, w, &session)
+	if session.Status != service.SessionPaused {
+		t.Errorf("Expected status paused, got %s", session.Status)
+	}
+	if session.DurationSeconds < 590 || session.DurationSeconds > 610 {
+		t.Errorf("Expected about 600 active seconds, got %d", session.DurationSeconds)
+	}
+	if session.EndTime != nil {
+		t.Errorf("Expected no end time for a paused session, got %s", *session.EndTime)
+	}
+
+	// Reviews are rejected while paused
+	w = post("/api/study_sessions/1/word/1/review", `{"grade":"good"}`)
+	testutil.CheckResponseCode(t, http.StatusConflict, w.Code)
+
+	w = post("/api/study_sessions/1/pause", "")
+	testutil.CheckResponseCode(t, http.StatusConflict, w.Code)
+
+	w = post("/api/study_sessions/1/resume", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	w = post("/api/study_sessions/1/word/1/review", `{"grade":"good"}`)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	w = post("/api/study_sessions/1/end", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	session = service.SessionResponse{}
+	testutil.ParseResponse(t, w, &session)
+	if session.Status != service.SessionCompleted {
+		t.Errorf("Expected status completed, got %s", session.Status)
+	}
+	if session.EndTime == nil {
+		t.Error("Expected an end time for a completed session")
+	}
+	if session.ReviewItemsCount != 1 {
+		t.Errorf("Expected 1 review item, got %d", session.ReviewItemsCount)
+	}
+
+	w = post("/api/study_sessions/1/word/1/review", `{"grade":"good"}`)
+	testutil.CheckResponseCode(t, http.StatusConflict, w.Code)
+
+	w = post("/api/study_sessions/1/end", "")
+	testutil.CheckResponseCode(t, http.StatusConflict, w.Code)
+
+	w = post("/api/study_sessions/99/end", "")
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+
+	w = post("/api/study_sessions/99/word/1/review", `{"grade":"good"}`)
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+}
+
+func TestSweepStaleSessions(t *testing.T) {
+	_, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Session 1 went idle an hour ago after 15 active minutes, session 2 is
+	// still in use and session 3 was paused an hour ago
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at, last_activity_at)
+		VALUES (1, 1, 1, datetime('now', '-75 minutes'), datetime('now', '-60 minutes'));
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at, last_activity_at)
+		VALUES (1, 1, 1, datetime('now', '-75 minutes'), datetime('now', '-1 minutes'));
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at, status, last_activity_at)
+		VALUES (1, 1, 1, datetime('now', '-75 minutes'), 'paused', datetime('now', '-60 minutes'));
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	sessionService := service.NewSessionService()
+	abandoned, err := sessionService.SweepStale(30*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if abandoned != 1 {
+		t.Errorf("Expected 1 abandoned session, got %d", abandoned)
+	}
+
+	expected := map[int64]string{
+		1: service.SessionAbandoned,
+		2: service.SessionActive,
+		3: service.SessionPaused,
+	}
+	for id, status := range expected {
+		session, err := sessionService.Get(1, id)
+		if err != nil || session == nil {
+			t.Fatalf("Failed to get session %d: %v", id, err)
+		}
+		if session.Status != status {
+			t.Errorf("Expected session %d to be %s, got %s", id, status, session.Status)
+		}
+	}
+
+	session, _ := sessionService.Get(1, 1)
+	if session.DurationSeconds != 15*60 {
+		t.Errorf("Expected 900 active seconds up to the last activity, got %d", session.DurationSeconds)
+	}
+	if session.EndTime == nil {
+		t.Error("Expected an end time for an abandoned session")
+	}
+}
+
+func TestReviewBatch(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Insert prerequisite data
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"un","english":"one"}');
+		INSERT INTO words (parts) VALUES ('{"french":"deux","english":"two"}');
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES (1, 1, 1, CURRENT_TIMESTAMP);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return testutil.ExecuteRequest(r, req)
+	}
+
+	reviewedAt := time.Now().Add(-5 * time.Minute).UTC()
+	at := func(offset time.Duration) string {
+		return reviewedAt.Add(offset).Format(time.RFC3339)
+	}
+	first := `{"reviews": [
+		{"id": "a", "word_id": 1, "grade": "good", "reviewed_at": "` + at(0) + `"},
+		{"id": "b", "word_id": 2, "correct": false, "reviewed_at": "` + at(time.Second) + `"},
+		{"id": "c", "word_id": 1, "quality": 5, "reviewed_at": "` + at(2*time.Second) + `"}
+	]}`
+
+	var response service.BatchReviewResponse
+	w := post("/api/study_sessions/1/reviews", first)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &response)
+
+	if response.Created != 3 || response.Duplicates != 0 || len(response.Items) != 3 {
+		t.Fatalf("Expected 3 created reviews, got %+v", response)
+	}
+	if response.Items[1].ClientID != "b" || response.Items[1].Correct {
+		t.Errorf("Expected review b to be stored as wrong, got %+v", response.Items[1])
+	}
+
+	// Resending the batch with one new review only stores the new one
+	resent := strings.Replace(first, `]}`, `,{"id": "d", "word_id": 2, "grade": "easy"}]}`, 1)
+	response = service.BatchReviewResponse{}
+	w = post("/api/study_sessions/1/reviews", resent)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &response)
+
+	if response.Created != 1 || response.Duplicates != 3 || len(response.Items) != 4 {
+		t.Errorf("Expected 1 created and 3 duplicate reviews, got %+v", response)
+	}
+
+	var reviews, repetitions int
+	db.QueryRow("SELECT COUNT(*) FROM word_review_items").Scan(&reviews)
+	if reviews != 4 {
+		t.Errorf("Expected 4 stored reviews, got %d", reviews)
+	}
+	db.QueryRow("SELECT repetitions FROM word_review_states WHERE word_id = 1").Scan(&repetitions)
+	if repetitions != 2 {
+		t.Errorf("Expected word 1 to have 2 repetitions, got %d", repetitions)
+	}
+
+	// Replays are still accepted once the session has ended, new reviews are not
+	w = post("/api/study_sessions/1/end", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	w = post("/api/study_sessions/1/reviews", resent)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	w = post("/api/study_sessions/1/reviews", `{"reviews": [{"id": "e", "word_id": 1, "grade": "good"}]}`)
+	testutil.CheckResponseCode(t, http.StatusConflict, w.Code)
+
+	invalid := []string{
+		`{"reviews": []}`,
+		`{"reviews": [{"word_id": 1, "grade": "good"}]}`,
+		`{"reviews": [{"id": "x", "word_id": 1, "grade": "good"}, {"id": "x", "word_id": 2, "grade": "good"}]}`,
+		`{"reviews": [{"id": "x", "word_id": 1}]}`,
+		`{"reviews": [{"id": "x", "word_id": 1, "grade": "good", "reviewed_at": "` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}]}`,
+	}
+	for _, body := range invalid {
+		w = post("/api/study_sessions/1/reviews", body)
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	}
+
+	w = post("/api/study_sessions/99/reviews", `{"reviews": [{"id": "a", "word_id": 1, "grade": "good"}]}`)
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateSessionRequiresExistingReferences(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown group", `{"group_id": 42, "study_activity_id": 1}`},
+		{"unknown activity", `{"group_id": 1, "study_activity_id": 42}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/study_sessions", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := testutil.ExecuteRequest(r, req)
+
+			testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+			var response apierror.Body
+			testutil.ParseResponse(t, w, &response)
+			if response.Code != apierror.CodeValidation || response.Error == "" {
+				t.Errorf("Expected a validation error body, got %+v", response)
+			}
+		})
+	}
+
+	var sessions int
+	db.QueryRow("SELECT COUNT(*) FROM study_sessions").Scan(&sessions)
+	if sessions != 0 {
+		t.Errorf("Expected no sessions to be created, got %d", sessions)
+	}
+
+	// The database rejects dangling references that bypass the service
+	_, err = db.Exec("INSERT INTO study_sessions (user_id, group_id, study_activity_id) VALUES (1, 42, 1)")
+	if err == nil {
+		t.Error("Expected the foreign key constraint to reject the session")
+	}
+}
+
+func TestReviewWordNotFound(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"test","english":"test"}');
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES (1, 1, 1, CURRENT_TIMESTAMP);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	for _, path := range []string{
+		"/api/study_sessions/99/word/1/review",
+		"/api/study_sessions/1/word/99/review",
+	} {
+		req := httptest.NewRequest("POST", path, bytes.NewBufferString(`{"grade":"good"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := testutil.ExecuteRequest(r, req)
+
+		testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+
+		var response apierror.Body
+		testutil.ParseResponse(t, w, &response)
+		if response.Code != apierror.CodeNotFound {
+			t.Errorf("Expected code %s, got %s", apierror.CodeNotFound, response.Code)
+		}
+	}
+}
+
+func TestListSessionsSortedAndFiltered(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Greetings'), ('Verbs');
+		INSERT INTO study_activities (name, url) VALUES ('Flashcards', 'http://test.com'), ('Typing', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES
+		(1, 1, 1, '2025-01-10 09:00:00'),
+		(1, 2, 1, '2025-01-20 09:00:00'),
+		(1, 1, 2, '2025-02-01 09:00:00');
+		INSERT INTO words (parts) VALUES ('{"french":"bonjour","english":"hello"}');
+		INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 1, true), (1, 1, false), (1, 2, true);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	list := func(query string) []int64 {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/study_sessions?"+query, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Items []service.SessionResponse `json:"items"`
+		}
+		testutil.ParseResponse(t, w, &response)
+
+		ids := []int64{}
+		for _, session := range response.Items {
+			ids = append(ids, session.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"", "[3 2 1]"},
+		{"order=asc", "[1 2 3]"},
+		{"sort_by=review_items_count", "[1 2 3]"},
+		{"sort_by=group_name&order=asc", "[1 3 2]"},
+		{"group_id=1", "[3 1]"},
+		{"activity_id=1&group_id=2", "[2]"},
+		{"from=2025-01-15&to=2025-01-31", "[2]"},
+		{"to=2025-01-10", "[1]"},
+		{"from=2025-01-20T10:00:00%2B02:00", "[3 2]"},
+	}
+
+	for _, tt := range tests {
+		if ids := fmt.Sprint(list(tt.query)); ids != tt.expected {
+			t.Errorf("List %q: expected %s, got %s", tt.query, tt.expected, ids)
+		}
+	}
+
+	for _, query := range []string{"sort_by=french", "order=up", "group_id=one", "from=yesterday"} {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/study_sessions?"+query, nil))
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestListSessionsByCursor(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Greetings');
+		INSERT INTO study_activities (name, url) VALUES ('Flashcards', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES
+		(1, 1, 1, '2025-01-10 09:00:00'),
+		(1, 1, 1, '2025-01-20 09:00:00'),
+		(1, 1, 1, '2025-01-20 09:00:00'),
+		(1, 1, 1, '2025-01-05 09:00:00'),
+		(1, 1, 1, '2025-02-01 09:00:00');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	type cursorPage struct {
+		Items      []service.SessionResponse `json:"items"`
+		Pagination struct {
+			ItemsPerPage int     `json:"items_per_page"`
+			NextCursor   *string `json:"next_cursor"`
+		} `json:"pagination"`
+	}
+
+	var ids []int64
+	path := "/api/study_sessions?per_page=2&cursor="
+	for pages := 0; ; pages++ {
+		if pages == 3 {
+			t.Fatalf("Expected three pages, got more")
+		}
+
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", path, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var page cursorPage
+		testutil.ParseResponse(t, w, &page)
+		for _, session := range page.Items {
+			ids = append(ids, session.ID)
+		}
+
+		// A session created while paging does not shift later pages
+		db.Exec("INSERT INTO study_sessions (user_id, group_id, study_activity_id) VALUES (1, 1, 1)")
+
+		if page.Pagination.NextCursor == nil {
+			break
+		}
+		path = "/api/study_sessions?per_page=2&cursor=" + *page.Pagination.NextCursor
+	}
+
+	if fmt.Sprint(ids) != "[5 3 2 1 4]" {
+		t.Errorf("Expected every session newest first, got %v", ids)
+	}
+
+	for _, query := range []string{"cursor=nonsense", "cursor=&sort_by=id", "per_page=many"} {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/study_sessions?"+query, nil))
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	}
+
+	// Out of range pages are clamped instead of failing
+	w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/study_sessions?page=-1&per_page=0", nil))
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+}
+
+func TestListReviews(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Greetings');
+		INSERT INTO study_activities (name, url) VALUES ('Flashcards', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id) VALUES (1, 1, 1), (1, 1, 1);
+		INSERT INTO words (parts) VALUES ('{"french":"bonjour","english":"hello"}'), ('{"french":"merci","english":"thanks"}');
+		INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) VALUES
+		(1, 1, true, '2025-01-01 10:00:00'),
+		(2, 1, false, '2025-01-01 10:05:00'),
+		(1, 2, false, '2025-01-02 10:00:00');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+	otherUser := testutil.CreateTestUser(t, db, "other@example.com", service.RoleLearner)
+	db.Exec("INSERT INTO study_sessions (user_id, group_id, study_activity_id) VALUES (?, 1, 1)", otherUser.ID)
+	db.Exec("INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 3, true)")
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"", "[3 2 1]"},
+		{"word_id=1", "[3 1]"},
+		{"study_session_id=1&per_page=1&page=2", "[1]"},
+		{"cursor=&per_page=2", "[3 2]"},
+	}
+
+	for _, tt := range tests {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/word_review_items?"+tt.query, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Items []models.WordReviewItem `json:"items"`
+		}
+		testutil.ParseResponse(t, w, &response)
+
+		ids := []int64{}
+		for _, review := range response.Items {
+			ids = append(ids, review.ID)
+		}
+		if fmt.Sprint(ids) != tt.expected {
+			t.Errorf("List %q: expected %s, got %v", tt.query, tt.expected, ids)
+		}
+	}
+
+	w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/word_review_items?word_id=x", nil))
+	testutil.CheckResponseCode(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSessionsAreScopedToUser(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	other := testutil.CreateTestUser(t, db, "other@example.com", service.RoleLearner)
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"test","english":"test"}');
+		INSERT INTO groups (name) VALUES ('Test Group');
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES (1, 1, 1, CURRENT_TIMESTAMP);
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at) VALUES (?, 1, 1, CURRENT_TIMESTAMP);
+	`, other.ID)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/study_sessions", nil)
+	w := testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var list struct {
+		Items []service.SessionResponse `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &list)
+	if len(list.Items) != 1 || list.Items[0].ID != 1 {
+		t.Errorf("Expected only the learner's own session, got %+v", list.Items)
+	}
+
+	// Another learner's session is indistinguishable from a missing one
+	for _, tc := range []struct{ method, path, body string }{
+		{"GET", "/api/study_sessions/2", ""},
+		{"POST", "/api/study_sessions/2/word/1/review", `{"grade":"good"}`},
+		{"POST", "/api/study_sessions/2/reviews", `{"reviews":[{"id":"a","word_id":1,"grade":"good"}]}`},
+		{"POST", "/api/study_sessions/2/end", ""},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := testutil.ExecuteRequest(r, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s %s: expected %d, got %d", tc.method, tc.path, http.StatusNotFound, w.Code)
+		}
+	}
+
+	// Reviews only advance the reviewing learner's schedule
+	req = httptest.NewRequest("POST", "/api/study_sessions/1/word/1/review", strings.NewReader(`{"grade":"good"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var states, otherStates int
+	db.QueryRow("SELECT COUNT(*) FROM word_review_states WHERE user_id = 1").Scan(&states)
+	db.QueryRow("SELECT COUNT(*) FROM word_review_states WHERE user_id = ?", other.ID).Scan(&otherStates)
+	if states != 1 || otherStates != 0 {
+		t.Errorf("Expected 1 schedule for the learner and none for the other user, got %d and %d", states, otherStates)
+	}
+}
@@ -0,0 +1,184 @@
+// Package transfer imports and exports vocabulary as files. See
+// service.Formats for the formats supported.
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxImportSize bounds the files imported, in bytes
+const MaxImportSize = 10 << 20
+
+// contentTypes and extensions describe the files of each format
+var (
+	contentTypes = map[string]string{
+		service.FormatCSV:    "text/csv; charset=utf-8",
+		service.FormatTSV:    "text/tab-separated-values; charset=utf-8",
+		service.FormatSeed:   "application/json; charset=utf-8",
+		service.FormatBundle: "application/json; charset=utf-8",
+		service.FormatApkg:   "application/apkg",
+	}
+	extensions = map[string]string{
+		service.FormatCSV:    "csv",
+		service.FormatTSV:    "tsv",
+		service.FormatSeed:   "json",
+		service.FormatBundle: "json",
+		service.FormatApkg:   "apkg",
+	}
+)
+
+type Handler struct {
+	importService *service.ImportService
+	exportService *service.ExportService
+}
+
+func NewHandler(importService *service.ImportService, exportService *service.ExportService) *Handler {
+	return &Handler{
+		importService: importService,
+		exportService: exportService,
+	}
+}
+
+// RegisterRoutes registers the import and export routes. Importing
+// vocabulary requires the manage content permission; anyone who studies can
+// import their own review history.
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/export", h.Export)
+	r.POST("/import/reviews", h.ImportReviews)
+
+	manage := r.Group("/import", auth.Require(service.PermManageContent))
+	{
+		manage.POST("", h.Import)
+	}
+}
+
+// Export downloads vocabulary as a file in the format query parameter.
+// Anki decks hold the schedule of the current user.
+func (h *Handler) Export(c *gin.Context) {
+	options := service.ExportOptions{
+		Format: c.Query("format"),
+		Pair: service.LanguagePair{
+			Source: c.Query("source_language"),
+			Target: c.Query("target_language"),
+		},
+		UserID: auth.UserID(c),
+	}
+	if groupID := c.Query("group_id"); groupID != "" {
+		id, err := strconv.ParseInt(groupID, 10, 64)
+		if err != nil {
+			apierror.BadRequest(c, "invalid group_id")
+			return
+		}
+		options.GroupID = id
+	}
+
+	data, err := h.exportService.Export(options)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	filename := "vocabulary"
+	if options.GroupID != 0 {
+		filename = fmt.Sprintf("group-%d", options.GroupID)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, extensions[options.Format]))
+	c.Data(http.StatusOK, contentTypes[options.Format], data)
+}
+
+// Import stores the vocabulary of a file sent as the request body, or as
+// the file field of a multipart form, and reports on each word. The review
+// history of Anki decks is imported as reviews by the current user.
+func (h *Handler) Import(c *gin.Context) {
+	h.importFile(c, h.importService.Import)
+}
+
+// ImportReviews imports the review history of an Anki package as reviews
+// by the current user, matching its notes and decks to existing words and
+// groups
+func (h *Handler) ImportReviews(c *gin.Context) {
+	h.importFile(c, h.importService.ImportReviews)
+}
+
+func (h *Handler) importFile(c *gin.Context, importFile func(data []byte, options service.ImportOptions) (*service.ImportReport, error)) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	header, _ := strconv.ParseBool(c.DefaultQuery("header", "true"))
+	reviews, _ := strconv.ParseBool(c.DefaultQuery("reviews", "false"))
+	options := service.ImportOptions{
+		Format: c.Query("format"),
+		DryRun: dryRun,
+		Pair: service.LanguagePair{
+			Source: c.Query("source_language"),
+			Target: c.Query("target_language"),
+		},
+		Group:    c.Query("group"),
+		NoHeader: !header,
+		Reviews:  reviews,
+		UserID:   auth.UserID(c),
+	}
+	if columns := c.Query("columns"); columns != "" {
+		options.Columns = strings.Split(columns, ",")
+	}
+	// fields maps Anki note fields as name:target pairs, such as
+	// Front:french,Back:english
+	if fields := c.Query("fields"); fields != "" {
+		options.Fields = make(map[string]string)
+		for _, mapping := range strings.Split(fields, ",") {
+			name, target, ok := strings.Cut(mapping, ":")
+			if !ok {
+				apierror.BadRequest(c, "invalid fields: "+mapping)
+				return
+			}
+			options.Fields[name] = target
+		}
+	}
+
+	data, err := readFile(c)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.BadRequest(c, fmt.Sprintf("the file is larger than %d MB", MaxImportSize>>20))
+			return
+		}
+		apierror.BadRequest(c, "invalid file: "+err.Error())
+		return
+	}
+
+	report, err := importFile(data, options)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// readFile returns the file uploaded, up to MaxImportSize bytes
+func readFile(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxImportSize)
+	if c.ContentType() != "multipart/form-data" {
+		return io.ReadAll(c.Request.Body)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
@@ -0,0 +1,532 @@
+package transfer
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/anki"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T, role string) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	handler := NewHandler(service.NewImportService(), service.NewExportService())
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, role+"@example.com", role)))
+	handler.RegisterRoutes(api)
+
+	return r, db
+}
+
+func TestImportCSV(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO words (parts) VALUES ('{"english":"cat","french":"chat"}')`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	file := "french,english,attributes.gender,groups\n" +
+		"chat,cat,,Animals\n" +
+		"chien,dog,masculine,Animals;Pets\n" +
+		"chien,dog,,\n" +
+		",bird,,\n" +
+		"oiseau,bird,male,\n"
+
+	w := testutil.Request(r, "POST", "/api/import?format=csv&dry_run=true", file)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	want := service.ImportSummary{Rows: 5, Created: 1, Duplicates: 2, Errors: 2, GroupsCreated: 2}
+	if !report.DryRun || report.Summary != want {
+		t.Fatalf("Expected a dry run summary of %+v, got %+v", want, report.Summary)
+	}
+	if testutil.Count(t, db, "SELECT COUNT(*) FROM words") != 1 || testutil.Count(t, db, "SELECT COUNT(*) FROM groups") != 0 {
+		t.Fatal("Expected a dry run to store nothing")
+	}
+
+	rows := report.Rows
+	if rows[0].Row != 2 || rows[0].Status != service.ImportDuplicate || rows[0].WordID != 1 {
+		t.Errorf("Expected line 2 to duplicate word 1, got %+v", rows[0])
+	}
+	if rows[1].Status != service.ImportCreated || rows[1].WordID != 0 {
+		t.Errorf("Expected line 3 to be created without an id, got %+v", rows[1])
+	}
+	if rows[2].Status != service.ImportDuplicate || rows[2].WordID != 0 {
+		t.Errorf("Expected line 4 to duplicate line 3, got %+v", rows[2])
+	}
+	if rows[3].Row != 5 || rows[3].Status != service.ImportFailed || len(rows[3].Errors) == 0 {
+		t.Errorf("Expected line 5 to miss its french part, got %+v", rows[3])
+	}
+	if rows[4].Status != service.ImportFailed || rows[4].Errors[0].Field != "attributes.gender" {
+		t.Errorf("Expected line 6 to have a bad gender, got %+v", rows[4])
+	}
+
+	w = testutil.Request(r, "POST", "/api/import?format=csv", file)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &report)
+	if report.DryRun || report.Summary != want {
+		t.Fatalf("Expected a summary of %+v, got %+v", want, report.Summary)
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM words"); n != 2 {
+		t.Errorf("Expected 2 words, got %d", n)
+	}
+	if n := testutil.Count(t, db, "SELECT words_count FROM groups WHERE name = 'Animals'"); n != 2 {
+		t.Errorf("Expected the existing and the new word in Animals, got %d", n)
+	}
+	var gender sql.NullString
+	db.QueryRow(`SELECT gender FROM words WHERE json_extract(parts, '$.french') = 'chien'`).Scan(&gender)
+	if gender.String != "masculine" {
+		t.Errorf("Expected the gender attribute to be stored, got %v", gender)
+	}
+
+	// The groups are reused
+	w = testutil.Request(r, "POST", "/api/import?format=csv", file)
+	testutil.ParseResponse(t, w, &report)
+	if report.Summary.Created != 0 || report.Summary.GroupsCreated != 0 || testutil.Count(t, db, "SELECT COUNT(*) FROM groups") != 2 {
+		t.Errorf("Expected a second import to create nothing, got %+v", report.Summary)
+	}
+}
+
+func TestImportCSVOptions(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	file := "猫\tねこ\tneko\tcat\tnoun\n犬\t\tinu\tdog\tnoun\n"
+	w := testutil.Request(r, "POST", "/api/import?format=tsv&header=false&columns=kanji,reading,romaji,english,part_of_speech&source_language=ja&group=Animaux", file)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	if report.Summary.Created != 2 || report.Summary.GroupsCreated != 1 {
+		t.Fatalf("Expected 2 words in a new group, got %+v", report.Summary)
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM groups WHERE name = 'Animaux' AND source_language = 'ja' AND words_count = 2"); n != 1 {
+		t.Errorf("Expected a Japanese group of 2 words, got %d", n)
+	}
+
+	// The header row can be replaced, skipping columns
+	w = testutil.Request(r, "POST", "/api/import?format=csv&columns=french,-,english", "mot,note,word\nchat,x,cat\n")
+	testutil.ParseResponse(t, w, &report)
+	if report.Summary.Created != 1 || report.Rows[0].Row != 2 {
+		t.Errorf("Expected one word from line 2, got %+v", report)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		file   string
+		status int
+	}{
+		{"unknown format", "/api/import?format=xml", "", http.StatusUnprocessableEntity},
+		{"unknown column", "/api/import?format=csv", "french,spanish\nchat,gato\n", http.StatusUnprocessableEntity},
+		{"no columns", "/api/import?format=csv&header=false", "chat,cat\n", http.StatusUnprocessableEntity},
+		{"empty file", "/api/import?format=csv", "", http.StatusUnprocessableEntity},
+		{"bad quotes", "/api/import?format=csv", "french,english\n\"chat,cat\n", http.StatusUnprocessableEntity},
+		{"unknown language", "/api/import?format=csv&source_language=xx", "french,english\n", http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.CheckResponseCode(t, tt.status, testutil.Request(r, "POST", tt.path, tt.file).Code)
+		})
+	}
+}
+
+func TestImportMultipart(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "words.csv")
+	part.Write([]byte("french,english\nchat,cat\n"))
+	form.Close()
+
+	req := httptest.NewRequest("POST", "/api/import?format=csv", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	if report.Summary.Created != 1 {
+		t.Errorf("Expected one word created, got %+v", report.Summary)
+	}
+}
+
+func TestImportRequiresManageContent(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleLearner)
+	defer db.Close()
+
+	testutil.CheckResponseCode(t, http.StatusForbidden, testutil.Request(r, "POST", "/api/import?format=csv", "french,english\n").Code)
+	testutil.CheckResponseCode(t, http.StatusOK, testutil.Request(r, "GET", "/api/export?format=csv", "").Code)
+}
+
+func TestImportSeed(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	file := `{
+		"group_name": "Adjectives",
+		"words": [
+			{"french": "grand", "english": "big", "attributes": {"part_of_speech": "adjective"}},
+			{"french": "petit"},
+			{"english": "small", "french": "petit"}
+		]
+	}`
+	w := testutil.Request(r, "POST", "/api/import?format=seed", file)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	want := service.ImportSummary{Rows: 3, Created: 2, Errors: 1, GroupsCreated: 1}
+	if report.Summary != want {
+		t.Fatalf("Expected %+v, got %+v", want, report.Summary)
+	}
+	if report.Rows[1].Row != 2 || report.Rows[1].Errors[0].Field != "parts.english" {
+		t.Errorf("Expected word 2 to miss its english part, got %+v", report.Rows[1])
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM words WHERE part_of_speech = 'adjective'"); n != 1 {
+		t.Errorf("Expected the attributes to be imported, got %d adjectives", n)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "POST", "/api/import?format=seed", `{"words":[]}`).Code)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "POST", "/api/import?format=seed", `[]`).Code)
+}
+
+func TestExportAndImportBundle(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Animals'), ('Empty');
+		INSERT INTO groups (name, source_language) VALUES ('Animaux', 'ja');
+		INSERT INTO words (parts, gender) VALUES ('{"french":"chat","english":"cat"}', 'masculine');
+		INSERT INTO words (parts, source_language) VALUES ('{"kanji":"猫","romaji":"neko","english":"cat"}', 'ja');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1), (2, 3);
+		INSERT INTO study_activities (name, url) VALUES ('Flashcards', 'http://localhost:8081');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	w := testutil.Request(r, "GET", "/api/export?format=bundle", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	if disposition := w.Header().Get("Content-Disposition"); disposition != `attachment; filename="vocabulary.json"` {
+		t.Errorf("Expected a vocabulary.json attachment, got %q", disposition)
+	}
+
+	var bundle service.Bundle
+	testutil.ParseResponse(t, w, &bundle)
+	if bundle.Version != service.BundleVersion || len(bundle.Groups) != 3 || len(bundle.Words) != 2 ||
+		len(bundle.WordGroups) != 2 || len(bundle.StudyActivities) != 1 {
+		t.Fatalf("Expected the whole vocabulary, got %+v", bundle)
+	}
+	if !strings.Contains(string(bundle.Words[0].Attributes), `"gender": "masculine"`) {
+		t.Errorf("Expected the attributes of word 1, got %s", bundle.Words[0].Attributes)
+	}
+
+	// Importing a bundle into an empty database restores it
+	empty, other := setupTestRouter(t, service.RoleAdmin)
+	defer other.Close()
+
+	w = testutil.Request(empty, "POST", "/api/import?format=bundle", string(w.Body.Bytes()))
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	want := service.ImportSummary{Rows: 2, Created: 2, GroupsCreated: 3, ActivitiesCreated: 1}
+	if report.Summary != want {
+		t.Errorf("Expected %+v, got %+v", want, report.Summary)
+	}
+	if n := testutil.Count(t, other, "SELECT COUNT(*) FROM groups WHERE words_count = 1"); n != 2 {
+		t.Errorf("Expected the words to be linked to their groups, got %d groups with a word", n)
+	}
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"bad version", `{"version":2}`},
+		{"unknown group", `{"version":1,"words":[{"id":1,"parts":{"french":"chat","english":"cat"}}],"word_groups":[{"word_id":1,"group_id":9}]}`},
+		{"unnamed group", `{"version":1,"groups":[{"id":1,"name":" "}]}`},
+		{"bad activity", `{"version":1,"study_activities":[{"name":"Quiz"}]}`},
+		{"group with unknown language", `{"version":1,"groups":[{"id":1,"name":"Tiere","source_language":"de"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(empty, "POST", "/api/import?format=bundle", tt.file).Code)
+		})
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Animals'), ('Pets');
+		INSERT INTO words (parts, gender, notes) VALUES ('{"french":"chat","english":"cat"}', 'masculine', 'a "pet"');
+		INSERT INTO words (parts) VALUES ('{"french":"oiseau","english":"bird"}');
+		INSERT INTO words (parts, source_language) VALUES ('{"kanji":"猫","romaji":"neko","english":"cat"}', 'ja');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1), (1, 2), (2, 1);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	w := testutil.Request(r, "GET", "/api/export?format=csv", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/csv") {
+		t.Errorf("Expected CSV, got %q", contentType)
+	}
+
+	exported := w.Body.String()
+	records, err := csv.NewReader(strings.NewReader(exported)).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read the export: %v", err)
+	}
+	header := "french,english,part_of_speech,gender,plural,ipa,register,notes,groups"
+	if len(records) != 3 || strings.Join(records[0], ",") != header {
+		t.Fatalf("Expected the header and 2 French words, got %q", records)
+	}
+	if got := strings.Join(records[1], ","); got != `chat,cat,,masculine,,,,a "pet",Animals;Pets` {
+		t.Errorf("Unexpected row for chat: %s", got)
+	}
+
+	// An export imports back as duplicates
+	w = testutil.Request(r, "POST", "/api/import?format=csv", exported)
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	if want := (service.ImportSummary{Rows: 2, Duplicates: 2}); report.Summary != want {
+		t.Errorf("Expected %+v, got %+v", want, report.Summary)
+	}
+
+	w = testutil.Request(r, "GET", "/api/export?format=tsv&group_id=2", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	if n := strings.Count(w.Body.String(), "\n"); n != 2 {
+		t.Errorf("Expected the header and the word of Pets, got %q", w.Body.String())
+	}
+
+	w = testutil.Request(r, "GET", "/api/export?format=seed&group_id=1", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	var seed service.SeedFile
+	testutil.ParseResponse(t, w, &seed)
+	if seed.GroupName != "Animals" || len(seed.Words) != 2 || !strings.Contains(string(seed.Words[0]["attributes"]), `"notes": "a \"pet\""`) {
+		t.Errorf("Expected the words of Animals, got %+v", seed)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "GET", "/api/export?format=seed", "").Code)
+	testutil.CheckResponseCode(t, http.StatusNotFound, testutil.Request(r, "GET", "/api/export?format=csv&group_id=9", "").Code)
+	testutil.CheckResponseCode(t, http.StatusBadRequest, testutil.Request(r, "GET", "/api/export?format=csv&group_id=x", "").Code)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "GET", "/api/export", "").Code)
+}
+
+func TestExportAndImportApkg(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Animals');
+		INSERT INTO words (parts, gender) VALUES ('{"french":"chat","english":"cat"}', 'masculine');
+		INSERT INTO words (parts) VALUES ('{"french":"chien","english":"dog"}');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1), (2, 1);
+		INSERT INTO study_sessions (user_id, group_id, created_at) VALUES (1, 1, '2026-01-10 10:00:00');
+		INSERT INTO word_review_items (word_id, study_session_id, correct, quality, response_time_ms, created_at)
+		VALUES (1, 1, 0, 1, 4000, '2026-01-10 10:00:00'), (1, 1, 1, 4, 2500, '2026-01-11 10:00:00');
+		INSERT INTO word_review_states (user_id, word_id, interval_days, repetitions, lapses, due_at, last_reviewed_at)
+		VALUES (1, 1, 1, 1, 1, '2026-01-12 10:00:00', '2026-01-11 10:00:00');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "GET", "/api/export?format=apkg", "").Code)
+
+	w := testutil.Request(r, "GET", "/api/export?format=apkg&group_id=1", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	if disposition := w.Header().Get("Content-Disposition"); disposition != `attachment; filename="group-1.apkg"` {
+		t.Errorf("Expected a group-1.apkg attachment, got %q", disposition)
+	}
+	exported := w.Body.String()
+
+	collection, err := anki.Read([]byte(exported))
+	if err != nil {
+		t.Fatalf("Failed to read the export: %v", err)
+	}
+	if len(collection.Notes) != 2 || len(collection.Cards) != 2 || len(collection.Reviews) != 2 {
+		t.Fatalf("Expected 2 notes, 2 cards and 2 reviews, got %+v", collection)
+	}
+	if got := strings.Join(collection.Notes[0].Fields, "|"); got != "chat|cat|masculine" {
+		t.Errorf("Expected the parts and gender of chat, got %s", got)
+	}
+	if card := collection.Cards[0]; card.Type != anki.CardReview || card.Interval != 1 || card.Lapses != 1 {
+		t.Errorf("Expected chat to be a review card, got %+v", card)
+	}
+	if card := collection.Cards[1]; card.Type != anki.CardNew {
+		t.Errorf("Expected chien to be a new card, got %+v", card)
+	}
+	if collection.Reviews[0].Ease != 1 || collection.Reviews[1].Ease != 3 {
+		t.Errorf("Expected an again and a good review, got %+v", collection.Reviews)
+	}
+
+	// Importing the deck with its reviews restores the group and schedule
+	empty, other := setupTestRouter(t, service.RoleAdmin)
+	defer other.Close()
+
+	w = testutil.Request(empty, "POST", "/api/import?format=apkg&reviews=true", exported)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	want := service.ImportSummary{Rows: 2, Created: 2, GroupsCreated: 1, ReviewsCreated: 2}
+	if report.Summary != want {
+		t.Errorf("Expected %+v, got %+v", want, report.Summary)
+	}
+	if n := testutil.Count(t, other, `SELECT COUNT(*) FROM words WHERE gender = 'masculine'`); n != 1 {
+		t.Errorf("Expected the gender of chat to be imported, got %d words", n)
+	}
+	if n := testutil.Count(t, other, "SELECT COUNT(*) FROM study_sessions WHERE status = 'completed' AND active_seconds = 6"); n != 1 {
+		t.Errorf("Expected a completed session with the reviews, got %d", n)
+	}
+	if n := testutil.Count(t, other, "SELECT COUNT(*) FROM word_review_states WHERE user_id = 1 AND last_reviewed_at IS NOT NULL"); n != 1 {
+		t.Errorf("Expected the reviews to schedule chat, got %d schedules", n)
+	}
+
+	// The imported session has no activity but is listed like any other
+	sessions := service.NewSessionService()
+	listed, total, err := sessions.List(1, service.LanguagePair{}, service.ListOptions{}, 1, 10)
+	if err != nil {
+		t.Fatalf("Failed to list sessions: %v", err)
+	}
+	if total != 1 || len(listed) != 1 || listed[0].ReviewItemsCount != 2 || listed[0].ActivityName != "" {
+		t.Fatalf("Expected the imported session to be listed, got %d of %d: %+v", len(listed), total, listed)
+	}
+	if _, err := sessions.Get(1, listed[0].ID); err != nil {
+		t.Errorf("Expected to get the imported session, got %v", err)
+	}
+
+	// Reviews are imported once
+	w = testutil.Request(empty, "POST", "/api/import?format=apkg&reviews=true", exported)
+	testutil.ParseResponse(t, w, &report)
+	if want := (service.ImportSummary{Rows: 2, Duplicates: 2}); report.Summary != want {
+		t.Errorf("Expected %+v, got %+v", want, report.Summary)
+	}
+}
+
+func TestLearnerImportsReviews(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleLearner)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO groups (name) VALUES ('Animals');
+		INSERT INTO words (parts) VALUES ('{"french":"chat","english":"cat"}'), ('{"french":"chien","english":"dog"}');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1), (2, 1);
+		INSERT INTO study_sessions (user_id, group_id, created_at) VALUES (1, 1, '2026-01-10 10:00:00');
+		INSERT INTO word_review_items (word_id, study_session_id, correct, quality, created_at)
+		VALUES (1, 1, 1, 4, '2026-01-10 10:00:00'), (2, 1, 1, 4, '2026-01-10 10:01:00');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+	w := testutil.Request(r, "GET", "/api/export?format=apkg&group_id=1", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	exported := w.Body.String()
+
+	// The learner's vocabulary only has chat
+	r, other := setupTestRouter(t, service.RoleLearner)
+	defer other.Close()
+	_, err = other.Exec(`
+		INSERT INTO groups (name) VALUES ('Animals');
+		INSERT INTO words (parts) VALUES ('{"french":"chat","english":"cat"}');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusForbidden, testutil.Request(r, "POST", "/api/import?format=apkg&reviews=true", exported).Code)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "POST", "/api/import/reviews?format=csv", "french,english\n").Code)
+
+	w = testutil.Request(r, "POST", "/api/import/reviews?format=apkg", exported)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	if want := (service.ImportSummary{Rows: 2, Duplicates: 1, Unmatched: 1, ReviewsCreated: 1}); report.Summary != want {
+		t.Errorf("Expected %+v, got %+v", want, report.Summary)
+	}
+	if n := testutil.Count(t, other, "SELECT COUNT(*) FROM words"); n != 1 {
+		t.Errorf("Expected no word to be created, got %d words", n)
+	}
+	if n := testutil.Count(t, other, "SELECT COUNT(*) FROM word_review_items wri JOIN study_sessions ss ON wri.study_session_id = ss.id WHERE ss.user_id = 1 AND ss.group_id = 1"); n != 1 {
+		t.Errorf("Expected the review of chat to be imported for the learner, got %d", n)
+	}
+}
+
+func TestImportApkgFields(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	deck := &anki.Collection{
+		Created: time.Now(),
+		Models: map[int64]anki.Model{
+			1: {ID: 1, Name: "Basic", Fields: []string{"Front", "Back"}},
+			2: {ID: 2, Name: "Vocabulary", Fields: []string{"Word", "Meaning", "Notes"}},
+		},
+		Decks: map[int64]anki.Deck{10: {ID: 10, Name: "Verbs"}},
+		Notes: []anki.Note{
+			{ID: 1, ModelID: 1, Fields: []string{"<b>manger</b>", "to eat"}},
+			{ID: 2, ModelID: 2, Fields: []string{"boire", "to drink", "irregular<br>verb"}},
+			{ID: 3, ModelID: 3, Fields: []string{"voir", "to see"}},
+		},
+		Cards: []anki.Card{{ID: 1, NoteID: 1, DeckID: 10}, {ID: 2, NoteID: 2, DeckID: 10}},
+	}
+	data, err := deck.Write()
+	if err != nil {
+		t.Fatalf("Failed to write the deck: %v", err)
+	}
+
+	w := testutil.Request(r, "POST", "/api/import?format=apkg&fields=Word:french,Meaning:english,Notes:notes", string(data))
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	var report service.ImportReport
+	testutil.ParseResponse(t, w, &report)
+	if want := (service.ImportSummary{Rows: 3, Created: 2, Errors: 1, GroupsCreated: 1}); report.Summary != want {
+		t.Fatalf("Expected %+v, got %+v", want, report.Summary)
+	}
+	if report.Rows[2].Status != service.ImportFailed {
+		t.Errorf("Expected the note without a type to fail, got %+v", report.Rows[2])
+	}
+	for _, query := range []string{
+		`SELECT COUNT(*) FROM words WHERE json_extract(parts, '$.french') = 'manger' AND json_extract(parts, '$.english') = 'to eat'`,
+		`SELECT COUNT(*) FROM words WHERE notes = 'irregular verb'`,
+		`SELECT COUNT(*) FROM groups WHERE name = 'Verbs' AND words_count = 2`,
+	} {
+		if testutil.Count(t, db, query) != 1 {
+			t.Errorf("Expected a match for %s", query)
+		}
+	}
+
+	for _, path := range []string{
+		"/api/import?format=apkg&fields=Word:spanish",
+		"/api/import?format=apkg&fields=Word",
+	} {
+		if code := testutil.Request(r, "POST", path, string(data)).Code; code == http.StatusOK {
+			t.Errorf("Expected %s to fail, got %d", path, code)
+		}
+	}
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, testutil.Request(r, "POST", "/api/import?format=apkg", "not a zip").Code)
+}
@@ -0,0 +1,75 @@
+package users
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/pagination"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	userService *service.UserService
+}
+
+func NewHandler(userService *service.UserService) *Handler {
+	return &Handler{
+		userService: userService,
+	}
+}
+
+// RegisterRoutes registers the account management routes, which require the
+// manage users permission
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	users := r.Group("/users", auth.Require(service.PermManageUsers))
+	{
+		users.GET("", h.List)
+		users.PUT("/:id/role", h.SetRole)
+	}
+}
+
+// List returns a paginated list of user accounts
+func (h *Handler) List(c *gin.Context) {
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	users, total, err := h.userService.List(page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, users, total, page)
+}
+
+// SetRole changes a user's role
+func (h *Handler) SetRole(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	user, err := h.userService.SetRole(auth.UserID(c), id, req.Role)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,115 @@
+package users
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter(t *testing.T, role string) (*gin.Engine, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+
+	userService := service.NewUserService()
+	handler := NewHandler(userService)
+
+	r := gin.New()
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, role+"@example.com", role)))
+	handler.RegisterRoutes(api)
+
+	return r, db
+}
+
+func setRole(r *gin.Engine, id, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("PUT", "/api/users/"+id+"/role", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	return testutil.ExecuteRequest(r, req)
+}
+
+func TestSetRole(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	testutil.CreateTestUser(t, db, "marie@example.com", service.RoleLearner)
+
+	w := setRole(r, "2", `{"role":"teacher"}`)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var user models.User
+	testutil.ParseResponse(t, w, &user)
+	if user.ID != 2 || user.Role != service.RoleTeacher {
+		t.Errorf("Expected user 2 to be a teacher, got %+v", user)
+	}
+
+	var actor int64
+	var details string
+	err := db.QueryRow("SELECT user_id, details FROM audit_log WHERE action = 'set_role'").Scan(&actor, &details)
+	if err != nil || actor != 1 || details != `{"from":"learner","to":"teacher","user_id":2}` {
+		t.Errorf("Expected the change to be audited, got %d %s (%v)", actor, details, err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/users", nil)
+	w = testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var list struct {
+		Items []models.User `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &list)
+	if len(list.Items) != 2 || list.Items[1].Role != service.RoleTeacher {
+		t.Errorf("Expected both users, got %+v", list.Items)
+	}
+}
+
+func TestSetRoleErrors(t *testing.T) {
+	r, db := setupTestRouter(t, service.RoleAdmin)
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		id       string
+		body     string
+		expected int
+		code     string
+	}{
+		{"unknown role", "1", `{"role":"owner"}`, http.StatusUnprocessableEntity, apierror.CodeValidation},
+		{"missing role", "1", `{}`, http.StatusUnprocessableEntity, apierror.CodeValidation},
+		{"unknown user", "99", `{"role":"teacher"}`, http.StatusNotFound, apierror.CodeNotFound},
+		{"last admin", "1", `{"role":"learner"}`, http.StatusConflict, apierror.CodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := setRole(r, tt.id, tt.body)
+			testutil.CheckResponseCode(t, tt.expected, w.Code)
+
+			var response apierror.Body
+			testutil.ParseResponse(t, w, &response)
+			if response.Code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, response.Code)
+			}
+		})
+	}
+}
+
+func TestUsersRequireAdmin(t *testing.T) {
+	for _, role := range []string{service.RoleTeacher, service.RoleLearner} {
+		r, db := setupTestRouter(t, role)
+
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/users", nil))
+		testutil.CheckResponseCode(t, http.StatusForbidden, w.Code)
+
+		w = setRole(r, "1", `{"role":"admin"}`)
+		testutil.CheckResponseCode(t, http.StatusForbidden, w.Code)
+
+		db.Close()
+	}
+}
@@ -1,9 +1,14 @@
 package words
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/listing"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/pagination"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -19,55 +24,211 @@ func NewHandler(wordService *service.WordService) *Handler {
 	}
 }
 
-// RegisterRoutes registers all routes for words
+// RegisterRoutes registers all routes for words. Changing words requires
+// the manage content permission.
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	words := r.Group("/words")
 	{
 		words.GET("", h.List)
+		words.GET("/due", h.ListDue)
+		words.GET("/search", h.Search)
 		words.GET("/:id", h.Get)
 	}
+
+	manage := r.Group("/words", auth.Require(service.PermManageContent))
+	{
+		manage.POST("", h.Create)
+		manage.PUT("/:id", h.Update)
+		manage.PATCH("/:id", h.Patch)
+		manage.DELETE("/:id", h.Delete)
+	}
 }
 
-// List returns a paginated list of words
+type wordRequest struct {
+	SourceLanguage string          `json:"source_language"`
+	TargetLanguage string          `json:"target_language"`
+	Parts          json.RawMessage `json:"parts"`
+	Attributes     json.RawMessage `json:"attributes"`
+	GroupIDs       []int64         `json:"group_ids"`
+}
+
+func (r wordRequest) input() service.WordInput {
+	return service.WordInput{
+		Pair:       service.LanguagePair{Source: r.SourceLanguage, Target: r.TargetLanguage},
+		Parts:      r.Parts,
+		Attributes: r.Attributes,
+		GroupIDs:   r.GroupIDs,
+	}
+}
+
+// List returns a paginated list of words, optionally of one language pair
+// and filtered by part of speech, gender and register
 func (h *Handler) List(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
 
-	words, total, err := h.wordService.List(page, perPage)
+	filter := service.WordFilter{
+		LanguagePair: service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")},
+		PartOfSpeech: c.Query("part_of_speech"),
+		Gender:       c.Query("gender"),
+		Register:     c.Query("register"),
+	}
+
+	words, total, err := h.wordService.List(auth.UserID(c), filter, listing.Options(c), page.Number, page.PerPage)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": words,
-		"pagination": gin.H{
-			"current_page":   page,
-			"total_pages":    (total + perPage - 1) / perPage,
-			"total_items":    total,
-			"items_per_page": perPage,
-		},
-	})
+	pagination.Respond(c, words, total, page)
+}
+
+// ListDue returns words whose spaced repetition review is due now
+func (h *Handler) ListDue(c *gin.Context) {
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	includeNew, _ := strconv.ParseBool(c.DefaultQuery("include_new", "true"))
+	pair := service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")}
+
+	words, total, err := h.wordService.ListDue(auth.UserID(c), pair, includeNew, page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, words, total, page)
+}
+
+// Search returns a page of the words matching a full-text query, most
+// relevant first. Matching ignores case and accents and treats each word of
+// the query as a prefix.
+func (h *Handler) Search(c *gin.Context) {
+	page, err := pagination.Parse(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	search := service.WordSearch{
+		LanguagePair: service.LanguagePair{Source: c.Query("source_language"), Target: c.Query("target_language")},
+		Query:        c.Query("q"),
+		Direction:    c.Query("direction"),
+	}
+
+	words, total, err := h.wordService.Search(auth.UserID(c), search, page.Number, page.PerPage)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	pagination.Respond(c, words, total, page)
 }
 
 // Get returns a single word by ID
 func (h *Handler) Get(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		apierror.BadRequest(c, "invalid id")
 		return
 	}
 
-	word, err := h.wordService.Get(id)
+	word, err := h.wordService.Get(auth.UserID(c), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
 	if word == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "word not found"})
+		apierror.NotFound(c, "word not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, word)
 }
+
+// Create adds a new word, optionally attaching it to groups
+func (h *Handler) Create(c *gin.Context) {
+	var req wordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	word, err := h.wordService.Create(auth.UserID(c), req.input())
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, word)
+}
+
+// Update replaces a word's parts and, if given, its attributes and groups
+func (h *Handler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req wordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	word, err := h.wordService.Update(auth.UserID(c), id, req.input())
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, word)
+}
+
+// Patch merges the given parts and attributes into a word and, if group_ids
+// is given, replaces its groups
+func (h *Handler) Patch(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req wordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Binding(c, err)
+		return
+	}
+
+	word, err := h.wordService.Patch(auth.UserID(c), id, req.input())
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, word)
+}
+
+// Delete removes a word with its group links and review history
+func (h *Handler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.BadRequest(c, "invalid id")
+		return
+	}
+
+	if err := h.wordService.Delete(id); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,13 +1,18 @@
 package words
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/apierror"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
 	"github.com/gin-gonic/gin"
@@ -21,7 +26,7 @@ func setupTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
 	handler := NewHandler(wordService)
 
 	r := gin.New()
-	api := r.Group("/api")
+	api := r.Group("/api", testutil.AsUser(testutil.CreateTestUser(t, db, "admin@example.com", service.RoleAdmin)))
 	handler.RegisterRoutes(api)
 
 	return r, db
@@ -126,3 +131,474 @@ func TestGetWord(t *testing.T) {
 
 	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
 }
+
+func TestCreateWord(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO groups (name) VALUES ('Greetings')`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	body := []byte(`{"parts":{"french":"salut","english":"hi"},"group_ids":[1]}`)
+	req := httptest.NewRequest("POST", "/api/words", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var word service.WordResponse
+	testutil.ParseResponse(t, w, &word)
+
+	var wordsCount int
+	db.QueryRow("SELECT words_count FROM groups WHERE id = 1").Scan(&wordsCount)
+	if wordsCount != 1 {
+		t.Errorf("Expected group words_count 1, got %d", wordsCount)
+	}
+
+	// Missing english translation
+	body = []byte(`{"parts":{"french":"salut","english":""}}`)
+	req = httptest.NewRequest("POST", "/api/words", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	// Unknown group
+	body = []byte(`{"parts":{"french":"salut","english":"hi"},"group_ids":[42]}`)
+	req = httptest.NewRequest("POST", "/api/words", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestPatchWord(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO words (parts) VALUES ('{"french":"bonjour","english":"hello"}')`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	body := []byte(`{"parts":{"english":"good morning"}}`)
+	req := httptest.NewRequest("PATCH", "/api/words/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var word service.WordResponse
+	testutil.ParseResponse(t, w, &word)
+
+	var parts struct {
+		French  string `json:"french"`
+		English string `json:"english"`
+	}
+	if err := json.Unmarshal(word.Parts, &parts); err != nil {
+		t.Fatalf("Failed to parse word parts: %v", err)
+	}
+
+	if parts.French != "bonjour" || parts.English != "good morning" {
+		t.Errorf("Expected bonjour/good morning, got %s/%s", parts.French, parts.English)
+	}
+
+	req = httptest.NewRequest("PATCH", "/api/words/999", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+}
+
+func TestPatchWordWithEmptyOptionalValue(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Words stored before a field had allowed values may hold it empty
+	_, err := db.Exec(`
+		INSERT INTO language_fields (language_code, name, required, allowed_values, position)
+		VALUES ('fr', 'article', false, '["le","la"]', 5);
+		INSERT INTO words (parts) VALUES ('{"french":"chat","english":"cat","article":""}');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PATCH", "/api/words/1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return testutil.ExecuteRequest(r, req)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusOK, patch(`{"parts":{"english":"tomcat"}}`).Code)
+	testutil.CheckResponseCode(t, http.StatusOK, patch(`{"parts":{"article":"le"}}`).Code)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, patch(`{"parts":{"article":"les"}}`).Code)
+}
+
+func TestWordLanguages(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"chat","english":"cat"}');
+		INSERT INTO groups (name) VALUES ('Animals');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/words", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return testutil.ExecuteRequest(r, req)
+	}
+
+	w := post(`{"source_language":"ja","target_language":"en","parts":{"kanji":"猫","reading":"ねこ","romaji":"neko","english":"cat"}}`)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var word service.WordResponse
+	testutil.ParseResponse(t, w, &word)
+	if word.SourceLanguage != "ja" || word.TargetLanguage != "en" {
+		t.Errorf("Expected a Japanese to English word, got %s to %s", word.SourceLanguage, word.TargetLanguage)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing required field", `{"source_language":"ja","parts":{"kanji":"犬","english":"dog"}}`},
+		{"field of another language", `{"parts":{"french":"chien","english":"dog","romaji":"inu"}}`},
+		{"gender in parts", `{"parts":{"french":"chien","english":"dog","gender":"masculine"}}`},
+		{"not a string", `{"parts":{"french":"chien","english":2}}`},
+		{"unknown language", `{"source_language":"xx","parts":{"english":"dog"}}`},
+		{"same languages", `{"source_language":"en","target_language":"en","parts":{"english":"dog"}}`},
+		{"group of another pair", `{"source_language":"ja","parts":{"kanji":"犬","romaji":"inu","english":"dog"},"group_ids":[1]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(tt.body)
+			testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+		})
+	}
+
+	// Every problem is reported with its field
+	w = post(`{"parts":{"french":" ","romaji":"inu"}}`)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	var body apierror.Body
+	testutil.ParseResponse(t, w, &body)
+	fields, _ := json.Marshal(body.Fields)
+	expected := `[{"field":"parts.english","message":"is required"},` +
+		`{"field":"parts.french","message":"must not be blank"},` +
+		`{"field":"parts.romaji","message":"is not allowed"}]`
+	if string(fields) != expected {
+		t.Errorf("Expected field errors %s, got %s", expected, fields)
+	}
+
+	req := httptest.NewRequest("GET", "/api/words?source_language=ja&target_language=en", nil)
+	w = testutil.ExecuteRequest(r, req)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []service.WordResponse `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Items) != 1 || response.Items[0].ID != 2 {
+		t.Errorf("Expected only the Japanese word, got %+v", response.Items)
+	}
+
+	req = httptest.NewRequest("GET", "/api/words/due?source_language=fr", nil)
+	w = testutil.ExecuteRequest(r, req)
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Items) != 1 || response.Items[0].ID != 1 {
+		t.Errorf("Expected only the French word to be due, got %+v", response.Items)
+	}
+}
+
+func TestWordAttributes(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return testutil.ExecuteRequest(r, req)
+	}
+
+	w := send("POST", "/api/words", `{"parts":{"french":"cheval","english":"horse"},`+
+		`"attributes":{"part_of_speech":"noun","gender":"masculine","plural":"chevaux","ipa":" ʃə.val "}}`)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	var word service.WordResponse
+	testutil.ParseResponse(t, w, &word)
+	if word.Attributes.Gender != "masculine" || word.Attributes.Plural != "chevaux" || word.Attributes.IPA != "ʃə.val" {
+		t.Errorf("Expected the attributes to be stored, got %+v", word.Attributes)
+	}
+
+	w = send("POST", "/api/words", `{"parts":{"french":"courir","english":"to run"},"attributes":{"part_of_speech":"verb"}}`)
+	testutil.CheckResponseCode(t, http.StatusCreated, w.Code)
+
+	// Patching merges, null clears and a PUT without attributes keeps them
+	w = send("PATCH", "/api/words/1", `{"attributes":{"register":"informal","ipa":null}}`)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	w = send("PUT", "/api/words/1", `{"parts":{"french":"cheval","english":"horse"}}`)
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	word = service.WordResponse{}
+	testutil.ParseResponse(t, w, &word)
+	expected := models.WordAttributes{PartOfSpeech: "noun", Gender: "masculine", Plural: "chevaux", Register: "informal"}
+	if word.Attributes != expected {
+		t.Errorf("Expected attributes %+v, got %+v", expected, word.Attributes)
+	}
+
+	var response struct {
+		Items []service.WordResponse `json:"items"`
+	}
+	w = send("GET", "/api/words?part_of_speech=noun&gender=masculine", "")
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Items) != 1 || response.Items[0].ID != 1 {
+		t.Errorf("Expected only the masculine noun, got %+v", response.Items)
+	}
+
+	w = send("GET", "/api/words?register=formal", "")
+	testutil.ParseResponse(t, w, &response)
+	if len(response.Items) != 0 {
+		t.Errorf("Expected no formal words, got %+v", response.Items)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, send("GET", "/api/words?gender=plural", "").Code)
+
+	w = send("PATCH", "/api/words/2", `{"attributes":{"part_of_speech":"verbe","tense":"present"}}`)
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+
+	var body apierror.Body
+	testutil.ParseResponse(t, w, &body)
+	fields, _ := json.Marshal(body.Fields)
+	expectedFields := `[{"field":"attributes.tense","message":"is not allowed"},` +
+		`{"field":"attributes.part_of_speech","message":"must be one of ` + strings.Join(service.PartsOfSpeech, ", ") + `"}]`
+	if string(fields) != expectedFields {
+		t.Errorf("Expected field errors %s, got %s", expectedFields, fields)
+	}
+
+	testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, send("PATCH", "/api/words/2", `{"attributes":{"notes":1}}`).Code)
+}
+
+func TestListWordsSortedAndFiltered(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES
+		('{"french":"chat","english":"cat"}'),
+		('{"french":"Bonjour","english":"hello"}'),
+		('{"french":"au revoir","english":"goodbye"}');
+		INSERT INTO groups (name) VALUES ('Greetings');
+		INSERT INTO word_groups (word_id, group_id) VALUES (2, 1), (3, 1);
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id) VALUES (1, 1, 1);
+		INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (3, 1, false), (3, 1, false), (1, 1, false);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"", "[1 2 3]"},
+		{"sort_by=french", "[3 2 1]"},
+		{"sort_by=english&order=desc", "[2 3 1]"},
+		{"sort_by=wrong_count&order=desc", "[3 1 2]"},
+		{"group_id=1&order=desc", "[3 2]"},
+	}
+
+	for _, tt := range tests {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/words?"+tt.query, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Items []service.WordResponse `json:"items"`
+		}
+		testutil.ParseResponse(t, w, &response)
+
+		ids := []int64{}
+		for _, word := range response.Items {
+			ids = append(ids, word.ID)
+		}
+		if fmt.Sprint(ids) != tt.expected {
+			t.Errorf("List %q: expected %s, got %v", tt.query, tt.expected, ids)
+		}
+	}
+
+	for _, query := range []string{"sort_by=spelling", "sort_by=parts", "activity_id=1", "from=2025-01-01"} {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/words?"+query, nil))
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestSearchWords(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES
+		('{"french":"être en retard","english":"to be late"}'),
+		('{"french":"être","english":"to be"}'),
+		('{"french":"fenêtre","english":"window"}'),
+		('{"french":"l''été","english":"summer"}');
+		INSERT INTO words (parts, source_language, target_language) VALUES
+		('{"kanji":"猫","reading":"ねこ","romaji":"neko","english":"cat"}', 'ja', 'en');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	search := func(query string) []int64 {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/words/search?"+query, nil))
+		testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Items []service.WordResponse `json:"items"`
+		}
+		testutil.ParseResponse(t, w, &response)
+
+		ids := []int64{}
+		for _, word := range response.Items {
+			ids = append(ids, word.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		query    string
+		expected []int64
+	}{
+		// Exact matches rank first, accents and case are ignored
+		{"q=etre", []int64{2, 1}},
+		{"q=ETR", []int64{2, 1}},
+		{"q=ete", []int64{4}},
+		{"q=retar", []int64{1}},
+		{"q=to+be", []int64{2, 1}},
+		{"q=to+be&direction=source", []int64{}},
+		{"q=window&direction=target", []int64{3}},
+		{"q=neko", []int64{5}},
+		{"q=neko&source_language=fr", []int64{}},
+		{"q=etre&per_page=1&page=2", []int64{1}},
+	}
+
+	for _, tt := range tests {
+		if ids := search(tt.query); fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+			t.Errorf("Search %s: expected %v, got %v", tt.query, tt.expected, ids)
+		}
+	}
+
+	// The index follows changes to words
+	req := httptest.NewRequest("PUT", "/api/words/3", bytes.NewBufferString(`{"parts":{"french":"étoile","english":"star"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	testutil.CheckResponseCode(t, http.StatusOK, testutil.ExecuteRequest(r, req).Code)
+	testutil.CheckResponseCode(t, http.StatusNoContent, testutil.ExecuteRequest(r, httptest.NewRequest("DELETE", "/api/words/2", nil)).Code)
+
+	if ids := search("q=etoi"); fmt.Sprint(ids) != "[3]" {
+		t.Errorf("Expected the updated word, got %v", ids)
+	}
+	if ids := search("q=window"); len(ids) != 0 {
+		t.Errorf("Expected no words for the old parts, got %v", ids)
+	}
+	if ids := search("q=etre"); fmt.Sprint(ids) != "[1]" {
+		t.Errorf("Expected the deleted word to be gone, got %v", ids)
+	}
+
+	for _, query := range []string{"q=", "q=*", "q=etre&direction=sideways"} {
+		w := testutil.ExecuteRequest(r, httptest.NewRequest("GET", "/api/words/search?"+query, nil))
+		testutil.CheckResponseCode(t, http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestDeleteWord(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"bonjour","english":"hello"}');
+		INSERT INTO groups (name) VALUES ('Greetings');
+		INSERT INTO word_groups (word_id, group_id) VALUES (1, 1);
+		INSERT INTO study_activities (name, url) VALUES ('Test Activity', 'http://test.com');
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id) VALUES (1, 1, 1);
+		INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 1, true);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	req := httptest.NewRequest("DELETE", "/api/words/1", nil)
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusNoContent, w.Code)
+
+	var links, reviews, wordsCount int
+	db.QueryRow("SELECT COUNT(*) FROM word_groups").Scan(&links)
+	db.QueryRow("SELECT COUNT(*) FROM word_review_items").Scan(&reviews)
+	db.QueryRow("SELECT words_count FROM groups WHERE id = 1").Scan(&wordsCount)
+	if links != 0 || reviews != 0 || wordsCount != 0 {
+		t.Errorf("Expected links, reviews and words_count to be 0, got %d, %d, %d", links, reviews, wordsCount)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/words/1", nil)
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusNotFound, w.Code)
+}
+
+func TestListDueWords(t *testing.T) {
+	r, db := setupTestRouter(t)
+	defer db.Close()
+
+	// Word 1 is overdue, word 2 is scheduled in the future, word 3 is new
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES
+		('{"french":"bonjour","english":"hello"}'),
+		('{"french":"merci","english":"thank you"}'),
+		('{"french":"au revoir","english":"goodbye"}');
+		INSERT INTO word_review_states (user_id, word_id, interval_days, repetitions, due_at) VALUES
+		(1, 1, 1, 1, ?),
+		(1, 2, 6, 2, ?);
+	`, time.Now().UTC().Add(-time.Hour).Truncate(time.Second), time.Now().UTC().Add(72*time.Hour).Truncate(time.Second))
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/words/due", nil)
+	w := testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []service.DueWordResponse `json:"items"`
+	}
+	testutil.ParseResponse(t, w, &response)
+
+	if len(response.Items) != 2 {
+		t.Fatalf("Expected 2 due words, got %d", len(response.Items))
+	}
+	if response.Items[0].ID != 1 || response.Items[0].ReviewState == nil {
+		t.Errorf("Expected overdue word 1 first with its review state, got %+v", response.Items[0])
+	}
+	if response.Items[1].ID != 3 || response.Items[1].ReviewState != nil {
+		t.Errorf("Expected new word 3 second without a review state, got %+v", response.Items[1])
+	}
+
+	req = httptest.NewRequest("GET", "/api/words/due?include_new=false", nil)
+	w = testutil.ExecuteRequest(r, req)
+
+	testutil.CheckResponseCode(t, http.StatusOK, w.Code)
+	testutil.ParseResponse(t, w, &response)
+
+	if len(response.Items) != 1 {
+		t.Errorf("Expected 1 due word without new words, got %d", len(response.Items))
+	}
+}
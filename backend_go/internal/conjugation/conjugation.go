@@ -0,0 +1,306 @@
+// Package conjugation conjugates French verbs. Regular -er, -ir and -re
+// verbs follow the rules of their group; irregular verbs are looked up in a
+// table.
+package conjugation
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Tense is a French tense
+type Tense string
+
+const (
+	Present      Tense = "present"
+	Imperfect    Tense = "imperfect"
+	Future       Tense = "future"
+	Conditional  Tense = "conditional"
+	PasseCompose Tense = "passe_compose"
+)
+
+// Tenses lists every supported tense
+var Tenses = []Tense{Present, Imperfect, Future, Conditional, PasseCompose}
+
+// ParseTense returns the tense with the given name
+func ParseTense(name string) (Tense, bool) {
+	for _, tense := range Tenses {
+		if string(tense) == name {
+			return tense, true
+		}
+	}
+	return "", false
+}
+
+// Person is the grammatical person a verb is conjugated for
+type Person int
+
+const (
+	FirstSingular Person = iota
+	SecondSingular
+	ThirdSingular
+	FirstPlural
+	SecondPlural
+	ThirdPlural
+)
+
+// Persons lists every person in conjugation table order
+var Persons = []Person{FirstSingular, SecondSingular, ThirdSingular, FirstPlural, SecondPlural, ThirdPlural}
+
+var personNames = [...]string{"je", "tu", "il", "nous", "vous", "ils"}
+
+var pronouns = [...]string{"je", "tu", "il/elle", "nous", "vous", "ils/elles"}
+
+// ParsePerson returns the person with the given name, one of je, tu, il,
+// nous, vous or ils
+func ParsePerson(name string) (Person, bool) {
+	for i, personName := range personNames {
+		if personName == name {
+			return Person(i), true
+		}
+	}
+	return 0, false
+}
+
+// String returns the name of the person, as accepted by ParsePerson
+func (p Person) String() string {
+	return personNames[p]
+}
+
+// Pronoun returns the subject pronouns of the person
+func (p Person) Pronoun() string {
+	return pronouns[p]
+}
+
+func (p Person) plural() bool {
+	return p >= FirstPlural
+}
+
+// ErrNotAVerb is returned for words that are not an infinitive that can be
+// conjugated
+var ErrNotAVerb = errors.New("not a French verb that can be conjugated")
+
+// Verb is a French verb with the stems it is conjugated from
+type Verb struct {
+	Infinitive string
+	// Auxiliary is avoir or être, the verb the passé composé is formed with
+	Auxiliary  string
+	Participle string
+	Irregular  bool
+
+	present       [6]string
+	imperfectStem string
+	futureStem    string
+}
+
+// Lookup returns the verb for an infinitive such as parler or aller
+func Lookup(infinitive string) (*Verb, error) {
+	infinitive = strings.ToLower(strings.TrimSpace(infinitive))
+	if infinitive == "" || strings.ContainsAny(infinitive, " '’") {
+		return nil, ErrNotAVerb
+	}
+
+	if entry, ok := irregularVerbs[infinitive]; ok {
+		return entry.verb(infinitive), nil
+	}
+
+	for _, ending := range []string{"er", "ir", "re"} {
+		stem, ok := strings.CutSuffix(infinitive, ending)
+		if ok && len([]rune(stem)) >= 2 && isLetters(stem) {
+			return regularVerb(infinitive, stem, ending), nil
+		}
+	}
+
+	return nil, ErrNotAVerb
+}
+
+func regularVerb(infinitive, stem, ending string) *Verb {
+	verb := &Verb{
+		Infinitive: infinitive,
+		Auxiliary:  auxiliary(infinitive),
+		futureStem: infinitive,
+	}
+
+	switch ending {
+	case "er":
+		// Keep the soft g and c before o: mangeons, commençons
+		nous := stem
+		if strings.HasSuffix(stem, "g") {
+			nous += "e"
+		} else if c, ok := strings.CutSuffix(stem, "c"); ok {
+			nous = c + "ç"
+		}
+		verb.present = [6]string{stem + "e", stem + "es", stem + "e", nous + "ons", stem + "ez", stem + "ent"}
+		verb.Participle = stem + "é"
+	case "ir":
+		verb.present = [6]string{stem + "is", stem + "is", stem + "it", stem + "issons", stem + "issez", stem + "issent"}
+		verb.Participle = stem + "i"
+	case "re":
+		verb.present = [6]string{stem + "s", stem + "s", stem, stem + "ons", stem + "ez", stem + "ent"}
+		verb.Participle = stem + "u"
+		verb.futureStem = stem + "r"
+	}
+
+	return verb
+}
+
+// Conjugate returns the forms of the verb in a tense, in the order of
+// Persons. Passé composé forms include the auxiliary; with être the
+// participle agrees with the subject, as in allé(e) and allé(e)s.
+func (v *Verb) Conjugate(tense Tense) [6]string {
+	var forms [6]string
+	for _, person := range Persons {
+		forms[person] = v.Form(tense, person)
+	}
+	return forms
+}
+
+// Form returns the verb conjugated in one tense and person
+func (v *Verb) Form(tense Tense, person Person) string {
+	switch tense {
+	case Present:
+		return v.present[person]
+	case Imperfect:
+		return imperfectForm(v.imperfect(), person)
+	case Future:
+		return v.futureStem + futureEndings[person]
+	case Conditional:
+		return v.futureStem + imperfectEndings[person]
+	case PasseCompose:
+		if v.Auxiliary == "être" {
+			agreement := "(e)"
+			if person.plural() {
+				agreement = "(e)s"
+			}
+			return etre.present[person] + " " + v.Participle + agreement
+		}
+		return avoir.present[person] + " " + v.Participle
+	}
+	return ""
+}
+
+// imperfect returns the stem of the imperfect: the nous form of the present
+// without -ons
+func (v *Verb) imperfect() string {
+	if v.imperfectStem != "" {
+		return v.imperfectStem
+	}
+	return strings.TrimSuffix(v.present[FirstPlural], "ons")
+}
+
+var (
+	futureEndings    = [6]string{"ai", "as", "a", "ons", "ez", "ont"}
+	imperfectEndings = [6]string{"ais", "ais", "ait", "ions", "iez", "aient"}
+)
+
+// imperfectForm adds the imperfect ending, undoing the spelling changes
+// that only apply before a: mangions, commencions
+func imperfectForm(stem string, person Person) string {
+	ending := imperfectEndings[person]
+	if strings.HasPrefix(ending, "i") {
+		if s, ok := strings.CutSuffix(stem, "ge"); ok {
+			stem = s + "g"
+		} else if s, ok := strings.CutSuffix(stem, "ç"); ok {
+			stem = s + "c"
+		}
+	}
+	return stem + ending
+}
+
+// Text returns a form with its subject pronoun, eliding je before a vowel
+// or mute h: je parle, j'aime, il/elle a
+func Text(person Person, form string) string {
+	if person == FirstSingular && startsWithVowelSound(form) {
+		return "j'" + form
+	}
+	return person.Pronoun() + " " + form
+}
+
+// Answers returns the spellings of a form accepted as correct, spelling out
+// the agreements of passé composé forms with être
+func Answers(form string) []string {
+	if base, ok := strings.CutSuffix(form, "(e)s"); ok {
+		return []string{base + "s", base + "es"}
+	}
+	if base, ok := strings.CutSuffix(form, "(e)"); ok {
+		return []string{base, base + "e"}
+	}
+	return []string{form}
+}
+
+func startsWithVowelSound(word string) bool {
+	for _, r := range word {
+		return strings.ContainsRune("aeiouyhàâäéèêëîïôöûüœ", r)
+	}
+	return false
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match is how closely an answer matches a form
+type Match int
+
+const (
+	NoMatch Match = iota
+	// MatchIgnoringAccents is a correct answer with missing or wrong accents
+	MatchIgnoringAccents
+	MatchExact
+)
+
+// Compare checks a learner's answer against a form of the verb. Case,
+// spacing and a leading subject pronoun are ignored.
+func Compare(person Person, form, answer string) Match {
+	answer = normalizeAnswer(person, answer)
+
+	match := NoMatch
+	for _, accepted := range Answers(form) {
+		if answer == accepted {
+			return MatchExact
+		}
+		if stripAccents(answer) == stripAccents(accepted) {
+			match = MatchIgnoringAccents
+		}
+	}
+	return match
+}
+
+var subjectPronouns = [...][]string{
+	{"je ", "j'"},
+	{"tu "},
+	{"il ", "elle ", "on "},
+	{"nous "},
+	{"vous "},
+	{"ils ", "elles "},
+}
+
+func normalizeAnswer(person Person, answer string) string {
+	answer = strings.ToLower(strings.Join(strings.Fields(answer), " "))
+	answer = strings.ReplaceAll(answer, "’", "'")
+	for _, pronoun := range subjectPronouns[person] {
+		if rest, ok := strings.CutPrefix(answer, pronoun); ok {
+			return rest
+		}
+	}
+	return answer
+}
+
+var accents = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a",
+	"ç", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i",
+	"ô", "o", "ö", "o",
+	"ù", "u", "û", "u", "ü", "u",
+)
+
+func stripAccents(s string) string {
+	return accents.Replace(s)
+}
@@ -0,0 +1,99 @@
+package conjugation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConjugate(t *testing.T) {
+	tests := []struct {
+		infinitive string
+		tense      Tense
+		expected   [6]string
+	}{
+		{"parler", Present, [6]string{"parle", "parles", "parle", "parlons", "parlez", "parlent"}},
+		{"finir", Present, [6]string{"finis", "finis", "finit", "finissons", "finissez", "finissent"}},
+		{"vendre", Present, [6]string{"vends", "vends", "vend", "vendons", "vendez", "vendent"}},
+		{"manger", Present, [6]string{"mange", "manges", "mange", "mangeons", "mangez", "mangent"}},
+		{"commencer", Imperfect, [6]string{"commençais", "commençais", "commençait", "commencions", "commenciez", "commençaient"}},
+		{"finir", Imperfect, [6]string{"finissais", "finissais", "finissait", "finissions", "finissiez", "finissaient"}},
+		{"vendre", Future, [6]string{"vendrai", "vendras", "vendra", "vendrons", "vendrez", "vendront"}},
+		{"parler", Conditional, [6]string{"parlerais", "parlerais", "parlerait", "parlerions", "parleriez", "parleraient"}},
+		{"être", Present, [6]string{"suis", "es", "est", "sommes", "êtes", "sont"}},
+		{"être", Imperfect, [6]string{"étais", "étais", "était", "étions", "étiez", "étaient"}},
+		{"aller", Future, [6]string{"irai", "iras", "ira", "irons", "irez", "iront"}},
+		{"faire", Imperfect, [6]string{"faisais", "faisais", "faisait", "faisions", "faisiez", "faisaient"}},
+		{"avoir", PasseCompose, [6]string{"ai eu", "as eu", "a eu", "avons eu", "avez eu", "ont eu"}},
+		{"aller", PasseCompose, [6]string{"suis allé(e)", "es allé(e)", "est allé(e)", "sommes allé(e)s", "êtes allé(e)s", "sont allé(e)s"}},
+		{"arriver", PasseCompose, [6]string{"suis arrivé(e)", "es arrivé(e)", "est arrivé(e)", "sommes arrivé(e)s", "êtes arrivé(e)s", "sont arrivé(e)s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.infinitive+" "+string(tt.tense), func(t *testing.T) {
+			verb, err := Lookup(tt.infinitive)
+			if err != nil {
+				t.Fatalf("Lookup failed: %v", err)
+			}
+			if forms := verb.Conjugate(tt.tense); forms != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, forms)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, word := range []string{"", "bonjour", "au revoir", "s'il vous plaît", "mer", "grand"} {
+		if _, err := Lookup(word); !errors.Is(err, ErrNotAVerb) {
+			t.Errorf("Expected %q not to be a verb, got %v", word, err)
+		}
+	}
+
+	verb, err := Lookup(" Dire ")
+	if err != nil || verb.Infinitive != "dire" || !verb.Irregular || verb.Auxiliary != "avoir" {
+		t.Errorf("Expected dire to be an irregular verb with avoir, got %+v, %v", verb, err)
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		person   Person
+		form     string
+		expected string
+	}{
+		{FirstSingular, "parle", "je parle"},
+		{FirstSingular, "ai", "j'ai"},
+		{FirstSingular, "habite", "j'habite"},
+		{FirstSingular, "écris", "j'écris"},
+		{ThirdPlural, "ont", "ils/elles ont"},
+	}
+
+	for _, tt := range tests {
+		if text := Text(tt.person, tt.form); text != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, text)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		person   Person
+		form     string
+		answer   string
+		expected Match
+	}{
+		{FirstPlural, "irons", "irons", MatchExact},
+		{FirstPlural, "irons", "  Nous   irons ", MatchExact},
+		{FirstSingular, "ai", "j’ai", MatchExact},
+		{ThirdSingular, "est allé(e)", "elle est allée", MatchExact},
+		{ThirdPlural, "sont allé(e)s", "sont alles", MatchIgnoringAccents},
+		{SecondPlural, "êtes", "etes", MatchIgnoringAccents},
+		{SecondPlural, "êtes", "êtez", NoMatch},
+		{FirstSingular, "suis", "je", NoMatch},
+	}
+
+	for _, tt := range tests {
+		if match := Compare(tt.person, tt.form, tt.answer); match != tt.expected {
+			t.Errorf("Compare(%q, %q): expected %d, got %d", tt.form, tt.answer, tt.expected, match)
+		}
+	}
+}
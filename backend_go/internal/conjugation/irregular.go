@@ -0,0 +1,99 @@
+package conjugation
+
+// irregular is a verb whose present, future stem or participle do not follow
+// the rules of its group. imperfect is only set when the imperfect is not
+// built from the nous form of the present.
+type irregular struct {
+	present    [6]string
+	future     string
+	participle string
+	imperfect  string
+}
+
+func (i irregular) verb(infinitive string) *Verb {
+	return &Verb{
+		Infinitive:    infinitive,
+		Auxiliary:     auxiliary(infinitive),
+		Participle:    i.participle,
+		Irregular:     true,
+		present:       i.present,
+		imperfectStem: i.imperfect,
+		futureStem:    i.future,
+	}
+}
+
+var irregularVerbs = map[string]irregular{
+	"être":       {[6]string{"suis", "es", "est", "sommes", "êtes", "sont"}, "ser", "été", "ét"},
+	"avoir":      {[6]string{"ai", "as", "a", "avons", "avez", "ont"}, "aur", "eu", ""},
+	"aller":      {[6]string{"vais", "vas", "va", "allons", "allez", "vont"}, "ir", "allé", ""},
+	"faire":      {[6]string{"fais", "fais", "fait", "faisons", "faites", "font"}, "fer", "fait", ""},
+	"dire":       {[6]string{"dis", "dis", "dit", "disons", "dites", "disent"}, "dir", "dit", ""},
+	"venir":      {[6]string{"viens", "viens", "vient", "venons", "venez", "viennent"}, "viendr", "venu", ""},
+	"devenir":    {[6]string{"deviens", "deviens", "devient", "devenons", "devenez", "deviennent"}, "deviendr", "devenu", ""},
+	"revenir":    {[6]string{"reviens", "reviens", "revient", "revenons", "revenez", "reviennent"}, "reviendr", "revenu", ""},
+	"tenir":      {[6]string{"tiens", "tiens", "tient", "tenons", "tenez", "tiennent"}, "tiendr", "tenu", ""},
+	"obtenir":    {[6]string{"obtiens", "obtiens", "obtient", "obtenons", "obtenez", "obtiennent"}, "obtiendr", "obtenu", ""},
+	"pouvoir":    {[6]string{"peux", "peux", "peut", "pouvons", "pouvez", "peuvent"}, "pourr", "pu", ""},
+	"vouloir":    {[6]string{"veux", "veux", "veut", "voulons", "voulez", "veulent"}, "voudr", "voulu", ""},
+	"savoir":     {[6]string{"sais", "sais", "sait", "savons", "savez", "savent"}, "saur", "su", ""},
+	"devoir":     {[6]string{"dois", "dois", "doit", "devons", "devez", "doivent"}, "devr", "dû", ""},
+	"recevoir":   {[6]string{"reçois", "reçois", "reçoit", "recevons", "recevez", "reçoivent"}, "recevr", "reçu", ""},
+	"voir":       {[6]string{"vois", "vois", "voit", "voyons", "voyez", "voient"}, "verr", "vu", ""},
+	"prendre":    {[6]string{"prends", "prends", "prend", "prenons", "prenez", "prennent"}, "prendr", "pris", ""},
+	"apprendre":  {[6]string{"apprends", "apprends", "apprend", "apprenons", "apprenez", "apprennent"}, "apprendr", "appris", ""},
+	"comprendre": {[6]string{"comprends", "comprends", "comprend", "comprenons", "comprenez", "comprennent"}, "comprendr", "compris", ""},
+	"mettre":     {[6]string{"mets", "mets", "met", "mettons", "mettez", "mettent"}, "mettr", "mis", ""},
+	"partir":     {[6]string{"pars", "pars", "part", "partons", "partez", "partent"}, "partir", "parti", ""},
+	"sortir":     {[6]string{"sors", "sors", "sort", "sortons", "sortez", "sortent"}, "sortir", "sorti", ""},
+	"dormir":     {[6]string{"dors", "dors", "dort", "dormons", "dormez", "dorment"}, "dormir", "dormi", ""},
+	"sentir":     {[6]string{"sens", "sens", "sent", "sentons", "sentez", "sentent"}, "sentir", "senti", ""},
+	"servir":     {[6]string{"sers", "sers", "sert", "servons", "servez", "servent"}, "servir", "servi", ""},
+	"ouvrir":     {[6]string{"ouvre", "ouvres", "ouvre", "ouvrons", "ouvrez", "ouvrent"}, "ouvrir", "ouvert", ""},
+	"offrir":     {[6]string{"offre", "offres", "offre", "offrons", "offrez", "offrent"}, "offrir", "offert", ""},
+	"courir":     {[6]string{"cours", "cours", "court", "courons", "courez", "courent"}, "courr", "couru", ""},
+	"mourir":     {[6]string{"meurs", "meurs", "meurt", "mourons", "mourez", "meurent"}, "mourr", "mort", ""},
+	"lire":       {[6]string{"lis", "lis", "lit", "lisons", "lisez", "lisent"}, "lir", "lu", ""},
+	"écrire":     {[6]string{"écris", "écris", "écrit", "écrivons", "écrivez", "écrivent"}, "écrir", "écrit", ""},
+	"boire":      {[6]string{"bois", "bois", "boit", "buvons", "buvez", "boivent"}, "boir", "bu", ""},
+	"croire":     {[6]string{"crois", "crois", "croit", "croyons", "croyez", "croient"}, "croir", "cru", ""},
+	"connaître":  {[6]string{"connais", "connais", "connaît", "connaissons", "connaissez", "connaissent"}, "connaîtr", "connu", ""},
+	"naître":     {[6]string{"nais", "nais", "naît", "naissons", "naissez", "naissent"}, "naîtr", "né", ""},
+	"vivre":      {[6]string{"vis", "vis", "vit", "vivons", "vivez", "vivent"}, "vivr", "vécu", ""},
+	"acheter":    {[6]string{"achète", "achètes", "achète", "achetons", "achetez", "achètent"}, "achèter", "acheté", ""},
+	"appeler":    {[6]string{"appelle", "appelles", "appelle", "appelons", "appelez", "appellent"}, "appeller", "appelé", ""},
+	"préférer":   {[6]string{"préfère", "préfères", "préfère", "préférons", "préférez", "préfèrent"}, "préférer", "préféré", ""},
+	"envoyer":    {[6]string{"envoie", "envoies", "envoie", "envoyons", "envoyez", "envoient"}, "enverr", "envoyé", ""},
+	"payer":      {[6]string{"paie", "paies", "paie", "payons", "payez", "paient"}, "paier", "payé", ""},
+}
+
+// etreVerbs form the passé composé with être rather than avoir
+var etreVerbs = map[string]bool{
+	"aller":     true,
+	"venir":     true,
+	"devenir":   true,
+	"revenir":   true,
+	"arriver":   true,
+	"partir":    true,
+	"entrer":    true,
+	"rentrer":   true,
+	"sortir":    true,
+	"monter":    true,
+	"descendre": true,
+	"naître":    true,
+	"mourir":    true,
+	"rester":    true,
+	"retourner": true,
+	"tomber":    true,
+}
+
+func auxiliary(infinitive string) string {
+	if etreVerbs[infinitive] {
+		return "être"
+	}
+	return "avoir"
+}
+
+var (
+	etre  = irregularVerbs["être"].verb("être")
+	avoir = irregularVerbs["avoir"].verb("avoir")
+)
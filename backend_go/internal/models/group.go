@@ -5,11 +5,14 @@ import (
 	"time"
 )
 
-// Group represents a collection of words
+// Group represents a collection of words. All its words share the group's
+// source and target language.
 type Group struct {
-	ID         int64  `json:"id"`
-	Name       string `json:"name"`
-	WordsCount int    `json:"words_count"`
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	SourceLanguage string `json:"source_language"`
+	TargetLanguage string `json:"target_language"`
+	WordsCount     int    `json:"words_count"`
 }
 
 // StudyActivity represents a learning activity type
@@ -19,6 +22,66 @@ type StudyActivity struct {
 	URL          string `json:"url"`
 	ThumbnailURL string `json:"thumbnail_url"`
 	Description  string `json:"description"`
+	Scheduler    string `json:"scheduler"`
+}
+
+// User is an account. Role is one of admin, teacher or learner.
+type User struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditEntry records who performed a sensitive action
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	UserID    int64           `json:"user_id"`
+	UserEmail string          `json:"user_email"`
+	Action    string          `json:"action"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// HistoryReset records the study sessions and reviews of a user archived
+// by a reset. Nil scope fields match any session. Status is archived until
+// the reset is restored or expires, when its archive is purged.
+type HistoryReset struct {
+	ID              int64      `json:"id"`
+	UserID          int64      `json:"user_id"`
+	ActorID         int64      `json:"actor_id"`
+	GroupID         *int64     `json:"group_id"`
+	StudyActivityID *int64     `json:"study_activity_id"`
+	From            *time.Time `json:"from"`
+	To              *time.Time `json:"to"`
+	SessionsCount   int        `json:"sessions_count"`
+	ReviewsCount    int        `json:"reviews_count"`
+	Status          string     `json:"status"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	RestoredAt      *time.Time `json:"restored_at"`
+}
+
+// Class is a set of learners taught by a teacher
+type Class struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	TeacherID     int64     `json:"teacher_id"`
+	StudentsCount int       `json:"students_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ClassAssignment asks a class to study a group with an activity by DueAt
+type ClassAssignment struct {
+	ID                int64     `json:"id"`
+	ClassID           int64     `json:"class_id"`
+	GroupID           int64     `json:"group_id"`
+	GroupName         string    `json:"group_name"`
+	StudyActivityID   int64     `json:"study_activity_id"`
+	StudyActivityName string    `json:"study_activity_name"`
+	DueAt             time.Time `json:"due_at"`
+	CreatedAt         time.Time `json:"created_at"`
 }
 
 // StudySession represents a learning session
@@ -26,15 +89,25 @@ type StudySession struct {
 	ID              int64     `json:"id"`
 	GroupID         int64     `json:"group_id"`
 	StudyActivityID int64     `json:"study_activity_id"`
+	Status          string    `json:"status"`
 	CreatedAt       time.Time `json:"created_at"`
 }
 
-// WordReviewItem represents a single word review in a study session
+// WordReviewItem represents a single word review in a study session.
+// Quality is graded from 0 to 5 and Correct is true when it is 3 or more.
+// Tense and Person are set for conjugation drill reviews.
 type WordReviewItem struct {
 	ID             int64     `json:"id"`
 	WordID         int64     `json:"word_id"`
 	StudySessionID int64     `json:"study_session_id"`
+	ClientID       string    `json:"client_id,omitempty"`
 	Correct        bool      `json:"correct"`
+	Quality        int       `json:"quality"`
+	Answer         string    `json:"answer,omitempty"`
+	ResponseTimeMs *int      `json:"response_time_ms,omitempty"`
+	Direction      string    `json:"direction,omitempty"`
+	Tense          string    `json:"tense,omitempty"`
+	Person         string    `json:"person,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
@@ -44,6 +117,17 @@ type Word struct {
 	Parts json.RawMessage `json:"parts"`
 }
 
+// WordAttributes is the lexical data of a word. Gender and Plural describe
+// the source language side; an empty value is unknown.
+type WordAttributes struct {
+	PartOfSpeech string `json:"part_of_speech,omitempty"`
+	Gender       string `json:"gender,omitempty"`
+	Plural       string `json:"plural,omitempty"`
+	IPA          string `json:"ipa,omitempty"`
+	Register     string `json:"register,omitempty"`
+	Notes        string `json:"notes,omitempty"`
+}
+
 // ScanWord is a helper type for scanning JSON from database
 type ScanWord struct {
 	ID    int64  `json:"id"`
@@ -58,8 +142,35 @@ func (sw *ScanWord) ToWord() Word {
 	}
 }
 
-// WordParts represents the parts of a word
-type WordParts struct {
-	French  string `json:"french"`
-	English string `json:"english"`
+// Language is a language words can be written in. Fields are the parts a
+// word has in the language.
+type Language struct {
+	Code   string          `json:"code"`
+	Name   string          `json:"name"`
+	Fields []LanguageField `json:"fields"`
+}
+
+// LanguageField is one part of a word, such as its spelling or reading.
+// Values limits the field to a fixed set when it is not empty.
+type LanguageField struct {
+	Name     string   `json:"name"`
+	Required bool     `json:"required"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// ReviewState is the spaced repetition schedule of a word. Scheduler names
+// the algorithm that produced it; EaseFactor is used by SM-2, Box by
+// Leitner and Stability and Difficulty by FSRS.
+type ReviewState struct {
+	WordID         int64      `json:"word_id"`
+	Scheduler      string     `json:"scheduler"`
+	EaseFactor     float64    `json:"ease_factor"`
+	IntervalDays   int        `json:"interval_days"`
+	Repetitions    int        `json:"repetitions"`
+	Lapses         int        `json:"lapses"`
+	Box            int        `json:"box"`
+	Stability      float64    `json:"stability"`
+	Difficulty     float64    `json:"difficulty"`
+	DueAt          time.Time  `json:"due_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
 }
@@ -2,6 +2,11 @@ package service
 
 import (
 	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
@@ -17,14 +22,61 @@ func NewActivityService() *ActivityService {
 	}
 }
 
+// LaunchResponse describes a study session started from an activity
+type LaunchResponse struct {
+	StudySessionID  int64  `json:"study_session_id"`
+	StudyActivityID int64  `json:"study_activity_id"`
+	GroupID         int64  `json:"group_id"`
+	LaunchURL       string `json:"launch_url"`
+}
+
+// List returns a paginated list of study activities
+func (s *ActivityService) List(options ListOptions, page, perPage int) ([]models.StudyActivity, int, error) {
+	offset := (page - 1) * perPage
+
+	list, err := options.query(s.db, activityListSpec)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	err = s.db.QueryRow("SELECT COUNT(*) FROM study_activities").Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, name, url, COALESCE(thumbnail_url, ''), COALESCE(description, ''), COALESCE(scheduler, '')
+		FROM study_activities
+		ORDER BY `+list.orderBy+`
+		LIMIT ? OFFSET ?
+	`, perPage, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var activities []models.StudyActivity
+	for rows.Next() {
+		var activity models.StudyActivity
+		err := rows.Scan(&activity.ID, &activity.Name, &activity.URL, &activity.ThumbnailURL, &activity.Description, &activity.Scheduler)
+		if err != nil {
+			return nil, 0, err
+		}
+		activities = append(activities, activity)
+	}
+
+	return activities, total, nil
+}
+
 // Get returns a single study activity by ID
 func (s *ActivityService) Get(id int64) (*models.StudyActivity, error) {
 	var activity models.StudyActivity
 	err := s.db.QueryRow(`
-		SELECT id, name, url, thumbnail_url, description
+		SELECT id, name, url, COALESCE(thumbnail_url, ''), COALESCE(description, ''), COALESCE(scheduler, '')
 		FROM study_activities
 		WHERE id = ?
-	`, id).Scan(&activity.ID, &activity.Name, &activity.URL, &activity.ThumbnailURL, &activity.Description)
+	`, id).Scan(&activity.ID, &activity.Name, &activity.URL, &activity.ThumbnailURL, &activity.Description, &activity.Scheduler)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -36,27 +88,37 @@ func (s *ActivityService) Get(id int64) (*models.StudyActivity, error) {
 	return &activity, nil
 }
 
-// ListSessions returns study sessions for an activity
-func (s *ActivityService) ListSessions(activityID int64, page, perPage int) ([]models.StudySession, int, error) {
+// ListSessions returns the user's study sessions for an activity
+func (s *ActivityService) ListSessions(userID, activityID int64, pair LanguagePair, options ListOptions, page, perPage int) ([]models.StudySession, int, error) {
 	offset := (page - 1) * perPage
 
+	list, err := options.query(s.db, activitySessionListSpec)
+	if err != nil {
+		return nil, 0, err
+	}
+	languages, languageArgs := pair.filter("g")
+	args := append(append([]interface{}{activityID, userID}, languageArgs...), list.args...)
+
 	var total int
-	err := s.db.QueryRow(`
-		SELECT COUNT(*) 
-		FROM study_sessions 
-		WHERE study_activity_id = ?
-	`, activityID).Scan(&total)
+	err = s.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM study_sessions
+		WHERE study_activity_id = ? AND user_id = ?
+			AND group_id IN (SELECT g.id FROM groups g WHERE `+languages+`)
+			AND `+list.condition, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	rows, err := s.db.Query(`
-		SELECT id, group_id, study_activity_id, created_at
+		SELECT id, group_id, study_activity_id, status, created_at
 		FROM study_sessions
-		WHERE study_activity_id = ?
-		ORDER BY created_at DESC
+		WHERE study_activity_id = ? AND user_id = ?
+			AND group_id IN (SELECT g.id FROM groups g WHERE `+languages+`)
+			AND `+list.condition+`
+		ORDER BY `+list.orderBy+`
 		LIMIT ? OFFSET ?
-	`, activityID, perPage, offset)
+	`, append(args, perPage, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -65,7 +127,7 @@ func (s *ActivityService) ListSessions(activityID int64, page, perPage int) ([]m
 	var sessions []models.StudySession
 	for rows.Next() {
 		var session models.StudySession
-		err := rows.Scan(&session.ID, &session.GroupID, &session.StudyActivityID, &session.CreatedAt)
+		err := rows.Scan(&session.ID, &session.GroupID, &session.StudyActivityID, &session.Status, &session.CreatedAt)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -74,3 +136,190 @@ func (s *ActivityService) ListSessions(activityID int64, page, perPage int) ([]m
 
 	return sessions, total, nil
 }
+
+// Create registers a new study activity
+func (s *ActivityService) Create(activity models.StudyActivity) (*models.StudyActivity, error) {
+	if err := validateActivity(&activity); err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO study_activities (name, url, thumbnail_url, description, scheduler)
+		VALUES (?, ?, ?, ?, ?)
+	`, activity.Name, activity.URL, activity.ThumbnailURL, activity.Description, nullString(activity.Scheduler))
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// Update replaces a study activity's fields
+func (s *ActivityService) Update(id int64, activity models.StudyActivity) (*models.StudyActivity, error) {
+	if err := validateActivity(&activity); err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(`
+		UPDATE study_activities
+		SET name = ?, url = ?, thumbnail_url = ?, description = ?, scheduler = ?
+		WHERE id = ?
+	`, activity.Name, activity.URL, activity.ThumbnailURL, activity.Description, nullString(activity.Scheduler), id)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, notFound("study activity", id)
+	}
+
+	return s.Get(id)
+}
+
+// Delete removes a study activity, its class assignments and its study
+// sessions with their review items
+func (s *ActivityService) Delete(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := requireExists(tx, "study_activities", "study activity", id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM word_review_items
+		WHERE study_session_id IN (SELECT id FROM study_sessions WHERE study_activity_id = ?)
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM study_sessions WHERE study_activity_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM class_assignments WHERE study_activity_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM study_activities WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Launch starts a study session for the user with the activity on the given
+// group and returns the activity URL with the session and group filled in
+func (s *ActivityService) Launch(userID, activityID, groupID int64) (*LaunchResponse, error) {
+	activity, err := s.Get(activityID)
+	if err != nil {
+		return nil, err
+	}
+	if activity == nil {
+		return nil, notFound("study activity", activityID)
+	}
+
+	// The session is only kept if the launch URL can be built for it
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := requireReference(tx, "groups", "group", groupID); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at)
+		VALUES (?, ?, ?, ?)
+	`, userID, groupID, activityID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	launchURL, err := buildLaunchURL(activity.URL, groupID, sessionID)
+	if err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("study activity %d has an invalid url: %v", activityID, err)}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &LaunchResponse{
+		StudySessionID:  sessionID,
+		StudyActivityID: activityID,
+		GroupID:         groupID,
+		LaunchURL:       launchURL,
+	}, nil
+}
+
+func validateActivity(activity *models.StudyActivity) error {
+	activity.Name = strings.TrimSpace(activity.Name)
+	activity.URL = strings.TrimSpace(activity.URL)
+
+	if activity.Name == "" {
+		return &ValidationError{Message: "name is required"}
+	}
+	if activity.URL == "" {
+		return &ValidationError{Message: "url is required"}
+	}
+	if _, err := url.Parse(activity.URL); err != nil {
+		return &ValidationError{Message: "url is not a valid URL"}
+	}
+	if _, ok := SchedulerByName(activity.Scheduler); activity.Scheduler != "" && !ok {
+		return &ValidationError{Message: "unknown scheduler " + activity.Scheduler}
+	}
+	return nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// buildLaunchURL fills the {group_id} and {study_session_id} placeholders in
+// an activity URL, or appends them as query parameters if it has none
+func buildLaunchURL(activityURL string, groupID, sessionID int64) (string, error) {
+	group := strconv.FormatInt(groupID, 10)
+	session := strconv.FormatInt(sessionID, 10)
+
+	filled := strings.NewReplacer(
+		"{group_id}", group,
+		"{study_session_id}", session,
+	).Replace(activityURL)
+	if filled != activityURL {
+		return filled, nil
+	}
+
+	u, err := url.Parse(activityURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("group_id", group)
+	query.Set("study_session_id", session)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,533 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/anki"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+)
+
+// ankiIDBase offsets the Anki ids of exported notes, cards, decks and note
+// types. The ids are derived from the word, group and language pair so a
+// deck exported again updates the notes Anki imported before.
+const ankiIDBase = 1_500_000_000_000
+
+// ankiClientPrefix starts the client id of reviews imported from Anki,
+// followed by the id of the Anki review, so they are only imported once
+const ankiClientPrefix = "anki-"
+
+// ankiQualities maps the Anki answer buttons, from again (1) to easy (4),
+// onto the quality of a review
+var ankiQualities = map[int]int{
+	1: grades["again"],
+	2: grades["hard"],
+	3: grades["good"],
+	4: grades["easy"],
+}
+
+// importReview is an Anki review of the card of a word of a batch, studied
+// in the group of the card's deck
+type importReview struct {
+	word   int
+	group  groupKey
+	ord    int
+	review anki.Review
+}
+
+// readApkg reads the notes of an Anki package as words of the pair. A
+// note's cards put it in the groups named like their decks. Each field of a
+// note goes to the part or attribute options.Fields maps it to; without a
+// mapping, fields named like a part or attribute go there. Note types with
+// none of their fields mapped to a part put their first field in the first
+// part of the source language and their second in the first part of the
+// target language, as for Anki's Front and Back.
+func readApkg(q queryer, data []byte, options ImportOptions) (*importBatch, error) {
+	collection, err := anki.Read(data)
+	var formatErr *anki.FormatError
+	if errors.As(err, &formatErr) {
+		return nil, &ValidationError{Message: formatErr.Message}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pair := options.Pair.orDefault()
+	source, target, err := requireLanguagePair(q, pair)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]bool)
+	for _, language := range []*models.Language{source, target} {
+		for _, field := range language.Fields {
+			fields[field.Name] = true
+		}
+	}
+
+	names := make([]string, 0, len(options.Fields))
+	for name := range options.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []FieldError
+	for _, name := range names {
+		to := options.Fields[name]
+		if to == "" || to == "-" || fields[to] || columnAttribute(to, fields) != "" {
+			continue
+		}
+		errs = append(errs, FieldError{
+			Field:   "fields." + name,
+			Message: fmt.Sprintf("%s is not a field of %s or %s or a word attribute", to, source.Name, target.Name),
+		})
+	}
+	if len(errs) > 0 {
+		return nil, newFieldsError(errs)
+	}
+
+	mappings := make(map[int64][]string)
+	for id, model := range collection.Models {
+		mappings[id] = ankiFieldMapping(model, options.Fields, fields, source, target)
+	}
+
+	cards := make(map[int64]anki.Card)
+	noteCards := make(map[int64][]anki.Card)
+	for _, card := range collection.Cards {
+		cards[card.ID] = card
+		noteCards[card.NoteID] = append(noteCards[card.NoteID], card)
+	}
+	deckGroup := func(deckID int64) (groupKey, bool) {
+		deck, ok := collection.Decks[deckID]
+		return groupKey{strings.TrimSpace(deck.Name), pair}, ok && strings.TrimSpace(deck.Name) != ""
+	}
+
+	batch := &importBatch{}
+	notes := make(map[int64]int)
+	for i, note := range collection.Notes {
+		word := importWord{row: i + 1, pair: pair}
+
+		mapping, ok := mappings[note.ModelID]
+		if !ok {
+			word.errs = append(word.errs, FieldError{Field: "note_type", Message: "the note's type is missing from the package"})
+		}
+
+		parts := make(map[string]string)
+		attributes := make(map[string]string)
+		for j, value := range note.Fields {
+			if j >= len(mapping) || mapping[j] == "" {
+				continue
+			}
+			if value = anki.Text(value); value == "" {
+				continue
+			}
+			if fields[mapping[j]] {
+				parts[mapping[j]] = value
+			} else {
+				attributes[columnAttribute(mapping[j], fields)] = value
+			}
+		}
+		if word.parts, err = json.Marshal(parts); err != nil {
+			return nil, err
+		}
+		if len(attributes) > 0 {
+			if word.attributes, err = json.Marshal(attributes); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, card := range noteCards[note.ID] {
+			if group, ok := deckGroup(card.DeckID); ok && !containsGroup(word.groups, group) {
+				word.groups = append(word.groups, group)
+			}
+		}
+
+		notes[note.ID] = len(batch.words)
+		batch.words = append(batch.words, word)
+	}
+
+	if options.Reviews {
+		for _, review := range collection.Reviews {
+			card, ok := cards[review.CardID]
+			if !ok || review.Ease == 0 || review.Type == anki.ReviewManual {
+				continue
+			}
+			word, ok := notes[card.NoteID]
+			group, found := deckGroup(card.DeckID)
+			if !ok || !found {
+				continue
+			}
+			batch.reviews = append(batch.reviews, importReview{word: word, group: group, ord: card.Ord, review: review})
+		}
+	}
+
+	return batch, nil
+}
+
+// ankiFieldMapping returns the part or attribute each field of a note type
+// goes to, or "" for fields that are skipped
+func ankiFieldMapping(model anki.Model, mapped map[string]string, fields map[string]bool, source, target *models.Language) []string {
+	mapping := make([]string, len(model.Fields))
+	usesParts := false
+	for i, name := range model.Fields {
+		to, ok := mapped[name]
+		if !ok {
+			if lower := strings.ToLower(strings.TrimSpace(name)); fields[lower] || columnAttribute(lower, fields) != "" {
+				to = lower
+			}
+		}
+		if to == "-" {
+			to = ""
+		}
+		mapping[i] = to
+		usesParts = usesParts || fields[to]
+	}
+
+	if !usesParts {
+		for i, language := range []*models.Language{source, target} {
+			if i < len(mapping) && len(language.Fields) > 0 {
+				mapping[i] = language.Fields[0].Name
+			}
+		}
+	}
+	return mapping
+}
+
+func containsGroup(groups []groupKey, group groupKey) bool {
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// ankiSession collects the reviews imported into one study session
+type ankiSession struct {
+	id          int64
+	first, last time.Time
+	activeMs    int64
+}
+
+// importReviews stores Anki reviews as reviews by the user of the words
+// imported, in one completed study session per group, and rebuilds the
+// user's schedule of the words reviewed with the default scheduler.
+// Reviews imported before, reviews of words that failed and, when only
+// matching, reviews of decks without a group are skipped.
+func (imp *importer) importReviews(userID int64, reviews []importReview) error {
+	if len(reviews) == 0 || userID == 0 {
+		return nil
+	}
+
+	sessions := make(map[groupKey]*ankiSession)
+	var sessionKeys []groupKey
+	reviewed := make(map[int64]bool)
+
+	for _, r := range reviews {
+		wordID := imp.wordIDs[r.word]
+		if wordID == 0 {
+			continue
+		}
+
+		clientID := ankiClientPrefix + strconv.FormatInt(r.review.ID, 10)
+		var exists bool
+		err := imp.tx.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1
+				FROM word_review_items wri
+				JOIN study_sessions ss ON wri.study_session_id = ss.id
+				WHERE ss.user_id = ? AND wri.client_id = ?
+			)
+		`, userID, clientID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		reviewedAt := r.review.ReviewTime().UTC()
+		session := sessions[r.group]
+		if session == nil {
+			groupID, err := imp.group(r.group)
+			if err != nil {
+				return err
+			}
+			if groupID == 0 {
+				continue
+			}
+			result, err := imp.tx.Exec(`
+				INSERT INTO study_sessions (user_id, group_id, status, created_at)
+				VALUES (?, ?, ?, ?)
+			`, userID, groupID, SessionCompleted, reviewedAt)
+			if err != nil {
+				return err
+			}
+			id, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			session = &ankiSession{id: id, first: reviewedAt}
+			sessions[r.group] = session
+			sessionKeys = append(sessionKeys, r.group)
+		}
+		session.last = reviewedAt
+		session.activeMs += int64(r.review.Time)
+
+		input := ReviewInput{Quality: ankiQualities[r.review.Ease]}
+		if r.review.Time > 0 {
+			input.ResponseTimeMs = &r.review.Time
+		}
+		switch r.ord {
+		case 0:
+			input.Direction = DirectionRecognition
+		case 1:
+			input.Direction = DirectionProduction
+		}
+		_, err = imp.tx.Exec(`
+			INSERT INTO word_review_items (
+				word_id, study_session_id, client_id, correct, quality, response_time_ms, direction, created_at
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, wordID, session.id, clientID, input.Correct(), input.Quality, input.ResponseTimeMs,
+			nullString(input.Direction), reviewedAt)
+		if err != nil {
+			return err
+		}
+		reviewed[wordID] = true
+		imp.report.Summary.ReviewsCreated++
+	}
+
+	for _, key := range sessionKeys {
+		session := sessions[key]
+		_, err := imp.tx.Exec(`
+			UPDATE study_sessions
+			SET ended_at = ?, last_activity_at = ?, active_seconds = ?
+			WHERE id = ?
+		`, session.last, session.last, session.activeMs/1000, session.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	scheduler, err := defaultScheduler(imp.tx)
+	if err != nil {
+		return err
+	}
+	wordIDs := make([]int64, 0, len(reviewed))
+	for wordID := range reviewed {
+		wordIDs = append(wordIDs, wordID)
+	}
+	sort.Slice(wordIDs, func(i, j int) bool { return wordIDs[i] < wordIDs[j] })
+	for _, wordID := range wordIDs {
+		if _, err := replayReviewState(imp.tx, scheduler, userID, wordID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportApkg writes the words of a group as an Anki deck with the user's
+// schedule of each word and their review history. The note type has a field
+// for each part of the group's languages and for the attributes set on its
+// words; cards show the first part of the source language and ask for the
+// rest.
+func (s *ExportService) exportApkg(group *models.Group, userID int64) ([]byte, error) {
+	pair := LanguagePair{group.SourceLanguage, group.TargetLanguage}
+	source, target, err := requireLanguagePair(s.db, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := s.words("w.id IN (SELECT word_id FROM word_groups WHERE group_id = ?)", group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	isField := make(map[string]bool)
+	for _, language := range []*models.Language{source, target} {
+		for _, field := range language.Fields {
+			names = append(names, field.Name)
+			isField[field.Name] = true
+		}
+	}
+	partCount := len(names)
+	for i, name := range attributeColumns {
+		for _, word := range words {
+			if attributeValues(word.attributes)[i] != "" {
+				names = append(names, attributeColumn(name, isField))
+				break
+			}
+		}
+	}
+
+	back := "{{FrontSide}}\n\n<hr id=answer>\n\n"
+	for _, name := range names[1:] {
+		back += fmt.Sprintf("{{#%[1]s}}<div>{{%[1]s}}</div>{{/%[1]s}}\n", name)
+	}
+	model := anki.Model{
+		ID:        ankiIDBase + int64(crc32.ChecksumIEEE([]byte(pair.Source+" "+pair.Target))),
+		Name:      fmt.Sprintf("%s to %s", source.Name, target.Name),
+		Fields:    names,
+		Templates: []anki.Template{{Name: "Recognition", Front: "{{" + names[0] + "}}", Back: back}},
+	}
+	deck := anki.Deck{ID: ankiIDBase + group.ID, Name: group.Name}
+
+	states, err := s.reviewStates(userID, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Due dates count days from the start of the day of the earliest one
+	created := time.Now().UTC()
+	for _, state := range states {
+		if state.DueAt.Before(created) {
+			created = state.DueAt.UTC()
+		}
+	}
+	created = created.Truncate(anki.Day)
+
+	collection := &anki.Collection{
+		Created: created,
+		Models:  map[int64]anki.Model{model.ID: model},
+		Decks:   map[int64]anki.Deck{deck.ID: deck},
+	}
+	for i, word := range words {
+		var parts map[string]json.RawMessage
+		if err := json.Unmarshal(word.parts, &parts); err != nil {
+			return nil, err
+		}
+		values := attributeValues(word.attributes)
+
+		note := anki.Note{
+			ID:      ankiIDBase + word.id,
+			GUID:    "lang-portal-" + strconv.FormatInt(word.id, 10),
+			ModelID: model.ID,
+		}
+		for j, name := range names {
+			if j < partCount {
+				note.Fields = append(note.Fields, anki.HTML(partText(parts[name])))
+				continue
+			}
+			attribute := strings.TrimPrefix(name, attributePrefix)
+			for k, column := range attributeColumns {
+				if column == attribute {
+					note.Fields = append(note.Fields, anki.HTML(values[k]))
+				}
+			}
+		}
+		collection.Notes = append(collection.Notes, note)
+		collection.Cards = append(collection.Cards, ankiCard(note.ID, deck.ID, states[word.id], created, i+1))
+	}
+
+	if collection.Reviews, err = s.ankiReviews(userID, group.ID); err != nil {
+		return nil, err
+	}
+
+	return collection.Write()
+}
+
+// ankiCard returns the card of a note scheduled like the word. Words never
+// reviewed are new cards at their position in the deck.
+func ankiCard(noteID, deckID int64, state *models.ReviewState, created time.Time, position int) anki.Card {
+	card := anki.Card{ID: noteID, NoteID: noteID, DeckID: deckID, Type: anki.CardNew, Queue: anki.QueueNew, Due: int64(position)}
+	if state == nil || state.LastReviewedAt == nil {
+		return card
+	}
+
+	card.Type = anki.CardReview
+	card.Queue = anki.QueueReview
+	card.Due = int64(state.DueAt.UTC().Sub(created) / anki.Day)
+	card.Interval = max(state.IntervalDays, 1)
+	card.Factor = int(state.EaseFactor * 1000)
+	if card.Factor == 0 {
+		card.Factor = int(defaultEaseFactor * 1000)
+	}
+	card.Reps = state.Repetitions
+	card.Lapses = state.Lapses
+	return card
+}
+
+// reviewStates returns the user's schedule of the words of a group, by word
+func (s *ExportService) reviewStates(userID, groupID int64) (map[int64]*models.ReviewState, error) {
+	rows, err := s.db.Query(`
+		SELECT word_id FROM word_review_states
+		WHERE user_id = ? AND word_id IN (SELECT word_id FROM word_groups WHERE group_id = ?)
+	`, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var wordIDs []int64
+	for rows.Next() {
+		var wordID int64
+		if err := rows.Scan(&wordID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		wordIDs = append(wordIDs, wordID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	states := make(map[int64]*models.ReviewState)
+	for _, wordID := range wordIDs {
+		if states[wordID], err = loadReviewState(s.db, userID, wordID); err != nil {
+			return nil, err
+		}
+	}
+	return states, nil
+}
+
+// ankiReviews returns the user's reviews of the words of a group as Anki
+// reviews of their cards
+func (s *ExportService) ankiReviews(userID, groupID int64) ([]anki.Review, error) {
+	rows, err := s.db.Query(`
+		SELECT wri.word_id, COALESCE(wri.quality, CASE WHEN wri.correct THEN 4 ELSE 1 END),
+			COALESCE(wri.response_time_ms, 0), wri.created_at
+		FROM word_review_items wri
+		JOIN study_sessions ss ON wri.study_session_id = ss.id
+		WHERE ss.user_id = ? AND wri.word_id IN (SELECT word_id FROM word_groups WHERE group_id = ?)
+		ORDER BY wri.created_at, wri.id
+	`, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []anki.Review{}
+	var last int64
+	for rows.Next() {
+		var wordID int64
+		var quality, responseTime int
+		var reviewedAt time.Time
+		if err := rows.Scan(&wordID, &quality, &responseTime, &reviewedAt); err != nil {
+			return nil, err
+		}
+
+		// Anki identifies reviews by their time in milliseconds
+		id := max(reviewedAt.UnixMilli(), last+1)
+		last = id
+
+		ease := 1
+		for button, q := range ankiQualities {
+			if q <= quality && button > ease {
+				ease = button
+			}
+		}
+		reviews = append(reviews, anki.Review{
+			ID:     id,
+			CardID: ankiIDBase + wordID,
+			Ease:   ease,
+			Time:   responseTime,
+			Type:   anki.ReviewReview,
+		})
+	}
+	return reviews, rows.Err()
+}
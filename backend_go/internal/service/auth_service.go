@@ -0,0 +1,223 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MinPasswordLength is the shortest password accepted at registration
+	MinPasswordLength = 8
+
+	// TokenTTL is how long a token issued at login stays valid
+	TokenTTL = 30 * 24 * time.Hour
+)
+
+type AuthService struct {
+	db *sql.DB
+}
+
+func NewAuthService() *AuthService {
+	return &AuthService{
+		db: storage.GetDB(),
+	}
+}
+
+// AuthResponse is a user together with a freshly issued bearer token
+type AuthResponse struct {
+	User      models.User `json:"user"`
+	Token     string      `json:"token"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+// Register creates a learner account and logs it in. The first account to be
+// registered becomes an admin and adopts the study history recorded before
+// accounts existed.
+func (s *AuthService) Register(email, password, name string) (*AuthResponse, error) {
+	email = strings.TrimSpace(email)
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return nil, &ValidationError{Message: "email must be a valid email address"}
+	}
+	if len(password) < MinPasswordLength {
+		return nil, &ValidationError{Message: "password must be at least 8 characters"}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var taken bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email = ?)", email).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, &ConflictError{Message: "email is already registered"}
+	}
+
+	var first bool
+	if err := tx.QueryRow("SELECT NOT EXISTS(SELECT 1 FROM users)").Scan(&first); err != nil {
+		return nil, err
+	}
+	role := RoleLearner
+	if first {
+		role = RoleAdmin
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO users (email, name, password_hash, role, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, email, strings.TrimSpace(name), string(hash), role, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("UPDATE study_sessions SET user_id = ? WHERE user_id IS NULL", userID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("UPDATE word_review_states SET user_id = ? WHERE user_id IS NULL", userID); err != nil {
+		return nil, err
+	}
+
+	response, err := issueToken(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Login checks a user's password and issues a new bearer token
+func (s *AuthService) Login(email, password string) (*AuthResponse, error) {
+	var userID int64
+	var hash string
+	err := s.db.QueryRow(`
+		SELECT id, password_hash FROM users WHERE email = ?
+	`, strings.TrimSpace(email)).Scan(&userID, &hash)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if err == sql.ErrNoRows || bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return nil, &UnauthorizedError{Message: "invalid email or password"}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	response, err := issueToken(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Logout revokes a bearer token
+func (s *AuthService) Logout(token string) error {
+	_, err := s.db.Exec("DELETE FROM auth_tokens WHERE token_hash = ?", hashToken(token))
+	return err
+}
+
+// Authenticate returns the user a bearer token was issued to, or an
+// UnauthorizedError if the token is unknown or expired
+func (s *AuthService) Authenticate(token string) (*models.User, error) {
+	var user models.User
+	var expiresAt time.Time
+	err := s.db.QueryRow(`
+		SELECT u.id, u.email, u.name, u.role, u.created_at, t.expires_at
+		FROM auth_tokens t
+		JOIN users u ON t.user_id = u.id
+		WHERE t.token_hash = ?
+	`, hashToken(token)).Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt, &expiresAt)
+
+	if err == sql.ErrNoRows {
+		return nil, &UnauthorizedError{Message: "invalid or expired token"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !time.Now().Before(expiresAt) {
+		if err := s.Logout(token); err != nil {
+			return nil, err
+		}
+		return nil, &UnauthorizedError{Message: "invalid or expired token"}
+	}
+
+	return &user, nil
+}
+
+// issueToken stores a new random token for the user. Only its hash is kept,
+// so a leaked database does not leak usable tokens.
+func issueToken(tx *sql.Tx, userID int64) (*AuthResponse, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(TokenTTL)
+
+	_, err = tx.Exec(`
+		INSERT INTO auth_tokens (token_hash, user_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?)
+	`, hashToken(token), userID, now, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := getUser(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthResponse{User: *user, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// newToken returns 32 random bytes, hex encoded
+func newToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
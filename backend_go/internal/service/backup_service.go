@@ -0,0 +1,371 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+// The reasons snapshots are taken, besides the resets they precede
+const (
+	SnapshotManual     = "manual"
+	SnapshotScheduled  = "scheduled"
+	SnapshotPreRestore = "pre_restore"
+)
+
+// SnapshotRetention is how many snapshots of each reason are kept. Taking a
+// snapshot deletes the oldest of its reason beyond that.
+var SnapshotRetention = map[string]int{
+	SnapshotManual:     20,
+	SnapshotScheduled:  7,
+	SnapshotPreRestore: 5,
+	ActionResetHistory: 10,
+	ActionFullReset:    10,
+}
+
+// ActionRestoreBackup replaces the database with a snapshot. It must be
+// confirmed like a reset.
+const ActionRestoreBackup = "restore_backup"
+
+// snapshotTimeFormat is the time in snapshot file names, sortable as text
+const snapshotTimeFormat = "20060102T150405.000Z"
+
+var snapshotName = regexp.MustCompile(`^snapshot-(\d{8}T\d{6}\.\d{3}Z)-([a-z_]+)\.db$`)
+
+// Snapshot is a copy of the database in the backup directory
+type Snapshot struct {
+	Name      string    `json:"name"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	Size      int64     `json:"size"`
+}
+
+type BackupService struct {
+	db  *sql.DB
+	dir string
+}
+
+func NewBackupService() *BackupService {
+	return &BackupService{
+		db:  storage.GetDB(),
+		dir: storage.BackupDir(),
+	}
+}
+
+// Enabled reports whether a backup directory is configured
+func (s *BackupService) Enabled() bool {
+	return s.dir != ""
+}
+
+// Snapshot copies the database to a new snapshot in the backup directory
+// and prunes the snapshots of the same reason beyond SnapshotRetention
+func (s *BackupService) Snapshot(reason string) (*Snapshot, error) {
+	if !s.Enabled() {
+		return nil, &ValidationError{Message: "backups are disabled: no backup directory is configured"}
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	createdAt := time.Now().UTC()
+	name := fmt.Sprintf("snapshot-%s-%s.db", createdAt.Format(snapshotTimeFormat), reason)
+	path := filepath.Join(s.dir, name)
+
+	// The snapshot is written under another name first so a failed or
+	// running snapshot is never listed
+	partial := path + ".partial"
+	os.Remove(partial)
+	if err := storage.Snapshot(s.db, partial); err != nil {
+		os.Remove(partial)
+		return nil, err
+	}
+	if err := os.Rename(partial, path); err != nil {
+		os.Remove(partial)
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// A snapshot that was taken is not failed by old ones left behind
+	if keep, ok := SnapshotRetention[reason]; ok {
+		if _, err := s.Prune(reason, keep); err != nil {
+			log.Printf("Failed to prune %s snapshots: %v", reason, err)
+		}
+	}
+	return &Snapshot{Name: name, Reason: reason, CreatedAt: createdAt.Truncate(time.Millisecond), Size: info.Size()}, nil
+}
+
+// List returns the snapshots of the backup directory, newest first
+func (s *BackupService) List() ([]Snapshot, error) {
+	snapshots := []Snapshot{}
+	if !s.Enabled() {
+		return snapshots, nil
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return snapshots, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		snapshot, ok := parseSnapshotName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Size = info.Size()
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name > snapshots[j].Name })
+	return snapshots, nil
+}
+
+func parseSnapshotName(name string) (Snapshot, bool) {
+	match := snapshotName.FindStringSubmatch(name)
+	if match == nil {
+		return Snapshot{}, false
+	}
+	createdAt, err := time.Parse(snapshotTimeFormat, match[1])
+	if err != nil {
+		return Snapshot{}, false
+	}
+	return Snapshot{Name: name, Reason: match[2], CreatedAt: createdAt}, true
+}
+
+// Prune deletes the snapshots taken for reason beyond the newest keep and
+// returns how many were deleted
+func (s *BackupService) Prune(reason string, keep int) (int, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	kept := 0
+	for _, snapshot := range snapshots {
+		if snapshot.Reason != reason {
+			continue
+		}
+		if kept < keep {
+			kept++
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, snapshot.Name)); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// RunSnapshots takes a scheduled snapshot every interval until stop is
+// closed
+func (s *BackupService) RunSnapshots(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			snapshot, err := s.Snapshot(SnapshotScheduled)
+			if err != nil {
+				log.Printf("Failed to take a scheduled snapshot: %v", err)
+				continue
+			}
+			log.Printf("Took scheduled snapshot %s", snapshot.Name)
+		}
+	}
+}
+
+// Restore replaces the database with a snapshot on behalf of actorID, who
+// must confirm it with a restore_backup confirmation token. See
+// RestoreSnapshot. The restore is recorded in the audit log of the restored
+// database when it knows the actor.
+func (s *BackupService) Restore(actorID int64, name, confirmationToken string) (*Snapshot, error) {
+	if err := checkConfirmation(s.db, actorID, ActionRestoreBackup, confirmationToken); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.restore(name, func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if err := consumeConfirmation(tx, actorID, ActionRestoreBackup, confirmationToken); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	var known bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", actorID).Scan(&known); err != nil {
+		return nil, err
+	}
+	if known {
+		err := recordAudit(tx, actorID, ActionRestoreBackup, map[string]interface{}{
+			"snapshot":          name,
+			"previous_snapshot": previous.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return previous, tx.Commit()
+}
+
+// RestoreSnapshot replaces the database with a snapshot and returns the
+// snapshot of the database it replaced. The snapshot must pass an integrity
+// check and have a schema this server can migrate: no migration newer than
+// the server's and none edited since it ran. Older snapshots are migrated
+// before they are swapped in. Confirmation tokens pending when the snapshot
+// was taken are void.
+func (s *BackupService) RestoreSnapshot(name string) (*Snapshot, error) {
+	return s.restore(name, nil)
+}
+
+// restore restores a snapshot, calling confirm, when given, once the
+// snapshot has been checked and the database snapshotted
+func (s *BackupService) restore(name string, confirm func() error) (*Snapshot, error) {
+	if !s.Enabled() {
+		return nil, &ValidationError{Message: "backups are disabled: no backup directory is configured"}
+	}
+	missing := &NotFoundError{Message: fmt.Sprintf("snapshot %s not found", name)}
+	if _, ok := parseSnapshotName(name); !ok {
+		return nil, missing
+	}
+	path := filepath.Join(s.dir, name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, missing
+	} else if err != nil {
+		return nil, err
+	}
+
+	// The snapshot is checked and migrated as a copy so it stays as it was
+	staged, err := s.stage(path)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(staged)
+
+	previous, err := s.Snapshot(SnapshotPreRestore)
+	if err != nil {
+		return nil, err
+	}
+	if confirm != nil {
+		if err := confirm(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := storage.Restore(s.db, staged); err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Exec("DELETE FROM confirmation_tokens"); err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// stage copies a snapshot to a temporary file, checks it and migrates it to
+// the server's schema, and returns the file's path
+func (s *BackupService) stage(path string) (string, error) {
+	staged, err := copyToTemp(path, s.dir)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkSnapshot(staged); err != nil {
+		os.Remove(staged)
+		return "", err
+	}
+	return staged, nil
+}
+
+func copyToTemp(path, dir string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(dir, "restore-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	return dst.Name(), nil
+}
+
+// checkSnapshot checks the integrity and schema version of a database and
+// applies the migrations it is missing
+func checkSnapshot(path string) error {
+	db, err := storage.Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow("PRAGMA quick_check").Scan(&result); err != nil {
+		return &ValidationError{Message: "the snapshot is not a readable database: " + err.Error()}
+	}
+	if result != "ok" {
+		return &ValidationError{Message: "the snapshot is corrupt: " + result}
+	}
+
+	migrator, err := storage.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	version, err := migrator.Version()
+	if err != nil {
+		return &ValidationError{Message: "the snapshot's schema version cannot be read: " + err.Error()}
+	}
+	migrations := migrator.Migrations()
+	latest := migrations[len(migrations)-1].Version
+	if version == 0 {
+		return &ValidationError{Message: "the snapshot has no applied migrations"}
+	}
+	if version > latest {
+		return &ValidationError{Message: fmt.Sprintf("the snapshot's schema version %d is newer than this server's %d", version, latest)}
+	}
+
+	if _, err := migrator.Up(false); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("the snapshot's schema version %d cannot be migrated: %v", version, err)}
+	}
+	return nil
+}
@@ -0,0 +1,679 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+// ClassService runs classes. Classes are visible to their teacher, their
+// students and admins; only the teacher and admins may change them or see
+// how individual students are doing. Classes the actor cannot see are
+// reported as not found.
+type ClassService struct {
+	db        *sql.DB
+	dashboard *DashboardService
+}
+
+func NewClassService() *ClassService {
+	return &ClassService{
+		db:        storage.GetDB(),
+		dashboard: NewDashboardService(),
+	}
+}
+
+// AssignmentResponse is an assignment with how many students completed it
+type AssignmentResponse struct {
+	models.ClassAssignment
+	CompletedStudents int `json:"completed_students"`
+	TotalStudents     int `json:"total_students"`
+}
+
+// StudentProgress is how far a student got with an assignment. A student
+// completed it once they finished a study session of the assignment's group
+// and activity started after it was assigned; it is late if that happened
+// after the due date. Accuracy is the percentage of correct reviews in those
+// sessions.
+type StudentProgress struct {
+	UserID        int64      `json:"user_id"`
+	Email         string     `json:"email"`
+	Name          string     `json:"name"`
+	Completed     bool       `json:"completed"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	Late          bool       `json:"late"`
+	WordsReviewed int        `json:"words_reviewed"`
+	WordsTotal    int        `json:"words_total"`
+	ReviewsCount  int        `json:"reviews_count"`
+	Accuracy      float64    `json:"accuracy"`
+}
+
+// AssignmentProgress is the progress of every student of a class with one
+// assignment
+type AssignmentProgress struct {
+	Assignment models.ClassAssignment `json:"assignment"`
+	Students   []StudentProgress      `json:"students"`
+}
+
+// StudentDashboard is the dashboard of one student as a teacher sees it
+type StudentDashboard struct {
+	User                 models.User       `json:"user"`
+	LastStudySession     *LastStudySession `json:"last_study_session"`
+	StudyProgress        *StudyProgress    `json:"study_progress"`
+	QuickStats           *QuickStats       `json:"quick_stats"`
+	AssignmentsCompleted int               `json:"assignments_completed"`
+	AssignmentsTotal     int               `json:"assignments_total"`
+}
+
+// ClassDashboard is the teacher's overview of a class
+type ClassDashboard struct {
+	Class    models.Class       `json:"class"`
+	Students []StudentDashboard `json:"students"`
+}
+
+const classQuery = `
+	SELECT
+		c.id,
+		c.name,
+		c.teacher_id,
+		(SELECT COUNT(*) FROM class_members m WHERE m.class_id = c.id) as students_count,
+		c.created_at
+	FROM classes c
+`
+
+// classVisibleTo limits classQuery to classes the user teaches or attends.
+// It takes the user id twice.
+const classVisibleTo = `
+	(c.teacher_id = ? OR EXISTS (
+		SELECT 1 FROM class_members m WHERE m.class_id = c.id AND m.user_id = ?
+	))
+`
+
+func scanClass(row rowScanner) (*models.Class, error) {
+	var class models.Class
+	err := row.Scan(&class.ID, &class.Name, &class.TeacherID, &class.StudentsCount, &class.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// List returns a page of the classes the actor teaches or attends, or of
+// all classes for admins
+func (s *ClassService) List(actor *models.User, page, perPage int) ([]models.Class, int, error) {
+	offset := (page - 1) * perPage
+
+	where := "WHERE " + classVisibleTo
+	args := []interface{}{actor.ID, actor.ID}
+	if actor.Role == RoleAdmin {
+		where = ""
+		args = nil
+	}
+
+	var total int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM classes c "+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(classQuery+where+`
+		ORDER BY c.name, c.id
+		LIMIT ? OFFSET ?
+	`, append(args, perPage, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var classes []models.Class
+	for rows.Next() {
+		class, err := scanClass(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		classes = append(classes, *class)
+	}
+
+	return classes, total, rows.Err()
+}
+
+// Get returns a class the actor may see, or nil otherwise
+func (s *ClassService) Get(actor *models.User, id int64) (*models.Class, error) {
+	class, err := s.access(s.db, actor, id, false)
+	if _, ok := err.(*NotFoundError); ok {
+		return nil, nil
+	}
+	return class, err
+}
+
+// Create starts a class taught by the actor
+func (s *ClassService) Create(actor *models.User, name string) (*models.Class, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ValidationError{Message: "name is required"}
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO classes (name, teacher_id, created_at) VALUES (?, ?, ?)
+	`, name, actor.ID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.access(s.db, actor, id, false)
+}
+
+// Rename changes the name of a class
+func (s *ClassService) Rename(actor *models.User, id int64, name string) (*models.Class, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ValidationError{Message: "name is required"}
+	}
+
+	if _, err := s.access(s.db, actor, id, true); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec("UPDATE classes SET name = ? WHERE id = ?", name, id); err != nil {
+		return nil, err
+	}
+
+	return s.access(s.db, actor, id, false)
+}
+
+// Delete removes a class with its enrollments and assignments. The
+// students' study history is kept.
+func (s *ClassService) Delete(actor *models.User, id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := s.access(tx, actor, id, true); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM class_assignments WHERE class_id = ?;
+		DELETE FROM class_members WHERE class_id = ?;
+		DELETE FROM classes WHERE id = ?;
+	`, id, id, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListStudents returns a page of the students enrolled in a class
+func (s *ClassService) ListStudents(actor *models.User, classID int64, page, perPage int) ([]models.User, int, error) {
+	offset := (page - 1) * perPage
+
+	if _, err := s.access(s.db, actor, classID, true); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM class_members WHERE class_id = ?", classID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	students, err := s.students(s.db, classID, perPage, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return students, total, nil
+}
+
+// AddStudents enrolls existing learners in a class, ignoring learners
+// already enrolled. Teachers and admins cannot be enrolled.
+func (s *ClassService) AddStudents(actor *models.User, classID int64, userIDs []int64) (*models.Class, error) {
+	if len(userIDs) == 0 {
+		return nil, &ValidationError{Message: "user_ids must not be empty"}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := s.access(tx, actor, classID, true); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for _, userID := range userIDs {
+		if err := requireLearner(tx, userID); err != nil {
+			return nil, err
+		}
+
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO class_members (class_id, user_id, enrolled_at) VALUES (?, ?, ?)
+		`, classID, userID, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.access(s.db, actor, classID, false)
+}
+
+// requireLearner returns a ValidationError unless the user exists and is a
+// learner
+func requireLearner(q queryer, userID int64) error {
+	var role string
+	err := q.QueryRow("SELECT role FROM users WHERE id = ?", userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return &ValidationError{Message: fmt.Sprintf("user %d does not exist", userID)}
+	}
+	if err != nil {
+		return err
+	}
+	if role != RoleLearner {
+		return &ValidationError{Message: fmt.Sprintf("user %d is a %s; only learners can be enrolled", userID, role)}
+	}
+	return nil
+}
+
+// RemoveStudent unenrolls a student from a class. Their study history is
+// kept.
+func (s *ClassService) RemoveStudent(actor *models.User, classID, userID int64) error {
+	if _, err := s.access(s.db, actor, classID, true); err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec("DELETE FROM class_members WHERE class_id = ? AND user_id = ?", classID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound("student", userID)
+	}
+
+	return nil
+}
+
+// ListAssignments returns the assignments of a class, soonest due first
+func (s *ClassService) ListAssignments(actor *models.User, classID int64) ([]AssignmentResponse, error) {
+	if _, err := s.access(s.db, actor, classID, false); err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.assignments(classID)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []AssignmentResponse
+	for _, assignment := range assignments {
+		progress, err := s.progress(assignment)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, summarize(assignment, progress))
+	}
+
+	return responses, nil
+}
+
+// CreateAssignment asks the class to study a group with an activity by dueAt
+func (s *ClassService) CreateAssignment(actor *models.User, classID, groupID, studyActivityID int64, dueAt time.Time) (*AssignmentResponse, error) {
+	if _, err := s.access(s.db, actor, classID, true); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if !dueAt.After(now) {
+		return nil, &ValidationError{Message: "due_at must be in the future"}
+	}
+	if err := requireReference(s.db, "groups", "group_id", groupID); err != nil {
+		return nil, err
+	}
+	if err := requireReference(s.db, "study_activities", "study_activity_id", studyActivityID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO class_assignments (class_id, group_id, study_activity_id, due_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, classID, groupID, studyActivityID, srsTime(dueAt), srsTime(now))
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.assignment(classID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.progress(*assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	response := summarize(*assignment, progress)
+	return &response, nil
+}
+
+// DeleteAssignment removes an assignment from a class
+func (s *ClassService) DeleteAssignment(actor *models.User, classID, assignmentID int64) error {
+	if _, err := s.access(s.db, actor, classID, true); err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec("DELETE FROM class_assignments WHERE id = ? AND class_id = ?", assignmentID, classID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound("assignment", assignmentID)
+	}
+
+	return nil
+}
+
+// AssignmentProgress returns every student's progress with an assignment
+func (s *ClassService) AssignmentProgress(actor *models.User, classID, assignmentID int64) (*AssignmentProgress, error) {
+	if _, err := s.access(s.db, actor, classID, true); err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.assignment(classID, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.progress(*assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AssignmentProgress{Assignment: *assignment, Students: students}, nil
+}
+
+// Dashboard returns the dashboard metrics of every student in a class
+// together with how many of the class's assignments they completed
+func (s *ClassService) Dashboard(actor *models.User, classID int64) (*ClassDashboard, error) {
+	class, err := s.access(s.db, actor, classID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.students(s.db, classID, -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.assignments(classID)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := make(map[int64]int)
+	for _, assignment := range assignments {
+		progress, err := s.progress(assignment)
+		if err != nil {
+			return nil, err
+		}
+		for _, student := range progress {
+			if student.Completed {
+				completed[student.UserID]++
+			}
+		}
+	}
+
+	dashboard := &ClassDashboard{Class: *class, Students: []StudentDashboard{}}
+	for _, student := range students {
+		entry := StudentDashboard{
+			User:                 student,
+			AssignmentsCompleted: completed[student.ID],
+			AssignmentsTotal:     len(assignments),
+		}
+
+		if entry.LastStudySession, err = s.dashboard.GetLastStudySession(student.ID); err != nil {
+			return nil, err
+		}
+		if entry.StudyProgress, err = s.dashboard.GetStudyProgress(student.ID, LanguagePair{}); err != nil {
+			return nil, err
+		}
+		if entry.QuickStats, err = s.dashboard.GetQuickStats(student.ID, LanguagePair{}); err != nil {
+			return nil, err
+		}
+
+		dashboard.Students = append(dashboard.Students, entry)
+	}
+
+	return dashboard, nil
+}
+
+// access returns the class if the actor may see it, and with manage set
+// also change it. Classes the actor cannot see are reported as not found,
+// classes they can see but not manage as forbidden.
+func (s *ClassService) access(q queryer, actor *models.User, id int64, manage bool) (*models.Class, error) {
+	class, err := scanClass(q.QueryRow(classQuery+"WHERE c.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, notFound("class", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.Role == RoleAdmin || class.TeacherID == actor.ID {
+		return class, nil
+	}
+
+	var enrolled bool
+	err = q.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM class_members WHERE class_id = ? AND user_id = ?)
+	`, id, actor.ID).Scan(&enrolled)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, notFound("class", id)
+	}
+	if manage {
+		return nil, &ForbiddenError{Message: "only the class's teacher can do this"}
+	}
+
+	return class, nil
+}
+
+// students returns the students of a class by name. A negative limit
+// returns all of them.
+func (s *ClassService) students(q queryer, classID int64, limit, offset int) ([]models.User, error) {
+	rows, err := q.Query(`
+		SELECT u.id, u.email, u.name, u.role, u.created_at
+		FROM class_members m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.class_id = ?
+		ORDER BY u.name, u.id
+		LIMIT ? OFFSET ?
+	`, classID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := []models.User{}
+	for rows.Next() {
+		student, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		students = append(students, *student)
+	}
+
+	return students, rows.Err()
+}
+
+const assignmentQuery = `
+	SELECT a.id, a.class_id, a.group_id, g.name, a.study_activity_id, sa.name, a.due_at, a.created_at
+	FROM class_assignments a
+	JOIN groups g ON a.group_id = g.id
+	JOIN study_activities sa ON a.study_activity_id = sa.id
+`
+
+func scanAssignment(row rowScanner) (*models.ClassAssignment, error) {
+	var assignment models.ClassAssignment
+	err := row.Scan(
+		&assignment.ID,
+		&assignment.ClassID,
+		&assignment.GroupID,
+		&assignment.GroupName,
+		&assignment.StudyActivityID,
+		&assignment.StudyActivityName,
+		&assignment.DueAt,
+		&assignment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (s *ClassService) assignment(classID, id int64) (*models.ClassAssignment, error) {
+	assignment, err := scanAssignment(s.db.QueryRow(assignmentQuery+"WHERE a.id = ? AND a.class_id = ?", id, classID))
+	if err == sql.ErrNoRows {
+		return nil, notFound("assignment", id)
+	}
+	return assignment, err
+}
+
+func (s *ClassService) assignments(classID int64) ([]models.ClassAssignment, error) {
+	rows, err := s.db.Query(assignmentQuery+`
+		WHERE a.class_id = ?
+		ORDER BY a.due_at, a.id
+	`, classID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []models.ClassAssignment
+	for rows.Next() {
+		assignment, err := scanAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, *assignment)
+	}
+
+	return assignments, rows.Err()
+}
+
+// progress returns the progress of each student of the assignment's class
+func (s *ClassService) progress(assignment models.ClassAssignment) ([]StudentProgress, error) {
+	rows, err := s.db.Query(`
+		SELECT
+			u.id,
+			u.email,
+			u.name,
+			(
+				SELECT strftime('%Y-%m-%dT%H:%M:%SZ', MIN(julianday(done.ended_at)))
+				FROM study_sessions done
+				WHERE done.user_id = u.id
+				AND done.group_id = a.group_id
+				AND done.study_activity_id = a.study_activity_id
+				AND done.status = ?
+				AND julianday(done.created_at) >= julianday(a.created_at)
+			) as completed_at,
+			(SELECT words_count FROM groups WHERE id = a.group_id) as words_total,
+			COUNT(DISTINCT wri.word_id) as words_reviewed,
+			COUNT(wri.id) as reviews_count,
+			COALESCE(SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END), 0) as correct_count
+		FROM class_assignments a
+		JOIN class_members m ON m.class_id = a.class_id
+		JOIN users u ON m.user_id = u.id
+		LEFT JOIN study_sessions ss ON ss.user_id = u.id
+			AND ss.group_id = a.group_id
+			AND ss.study_activity_id = a.study_activity_id
+			AND julianday(ss.created_at) >= julianday(a.created_at)
+		LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
+		WHERE a.id = ?
+		GROUP BY u.id
+		ORDER BY u.name, u.id
+	`, SessionCompleted, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := []StudentProgress{}
+	for rows.Next() {
+		var student StudentProgress
+		var completedAt sql.NullString
+		var correct int
+		err := rows.Scan(
+			&student.UserID,
+			&student.Email,
+			&student.Name,
+			&completedAt,
+			&student.WordsTotal,
+			&student.WordsReviewed,
+			&student.ReviewsCount,
+			&correct,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if completedAt.Valid {
+			at, err := time.Parse(time.RFC3339, completedAt.String)
+			if err != nil {
+				return nil, err
+			}
+			student.Completed = true
+			student.CompletedAt = &at
+			student.Late = at.After(assignment.DueAt)
+		}
+		if student.ReviewsCount > 0 {
+			student.Accuracy = float64(correct) / float64(student.ReviewsCount) * 100
+		}
+
+		students = append(students, student)
+	}
+
+	return students, rows.Err()
+}
+
+func summarize(assignment models.ClassAssignment, progress []StudentProgress) AssignmentResponse {
+	response := AssignmentResponse{ClassAssignment: assignment, TotalStudents: len(progress)}
+	for _, student := range progress {
+		if student.Completed {
+			response.CompletedStudents++
+		}
+	}
+	return response
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/conjugation"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+// conjugationLanguage is the source language of the words that can be
+// conjugated
+const conjugationLanguage = "fr"
+
+const (
+	// DefaultDrillSize and MaxDrillSize bound the prompts of a conjugation
+	// drill
+	DefaultDrillSize = 20
+	MaxDrillSize     = 100
+)
+
+// ConjugationService conjugates French verbs and runs conjugation drills
+type ConjugationService struct {
+	db       *sql.DB
+	sessions *SessionService
+}
+
+func NewConjugationService() *ConjugationService {
+	return &ConjugationService{
+		db:       storage.GetDB(),
+		sessions: NewSessionService(),
+	}
+}
+
+// ConjugatedForm is a verb conjugated for one person. Text adds the subject
+// pronoun to Form.
+type ConjugatedForm struct {
+	Person  string `json:"person"`
+	Pronoun string `json:"pronoun"`
+	Form    string `json:"form"`
+	Text    string `json:"text"`
+}
+
+// TenseConjugation is a verb conjugated in one tense
+type TenseConjugation struct {
+	Tense string           `json:"tense"`
+	Forms []ConjugatedForm `json:"forms"`
+}
+
+// ConjugationsResponse is the conjugation table of a word
+type ConjugationsResponse struct {
+	WordID     int64              `json:"word_id"`
+	Infinitive string             `json:"infinitive"`
+	Auxiliary  string             `json:"auxiliary"`
+	Participle string             `json:"participle"`
+	Irregular  bool               `json:"irregular"`
+	Tenses     []TenseConjugation `json:"tenses"`
+}
+
+// DrillPrompt asks for a verb in one tense and person. Answer is the
+// expected form, for clients that let learners check themselves.
+type DrillPrompt struct {
+	WordID     int64           `json:"word_id"`
+	Infinitive string          `json:"infinitive"`
+	Parts      json.RawMessage `json:"parts"`
+	Tense      string          `json:"tense"`
+	Person     string          `json:"person"`
+	Pronoun    string          `json:"pronoun"`
+	Answer     string          `json:"answer"`
+}
+
+// DrillAnswer is a learner's answer to a drill prompt
+type DrillAnswer struct {
+	WordID         int64
+	Tense          string
+	Person         string
+	Answer         string
+	ResponseTimeMs *int
+}
+
+// DrillReviewResponse is the review recorded for a drill answer together
+// with the form that was expected
+type DrillReviewResponse struct {
+	models.WordReviewItem
+	Expected string `json:"expected"`
+}
+
+// Conjugations returns the conjugation table of a word in the given tenses,
+// or every tense if none are given. Returns nil if the word does not exist.
+func (s *ConjugationService) Conjugations(id int64, tenses []string) (*ConjugationsResponse, error) {
+	parsed, err := parseTenses(tenses)
+	if err != nil {
+		return nil, err
+	}
+
+	verb, err := loadVerb(s.db, id)
+	if err != nil || verb == nil {
+		return nil, err
+	}
+
+	response := &ConjugationsResponse{
+		WordID:     id,
+		Infinitive: verb.Infinitive,
+		Auxiliary:  verb.Auxiliary,
+		Participle: verb.Participle,
+		Irregular:  verb.Irregular,
+	}
+	for _, tense := range parsed {
+		table := TenseConjugation{Tense: string(tense)}
+		for _, person := range conjugation.Persons {
+			form := verb.Form(tense, person)
+			table.Forms = append(table.Forms, ConjugatedForm{
+				Person:  person.String(),
+				Pronoun: person.Pronoun(),
+				Form:    form,
+				Text:    conjugation.Text(person, form),
+			})
+		}
+		response.Tenses = append(response.Tenses, table)
+	}
+
+	return response, nil
+}
+
+// Drill returns count prompts for the verbs of a French group in the given
+// tenses, or every tense if none are given. Only words tagged as verbs are
+// drilled, since untagged words such as hier or livre end like infinitives.
+// Prompts are drawn at random without repeating a verb, tense and person.
+func (s *ConjugationService) Drill(groupID int64, tenses []string, count int) ([]DrillPrompt, error) {
+	parsed, err := parseTenses(tenses)
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		count = DefaultDrillSize
+	}
+	if count > MaxDrillSize {
+		return nil, &ValidationError{Message: fmt.Sprintf("count must be at most %d", MaxDrillSize)}
+	}
+
+	var source string
+	err = s.db.QueryRow("SELECT source_language FROM groups WHERE id = ?", groupID).Scan(&source)
+	if err == sql.ErrNoRows {
+		return nil, notFound("group", groupID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if source != conjugationLanguage {
+		return nil, &ValidationError{Message: fmt.Sprintf("group %d is not a French group", groupID)}
+	}
+
+	rows, err := s.db.Query(`
+		SELECT w.id, json(w.parts), COALESCE(json_extract(w.parts, '$.french'), '')
+		FROM words w
+		JOIN word_groups wg ON wg.word_id = w.id
+		WHERE wg.group_id = ? AND w.part_of_speech = 'verb'
+		ORDER BY w.id
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prompts []DrillPrompt
+	for rows.Next() {
+		var id int64
+		var parts []byte
+		var infinitive string
+		if err := rows.Scan(&id, &parts, &infinitive); err != nil {
+			return nil, err
+		}
+
+		// Words tagged as verbs that are not infinitives are left out
+		verb, err := conjugation.Lookup(infinitive)
+		if err != nil {
+			continue
+		}
+
+		for _, tense := range parsed {
+			for _, person := range conjugation.Persons {
+				prompts = append(prompts, DrillPrompt{
+					WordID:     id,
+					Infinitive: verb.Infinitive,
+					Parts:      json.RawMessage(parts),
+					Tense:      string(tense),
+					Person:     person.String(),
+					Pronoun:    person.Pronoun(),
+					Answer:     verb.Form(tense, person),
+				})
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(prompts) == 0 {
+		return nil, &ValidationError{Message: fmt.Sprintf("group %d has no verbs to conjugate", groupID)}
+	}
+
+	rand.Shuffle(len(prompts), func(i, j int) {
+		prompts[i], prompts[j] = prompts[j], prompts[i]
+	})
+	if len(prompts) > count {
+		prompts = prompts[:count]
+	}
+
+	return prompts, nil
+}
+
+// Review grades a drill answer and records it in one of the user's study
+// sessions, tagged with the tense and person practiced. Answers that are
+// only wrong in their accents count as correct but hard.
+func (s *ConjugationService) Review(userID, sessionID int64, answer DrillAnswer) (*DrillReviewResponse, error) {
+	tense, ok := conjugation.ParseTense(answer.Tense)
+	if !ok {
+		return nil, &ValidationError{Message: "tense must be one of " + tenseNames()}
+	}
+	person, ok := conjugation.ParsePerson(answer.Person)
+	if !ok {
+		return nil, &ValidationError{Message: "person must be one of je, tu, il, nous, vous, ils"}
+	}
+
+	verb, err := loadVerb(s.db, answer.WordID)
+	if err != nil {
+		return nil, err
+	}
+	if verb == nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("word %d does not exist", answer.WordID)}
+	}
+
+	expected := verb.Form(tense, person)
+	quality := grades["again"]
+	switch conjugation.Compare(person, expected, answer.Answer) {
+	case conjugation.MatchExact:
+		quality = grades["good"]
+	case conjugation.MatchIgnoringAccents:
+		quality = grades["hard"]
+	}
+
+	review, err := s.sessions.ReviewWord(userID, sessionID, answer.WordID, ReviewInput{
+		Quality:        quality,
+		Answer:         answer.Answer,
+		ResponseTimeMs: answer.ResponseTimeMs,
+		Direction:      DirectionProduction,
+		Tense:          string(tense),
+		Person:         person.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DrillReviewResponse{WordReviewItem: *review, Expected: expected}, nil
+}
+
+// loadVerb returns the verb of a French word, or nil if the word does not
+// exist. Words of other languages, words tagged as another
+// part of speech and French words that are not infinitives are rejected.
+func loadVerb(q queryer, id int64) (*conjugation.Verb, error) {
+	var source, infinitive, partOfSpeech string
+	err := q.QueryRow(`
+		SELECT
+			source_language,
+			COALESCE(json_extract(parts, '$.french'), ''),
+			COALESCE(part_of_speech, '')
+		FROM words
+		WHERE id = ?
+	`, id).Scan(&source, &infinitive, &partOfSpeech)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if source != conjugationLanguage {
+		return nil, &ValidationError{Message: fmt.Sprintf("word %d is not French", id)}
+	}
+	if partOfSpeech != "" && partOfSpeech != "verb" {
+		return nil, &ValidationError{Message: fmt.Sprintf("word %d is a %s, not a verb", id, partOfSpeech)}
+	}
+
+	verb, err := conjugation.Lookup(infinitive)
+	if err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("%q is %v", infinitive, err)}
+	}
+
+	return verb, nil
+}
+
+// parseTenses returns the named tenses, or every tense if none are named
+func parseTenses(names []string) ([]conjugation.Tense, error) {
+	if len(names) == 0 {
+		return conjugation.Tenses, nil
+	}
+
+	var tenses []conjugation.Tense
+	for _, name := range names {
+		tense, ok := conjugation.ParseTense(name)
+		if !ok {
+			return nil, &ValidationError{Message: fmt.Sprintf("tense %q must be one of %s", name, tenseNames())}
+		}
+		tenses = append(tenses, tense)
+	}
+	return tenses, nil
+}
+
+func tenseNames() string {
+	names := make([]string, len(conjugation.Tenses))
+	for i, tense := range conjugation.Tenses {
+		names[i] = string(tense)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// cursor marks the last item of a page of a list paginated by cursor. Such
+// lists are sorted newest first by creation time, then by id, and the
+// cursor holds both keys of the item so later inserts and deletes don't
+// shift the pages that follow.
+type cursor struct {
+	// At is the creation time as a Julian day number
+	At float64 `json:"at"`
+	ID int64   `json:"id"`
+}
+
+// encode returns the cursor as the opaque string handed to clients
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor handed out by encode. An empty string asks
+// for the first page and returns nil.
+func decodeCursor(s string) (*cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.ID <= 0 {
+		return nil, newFieldsError([]FieldError{{Field: "cursor", Message: "is not a cursor returned by this list"}})
+	}
+	return &c, nil
+}
+
+// condition returns the condition selecting the items after the cursor in
+// a list sorted by the created and id columns, with its arguments. Any item
+// matches a nil cursor.
+func (c *cursor) condition(created, id string) (string, []interface{}) {
+	if c == nil {
+		return "1", nil
+	}
+	return fmt.Sprintf("(julianday(%s), %s) < (?, ?)", created, id), []interface{}{c.At, c.ID}
+}
+
+// cursorOrder orders a list paginated by cursor, newest first
+func cursorOrder(created, id string) string {
+	return fmt.Sprintf("julianday(%s) DESC, %s DESC", created, id)
+}
+
+// nextCursor returns the cursor after the item with the given id of table,
+// the last of a page
+func nextCursor(q queryer, table string, id int64) (string, error) {
+	c := cursor{ID: id}
+	err := q.QueryRow("SELECT julianday(created_at) FROM "+table+" WHERE id = ?", id).Scan(&c.At)
+	if err != nil {
+		return "", err
+	}
+	return c.encode(), nil
+}
@@ -35,16 +35,17 @@ type QuickStats struct {
 	StudyStreakDays    int     `json:"study_streak_days"`
 }
 
-// GetLastStudySession returns the most recent study session
-func (s *DashboardService) GetLastStudySession() (*LastStudySession, error) {
+// GetLastStudySession returns the user's most recent study session
+func (s *DashboardService) GetLastStudySession(userID int64) (*LastStudySession, error) {
 	var session LastStudySession
 	err := s.db.QueryRow(`
-		SELECT s.id, s.group_id, s.study_activity_id, g.name
+		SELECT s.id, s.group_id, COALESCE(s.study_activity_id, 0), g.name
 		FROM study_sessions s
 		JOIN groups g ON s.group_id = g.id
+		WHERE s.user_id = ?
 		ORDER BY s.created_at DESC
 		LIMIT 1
-	`).Scan(&session.ID, &session.GroupID, &session.StudyActivityID, &session.GroupName)
+	`, userID).Scan(&session.ID, &session.GroupID, &session.StudyActivityID, &session.GroupName)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -56,23 +57,27 @@ func (s *DashboardService) GetLastStudySession() (*LastStudySession, error) {
 	return &session, nil
 }
 
-// GetStudyProgress returns the overall study progress
-func (s *DashboardService) GetStudyProgress() (*StudyProgress, error) {
+// GetStudyProgress returns the user's study progress in the words of a
+// language pair
+func (s *DashboardService) GetStudyProgress(userID int64, pair LanguagePair) (*StudyProgress, error) {
 	var progress StudyProgress
+	languages, languageArgs := pair.filter("w")
 
 	// Get total available words
 	err := s.db.QueryRow(`
-		SELECT COUNT(*) FROM words
-	`).Scan(&progress.TotalAvailableWords)
+		SELECT COUNT(*) FROM words w WHERE `+languages, languageArgs...).Scan(&progress.TotalAvailableWords)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get total words studied (unique words reviewed)
 	err = s.db.QueryRow(`
-		SELECT COUNT(DISTINCT word_id) 
-		FROM word_review_items
-	`).Scan(&progress.TotalWordsStudied)
+		SELECT COUNT(DISTINCT wri.word_id)
+		FROM word_review_items wri
+		JOIN study_sessions ss ON wri.study_session_id = ss.id
+		JOIN words w ON wri.word_id = w.id
+		WHERE ss.user_id = ? AND `+languages,
+		append([]interface{}{userID}, languageArgs...)...).Scan(&progress.TotalWordsStudied)
 	if err != nil {
 		return nil, err
 	}
@@ -80,33 +85,41 @@ func (s *DashboardService) GetStudyProgress() (*StudyProgress, error) {
 	return &progress, nil
 }
 
-// GetQuickStats returns quick statistics about the user's learning
-func (s *DashboardService) GetQuickStats() (*QuickStats, error) {
+// GetQuickStats returns quick statistics about the user's study sessions of
+// groups in a language pair
+func (s *DashboardService) GetQuickStats(userID int64, pair LanguagePair) (*QuickStats, error) {
 	var stats QuickStats
 
+	// sessions restricts the study_sessions aliased ss to the user's
+	// sessions of the pair
+	languages, languageArgs := pair.filter("g")
+	sessions := "ss.user_id = ? AND ss.group_id IN (SELECT g.id FROM groups g WHERE " + languages + ")"
+	sessionArgs := append([]interface{}{userID}, languageArgs...)
+
 	// Get success rate
 	err := s.db.QueryRow(`
 		SELECT COALESCE(
-			(SELECT CAST(SUM(CASE WHEN correct THEN 1 ELSE 0 END) AS FLOAT) / COUNT(*) * 100
-			FROM word_review_items), 0)
-	`).Scan(&stats.SuccessRate)
+			(SELECT CAST(SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END) AS FLOAT) / COUNT(*) * 100
+			FROM word_review_items wri
+			JOIN study_sessions ss ON wri.study_session_id = ss.id
+			WHERE `+sessions+`), 0)
+	`, sessionArgs...).Scan(&stats.SuccessRate)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get total study sessions
 	err = s.db.QueryRow(`
-		SELECT COUNT(*) FROM study_sessions
-	`).Scan(&stats.TotalStudySessions)
+		SELECT COUNT(*) FROM study_sessions ss WHERE `+sessions, sessionArgs...).Scan(&stats.TotalStudySessions)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get total active groups (groups with at least one study session)
 	err = s.db.QueryRow(`
-		SELECT COUNT(DISTINCT group_id) 
-		FROM study_sessions
-	`).Scan(&stats.TotalActiveGroups)
+		SELECT COUNT(DISTINCT ss.group_id)
+		FROM study_sessions ss
+		WHERE `+sessions, sessionArgs...).Scan(&stats.TotalActiveGroups)
 	if err != nil {
 		return nil, err
 	}
@@ -114,9 +127,10 @@ func (s *DashboardService) GetQuickStats() (*QuickStats, error) {
 	// Get study streak (consecutive days with study sessions)
 	err = s.db.QueryRow(`
 		WITH RECURSIVE dates AS (
-			SELECT date(created_at) as study_date
-			FROM study_sessions
-			GROUP BY date(created_at)
+			SELECT date(ss.created_at) as study_date
+			FROM study_sessions ss
+			WHERE `+sessions+`
+			GROUP BY date(ss.created_at)
 			ORDER BY study_date DESC
 			LIMIT 1
 		),
@@ -128,12 +142,12 @@ func (s *DashboardService) GetQuickStats() (*QuickStats, error) {
 			FROM streak
 			WHERE EXISTS (
 				SELECT 1
-				FROM study_sessions
-				WHERE date(created_at) = date(study_date, '-1 day')
+				FROM study_sessions ss
+				WHERE `+sessions+` AND date(ss.created_at) = date(study_date, '-1 day')
 			)
 		)
 		SELECT COUNT(*) FROM streak
-	`).Scan(&stats.StudyStreakDays)
+	`, append(sessionArgs, sessionArgs...)...).Scan(&stats.StudyStreakDays)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError reports input that the service refused to store. Fields
+// lists the individual problems when there is more than one field to blame.
+type ValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+// FieldError is a problem with one field of the input, named by its path
+// such as parts.french
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// newFieldsError returns a ValidationError listing every field error
+func newFieldsError(errs []FieldError) *ValidationError {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Field + " " + err.Message
+	}
+	return &ValidationError{Message: strings.Join(messages, "; "), Fields: errs}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// NotFoundError reports that the resource a request operates on does not
+// exist. Missing resources referenced from a request body are reported as a
+// ValidationError instead.
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// ConflictError reports a request that is not allowed in the current state
+// of the resource, such as reviewing a word in a finished study session
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// UnauthorizedError reports a request without valid credentials
+type UnauthorizedError struct {
+	Message string
+}
+
+func (e *UnauthorizedError) Error() string {
+	return e.Message
+}
+
+// ForbiddenError reports an authenticated user whose role does not allow
+// the request
+type ForbiddenError struct {
+	Message string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+func notFound(resource string, id int64) *NotFoundError {
+	return &NotFoundError{Message: fmt.Sprintf("%s %d not found", resource, id)}
+}
+
+// rowExists reports whether table has a row with the given id
+func rowExists(q queryer, table string, id int64) (bool, error) {
+	var exists bool
+	err := q.QueryRow("SELECT EXISTS(SELECT 1 FROM "+table+" WHERE id = ?)", id).Scan(&exists)
+	return exists, err
+}
+
+// requireExists returns a NotFoundError unless table has a row with id
+func requireExists(q queryer, table, resource string, id int64) error {
+	exists, err := rowExists(q, table, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return notFound(resource, id)
+	}
+	return nil
+}
+
+// requireReference returns a ValidationError unless table has a row with
+// the id given in the request field
+func requireReference(q queryer, table, field string, id int64) error {
+	exists, err := rowExists(q, table, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &ValidationError{Message: fmt.Sprintf("%s %d does not exist", field, id)}
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+type ExportService struct {
+	db     *sql.DB
+	groups *GroupService
+}
+
+func NewExportService() *ExportService {
+	return &ExportService{
+		db:     storage.GetDB(),
+		groups: NewGroupService(),
+	}
+}
+
+// ExportOptions select what is exported
+type ExportOptions struct {
+	Format string
+	// Pair is the language pair of CSV and TSV exports. Empty languages
+	// default to French to English.
+	Pair LanguagePair
+	// GroupID limits CSV and TSV exports to the words of a group, in its
+	// language pair. Seed and apkg exports require it.
+	GroupID int64
+	// UserID is the user whose schedule and reviews apkg exports hold
+	UserID int64
+}
+
+// exportWord is a stored word with what exports write of it
+type exportWord struct {
+	id         int64
+	pair       LanguagePair
+	parts      json.RawMessage
+	attributes models.WordAttributes
+}
+
+// Export writes vocabulary in a format. CSV and TSV exports hold the words
+// of a language pair or group, seed exports the words of a group, apkg
+// exports a group as an Anki deck and bundle exports every group, word and
+// study activity. Each format can be imported
+// back.
+func (s *ExportService) Export(options ExportOptions) ([]byte, error) {
+	if err := checkFormat(options.Format); err != nil {
+		return nil, err
+	}
+
+	var group *models.Group
+	if options.GroupID != 0 {
+		var err error
+		if group, err = s.groups.Get(options.GroupID); err != nil {
+			return nil, err
+		}
+		if group == nil {
+			return nil, notFound("group", options.GroupID)
+		}
+	}
+
+	switch options.Format {
+	case FormatCSV:
+		return s.exportDelimited(',', options.Pair, group)
+	case FormatTSV:
+		return s.exportDelimited('\t', options.Pair, group)
+	case FormatSeed:
+		if group == nil {
+			return nil, newFieldsError([]FieldError{{Field: "group_id", Message: "is required for seed exports"}})
+		}
+		return s.exportSeed(group)
+	case FormatApkg:
+		if group == nil {
+			return nil, newFieldsError([]FieldError{{Field: "group_id", Message: "is required for apkg exports"}})
+		}
+		return s.exportApkg(group, options.UserID)
+	}
+	return s.exportBundle()
+}
+
+func (s *ExportService) exportDelimited(comma rune, pair LanguagePair, group *models.Group) ([]byte, error) {
+	pair = pair.orDefault()
+	condition, args := "w.source_language = ? AND w.target_language = ?", []interface{}{pair.Source, pair.Target}
+	if group != nil {
+		pair = LanguagePair{group.SourceLanguage, group.TargetLanguage}
+		condition, args = "w.id IN (SELECT word_id FROM word_groups WHERE group_id = ?)", []interface{}{group.ID}
+	}
+
+	source, target, err := requireLanguagePair(s.db, pair)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	isField := make(map[string]bool)
+	for _, language := range []*models.Language{source, target} {
+		for _, field := range language.Fields {
+			fields = append(fields, field.Name)
+			isField[field.Name] = true
+		}
+	}
+
+	words, err := s.words(condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groupNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = comma
+
+	header := append([]string{}, fields...)
+	for _, name := range attributeColumns {
+		header = append(header, attributeColumn(name, isField))
+	}
+	header = append(header, GroupsColumn)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, word := range words {
+		var parts map[string]json.RawMessage
+		if err := json.Unmarshal(word.parts, &parts); err != nil {
+			return nil, err
+		}
+
+		record := make([]string, 0, len(header))
+		for _, field := range fields {
+			record = append(record, partText(parts[field]))
+		}
+		record = append(record, attributeValues(word.attributes)...)
+		record = append(record, strings.Join(groups[word.id], groupSeparator))
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// partText returns a string part as it is and other values as JSON
+func partText(value json.RawMessage) string {
+	var text string
+	if len(value) == 0 || json.Unmarshal(value, &text) == nil {
+		return text
+	}
+	return string(value)
+}
+
+func (s *ExportService) exportSeed(group *models.Group) ([]byte, error) {
+	words, err := s.words("w.id IN (SELECT word_id FROM word_groups WHERE group_id = ?)", group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	file := SeedFile{
+		GroupName:      group.Name,
+		SourceLanguage: group.SourceLanguage,
+		TargetLanguage: group.TargetLanguage,
+		Words:          make([]map[string]json.RawMessage, 0, len(words)),
+	}
+	for _, word := range words {
+		var entry map[string]json.RawMessage
+		if err := json.Unmarshal(word.parts, &entry); err != nil {
+			return nil, err
+		}
+		if word.attributes != (models.WordAttributes{}) {
+			if entry["attributes"], err = json.Marshal(word.attributes); err != nil {
+				return nil, err
+			}
+		}
+		file.Words = append(file.Words, entry)
+	}
+
+	return json.MarshalIndent(file, "", "  ")
+}
+
+func (s *ExportService) exportBundle() ([]byte, error) {
+	bundle := Bundle{Version: BundleVersion, Words: []BundleWord{}}
+
+	var err error
+	if bundle.Groups, err = s.bundleGroups(); err != nil {
+		return nil, err
+	}
+
+	words, err := s.words("1")
+	if err != nil {
+		return nil, err
+	}
+	for _, word := range words {
+		entry := BundleWord{
+			ID:             word.id,
+			SourceLanguage: word.pair.Source,
+			TargetLanguage: word.pair.Target,
+			Parts:          word.parts,
+		}
+		if word.attributes != (models.WordAttributes{}) {
+			if entry.Attributes, err = json.Marshal(word.attributes); err != nil {
+				return nil, err
+			}
+		}
+		bundle.Words = append(bundle.Words, entry)
+	}
+
+	if bundle.WordGroups, err = s.bundleLinks(); err != nil {
+		return nil, err
+	}
+	if bundle.StudyActivities, err = s.bundleActivities(); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(bundle, "", "  ")
+}
+
+func (s *ExportService) bundleGroups() ([]BundleGroup, error) {
+	rows, err := s.db.Query("SELECT id, name, source_language, target_language FROM groups ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []BundleGroup{}
+	for rows.Next() {
+		var group BundleGroup
+		if err := rows.Scan(&group.ID, &group.Name, &group.SourceLanguage, &group.TargetLanguage); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+func (s *ExportService) bundleLinks() ([]BundleLink, error) {
+	rows, err := s.db.Query("SELECT word_id, group_id FROM word_groups ORDER BY group_id, word_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []BundleLink{}
+	for rows.Next() {
+		var link BundleLink
+		if err := rows.Scan(&link.WordID, &link.GroupID); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+func (s *ExportService) bundleActivities() ([]BundleActivity, error) {
+	rows, err := s.db.Query(`
+		SELECT name, url, COALESCE(thumbnail_url, ''), COALESCE(description, ''), COALESCE(scheduler, '')
+		FROM study_activities
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := []BundleActivity{}
+	for rows.Next() {
+		var activity BundleActivity
+		err := rows.Scan(&activity.Name, &activity.URL, &activity.ThumbnailURL, &activity.Description, &activity.Scheduler)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, activity)
+	}
+	return activities, rows.Err()
+}
+
+// words returns the words aliased w matching a condition, by id
+func (s *ExportService) words(condition string, args ...interface{}) ([]exportWord, error) {
+	rows, err := s.db.Query(`
+		SELECT w.id, w.source_language, w.target_language, w.parts, `+wordAttributeColumns+`
+		FROM words w
+		WHERE `+condition+`
+		ORDER BY w.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var words []exportWord
+	for rows.Next() {
+		var word exportWord
+		var parts []byte
+		targets := []interface{}{&word.id, &word.pair.Source, &word.pair.Target, &parts}
+		if err := rows.Scan(append(targets, attributeTargets(&word.attributes)...)...); err != nil {
+			return nil, err
+		}
+		word.parts = json.RawMessage(parts)
+		words = append(words, word)
+	}
+	return words, rows.Err()
+}
+
+// groupNames returns the names of the groups of each word, by word id
+func (s *ExportService) groupNames() (map[int64][]string, error) {
+	rows, err := s.db.Query(`
+		SELECT wg.word_id, g.name
+		FROM word_groups wg
+		JOIN groups g ON g.id = wg.group_id
+		ORDER BY g.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[int64][]string)
+	for rows.Next() {
+		var wordID int64
+		var name string
+		if err := rows.Scan(&wordID, &name); err != nil {
+			return nil, err
+		}
+		names[wordID] = append(names[wordID], name)
+	}
+	return names, rows.Err()
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"database/sql"
+	"strings"
+	"time"
 
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
@@ -17,21 +19,42 @@ func NewGroupService() *GroupService {
 	}
 }
 
-// List returns a paginated list of groups
-func (s *GroupService) List(page, perPage int) ([]models.Group, int, error) {
+const groupColumns = "id, name, source_language, target_language, words_count"
+
+func scanGroup(row rowScanner) (*models.Group, error) {
+	var group models.Group
+	err := row.Scan(&group.ID, &group.Name, &group.SourceLanguage, &group.TargetLanguage, &group.WordsCount)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// List returns a paginated list of the groups of a language pair
+func (s *GroupService) List(pair LanguagePair, options ListOptions, page, perPage int) ([]models.Group, int, error) {
 	offset := (page - 1) * perPage
+	languages, args := pair.filter("groups")
+
+	list, err := options.query(s.db, groupListSpec)
+	if err != nil {
+		return nil, 0, err
+	}
+	languages += " AND " + list.condition
+	args = append(args, list.args...)
 
 	var total int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM groups").Scan(&total)
+	err = s.db.QueryRow("SELECT COUNT(*) FROM groups WHERE "+languages, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	rows, err := s.db.Query(`
-		SELECT id, name, words_count 
+		SELECT `+groupColumns+`
 		FROM groups 
+		WHERE `+languages+`
+		ORDER BY `+list.orderBy+`
 		LIMIT ? OFFSET ?
-	`, perPage, offset)
+	`, append(args, perPage, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -39,12 +62,11 @@ func (s *GroupService) List(page, perPage int) ([]models.Group, int, error) {
 
 	var groups []models.Group
 	for rows.Next() {
-		var group models.Group
-		err := rows.Scan(&group.ID, &group.Name, &group.WordsCount)
+		group, err := scanGroup(rows)
 		if err != nil {
 			return nil, 0, err
 		}
-		groups = append(groups, group)
+		groups = append(groups, *group)
 	}
 
 	return groups, total, nil
@@ -52,12 +74,7 @@ func (s *GroupService) List(page, perPage int) ([]models.Group, int, error) {
 
 // Get returns a single group by ID
 func (s *GroupService) Get(id int64) (*models.Group, error) {
-	var group models.Group
-	err := s.db.QueryRow(`
-		SELECT id, name, words_count 
-		FROM groups 
-		WHERE id = ?
-	`, id).Scan(&group.ID, &group.Name, &group.WordsCount)
+	group, err := scanGroup(s.db.QueryRow("SELECT "+groupColumns+" FROM groups WHERE id = ?", id))
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -66,15 +83,20 @@ func (s *GroupService) Get(id int64) (*models.Group, error) {
 		return nil, err
 	}
 
-	return &group, nil
+	return group, nil
 }
 
 // ListWords returns words in a group
-func (s *GroupService) ListWords(groupID int64, page, perPage int) ([]models.Word, int, error) {
+func (s *GroupService) ListWords(groupID int64, options ListOptions, page, perPage int) ([]models.Word, int, error) {
 	offset := (page - 1) * perPage
 
+	list, err := options.query(s.db, memberWordListSpec)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	var total int
-	err := s.db.QueryRow(`
+	err = s.db.QueryRow(`
 		SELECT COUNT(*) 
 		FROM word_groups 
 		WHERE group_id = ?
@@ -88,6 +110,7 @@ func (s *GroupService) ListWords(groupID int64, page, perPage int) ([]models.Wor
 		FROM words w
 		JOIN word_groups wg ON w.id = wg.word_id
 		WHERE wg.group_id = ?
+		ORDER BY `+list.orderBy+`
 		LIMIT ? OFFSET ?
 	`, groupID, perPage, offset)
 	if err != nil {
@@ -108,27 +131,44 @@ func (s *GroupService) ListWords(groupID int64, page, perPage int) ([]models.Wor
 	return words, total, nil
 }
 
-// ListStudySessions returns study sessions for a group
-func (s *GroupService) ListStudySessions(groupID int64, page, perPage int) ([]models.StudySession, int, error) {
+// ListDueWords returns the group's words whose review is due now, most
+// overdue first. With includeNew set, words that were never reviewed are
+// listed after them.
+func (s *GroupService) ListDueWords(userID, groupID int64, includeNew bool, page, perPage int) ([]DueWordResponse, int, error) {
+	return listDueWords(s.db, userID, groupID, LanguagePair{}, includeNew, time.Now(), page, perPage)
+}
+
+// ListStudySessions returns the user's study sessions for a group
+func (s *GroupService) ListStudySessions(userID, groupID int64, pair LanguagePair, options ListOptions, page, perPage int) ([]models.StudySession, int, error) {
 	offset := (page - 1) * perPage
 
+	list, err := options.query(s.db, groupSessionListSpec)
+	if err != nil {
+		return nil, 0, err
+	}
+	languages, languageArgs := pair.filter("g")
+	args := append(append([]interface{}{groupID, userID}, languageArgs...), list.args...)
+
 	var total int
-	err := s.db.QueryRow(`
-		SELECT COUNT(*) 
-		FROM study_sessions 
-		WHERE group_id = ?
-	`, groupID).Scan(&total)
+	err = s.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM study_sessions
+		WHERE group_id = ? AND user_id = ?
+			AND group_id IN (SELECT g.id FROM groups g WHERE `+languages+`)
+			AND `+list.condition, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	rows, err := s.db.Query(`
-		SELECT id, group_id, study_activity_id, created_at
+		SELECT id, group_id, COALESCE(study_activity_id, 0), status, created_at
 		FROM study_sessions
-		WHERE group_id = ?
-		ORDER BY created_at DESC
+		WHERE group_id = ? AND user_id = ?
+			AND group_id IN (SELECT g.id FROM groups g WHERE `+languages+`)
+			AND `+list.condition+`
+		ORDER BY `+list.orderBy+`
 		LIMIT ? OFFSET ?
-	`, groupID, perPage, offset)
+	`, append(args, perPage, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -137,7 +177,7 @@ func (s *GroupService) ListStudySessions(groupID int64, page, perPage int) ([]mo
 	var sessions []models.StudySession
 	for rows.Next() {
 		var session models.StudySession
-		err := rows.Scan(&session.ID, &session.GroupID, &session.StudyActivityID, &session.CreatedAt)
+		err := rows.Scan(&session.ID, &session.GroupID, &session.StudyActivityID, &session.Status, &session.CreatedAt)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -146,3 +186,210 @@ func (s *GroupService) ListStudySessions(groupID int64, page, perPage int) ([]mo
 
 	return sessions, total, nil
 }
+
+// Create adds a new, empty group for a language pair. Empty languages
+// default to French to English.
+func (s *GroupService) Create(name string, pair LanguagePair) (*models.Group, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ValidationError{Message: "name is required"}
+	}
+
+	pair = pair.orDefault()
+	if _, _, err := requireLanguagePair(s.db, pair); err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO groups (name, source_language, target_language) VALUES (?, ?, ?)
+	`, name, pair.Source, pair.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// Rename changes a group's name
+func (s *GroupService) Rename(id int64, name string) (*models.Group, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ValidationError{Message: "name is required"}
+	}
+
+	result, err := s.db.Exec("UPDATE groups SET name = ? WHERE id = ?", name, id)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, notFound("group", id)
+	}
+
+	return s.Get(id)
+}
+
+// Delete removes a group, its word links, class assignments and study
+// sessions with their review items. The words themselves are kept, and
+// their schedules are replayed without the reviews removed.
+func (s *GroupService) Delete(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := requireExists(tx, "groups", "group", id); err != nil {
+		return err
+	}
+
+	reviewed, err := groupReviewedWords(tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM word_review_items
+		WHERE study_session_id IN (SELECT id FROM study_sessions WHERE group_id = ?)
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM study_sessions WHERE group_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM class_assignments WHERE group_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM word_groups WHERE group_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM groups WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	for _, user := range reviewed {
+		if err := rescheduleWords(tx, user.userID, user.wordIDs); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// userWords are the words a user reviewed
+type userWords struct {
+	userID  int64
+	wordIDs []int64
+}
+
+// groupReviewedWords returns the words each user reviewed in the study
+// sessions of a group
+func groupReviewedWords(tx *sql.Tx, groupID int64) ([]userWords, error) {
+	rows, err := tx.Query(`
+		SELECT DISTINCT ss.user_id, wri.word_id
+		FROM word_review_items wri
+		JOIN study_sessions ss ON ss.id = wri.study_session_id
+		WHERE ss.group_id = ? AND ss.user_id IS NOT NULL
+		ORDER BY ss.user_id, wri.word_id
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviewed []userWords
+	for rows.Next() {
+		var userID, wordID int64
+		if err := rows.Scan(&userID, &wordID); err != nil {
+			return nil, err
+		}
+		if len(reviewed) == 0 || reviewed[len(reviewed)-1].userID != userID {
+			reviewed = append(reviewed, userWords{userID: userID})
+		}
+		last := &reviewed[len(reviewed)-1]
+		last.wordIDs = append(last.wordIDs, wordID)
+	}
+	return reviewed, rows.Err()
+}
+
+// AddWords links existing words to a group, ignoring words already in it.
+// The words must be for the group's language pair.
+func (s *GroupService) AddWords(groupID int64, wordIDs []int64) (*models.Group, error) {
+	return s.changeWords(groupID, wordIDs, `
+		INSERT OR IGNORE INTO word_groups (word_id, group_id)
+		VALUES (?, ?)
+	`)
+}
+
+// RemoveWords unlinks words from a group
+func (s *GroupService) RemoveWords(groupID int64, wordIDs []int64) (*models.Group, error) {
+	return s.changeWords(groupID, wordIDs, `
+		DELETE FROM word_groups
+		WHERE word_id = ? AND group_id = ?
+	`)
+}
+
+// RepairWordsCounts recomputes words_count for every group whose counter no
+// longer matches word_groups and returns how many groups were fixed
+func (s *GroupService) RepairWordsCounts() (int64, error) {
+	result, err := s.db.Exec(`
+		UPDATE groups
+		SET words_count = (SELECT COUNT(*) FROM word_groups WHERE word_groups.group_id = groups.id)
+		WHERE words_count IS NOT (SELECT COUNT(*) FROM word_groups WHERE word_groups.group_id = groups.id)
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (s *GroupService) changeWords(groupID int64, wordIDs []int64, query string) (*models.Group, error) {
+	if len(wordIDs) == 0 {
+		return nil, &ValidationError{Message: "word_ids must not be empty"}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := requireExists(tx, "groups", "group", groupID); err != nil {
+		return nil, err
+	}
+
+	for _, wordID := range wordIDs {
+		if err := requireReference(tx, "words", "word", wordID); err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.Exec(query, wordID, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := requireMatchingLanguages(tx, "wg.group_id", groupID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.Get(groupID)
+}
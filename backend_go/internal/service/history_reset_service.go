@@ -0,0 +1,472 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+// HistoryResetRetention is how long a history reset can be restored before
+// its archive is purged
+const HistoryResetRetention = 7 * 24 * time.Hour
+
+// The statuses of a history reset
+const (
+	ResetArchived = "archived"
+	ResetRestored = "restored"
+	ResetExpired  = "expired"
+)
+
+// HistoryResetScope selects the study sessions a reset covers. Zero fields
+// match any session. From and To are dates or RFC 3339 times bounding when
+// sessions were started; a date in To includes that whole day.
+type HistoryResetScope struct {
+	GroupID         int64  `json:"group_id,omitempty"`
+	StudyActivityID int64  `json:"study_activity_id,omitempty"`
+	From            string `json:"from,omitempty"`
+	To              string `json:"to,omitempty"`
+}
+
+// historyResetColumns are the columns of history_resets read into a
+// models.HistoryReset by scanHistoryReset
+const historyResetColumns = `
+	id, user_id, actor_id, group_id, study_activity_id, from_time, to_time,
+	sessions_count, reviews_count, created_at, expires_at, restored_at
+`
+
+type HistoryResetService struct {
+	db *sql.DB
+}
+
+func NewHistoryResetService() *HistoryResetService {
+	return &HistoryResetService{
+		db: storage.GetDB(),
+	}
+}
+
+// Reset archives the study sessions of userID in scope and their reviews on
+// behalf of actorID, and reschedules the words they reviewed. The reset can
+// be restored until it expires after HistoryResetRetention.
+func (s *HistoryResetService) Reset(actorID, userID int64, scope HistoryResetScope) (*models.HistoryReset, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reset, err := resetHistory(tx, actorID, userID, scope)
+	if err != nil {
+		return nil, err
+	}
+	return reset, tx.Commit()
+}
+
+// resetHistory is Reset inside tx
+func resetHistory(tx *sql.Tx, actorID, userID int64, scope HistoryResetScope) (*models.HistoryReset, error) {
+	if err := requireExists(tx, "users", "user", userID); err != nil {
+		return nil, err
+	}
+
+	var errs []FieldError
+	var from, to sql.NullTime
+	if scope.GroupID != 0 {
+		if exists, err := rowExists(tx, "groups", scope.GroupID); err != nil {
+			return nil, err
+		} else if !exists {
+			errs = append(errs, FieldError{Field: "group_id", Message: fmt.Sprintf("group %d does not exist", scope.GroupID)})
+		}
+	}
+	if scope.StudyActivityID != 0 {
+		if exists, err := rowExists(tx, "study_activities", scope.StudyActivityID); err != nil {
+			return nil, err
+		} else if !exists {
+			errs = append(errs, FieldError{Field: "study_activity_id", Message: fmt.Sprintf("study activity %d does not exist", scope.StudyActivityID)})
+		}
+	}
+	bounds := []struct {
+		field string
+		value string
+		end   bool
+		time  *sql.NullTime
+	}{
+		{"from", scope.From, false, &from},
+		{"to", scope.To, true, &to},
+	}
+	for _, bound := range bounds {
+		if bound.value == "" {
+			continue
+		}
+		t, ok := parseListTime(bound.value, bound.end)
+		if !ok {
+			errs = append(errs, FieldError{Field: bound.field, Message: "must be a date such as 2025-01-31 or an RFC 3339 time"})
+			continue
+		}
+		*bound.time = sql.NullTime{Time: t.UTC(), Valid: true}
+	}
+	if from.Valid && to.Valid && !from.Time.Before(to.Time) {
+		errs = append(errs, FieldError{Field: "to", Message: "must be after from"})
+	}
+	if len(errs) > 0 {
+		return nil, newFieldsError(errs)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	result, err := tx.Exec(`
+		INSERT INTO history_resets (user_id, actor_id, group_id, study_activity_id, from_time, to_time, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, userID, actorID, nullID(scope.GroupID), nullID(scope.StudyActivityID), from, to, now, now.Add(HistoryResetRetention))
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions keep their ids in the archive so restoring puts them back
+	// as they were
+	timeArg := func(t sql.NullTime) interface{} {
+		if !t.Valid {
+			return nil
+		}
+		return t.Time.Format("2006-01-02 15:04:05")
+	}
+	_, err = tx.Exec(`
+		INSERT INTO archived_study_sessions
+			(id, reset_id, user_id, group_id, study_activity_id, created_at, status, active_seconds, resumed_at, last_activity_at, ended_at)
+		SELECT id, ?, user_id, group_id, study_activity_id, created_at, status, active_seconds, resumed_at, last_activity_at, ended_at
+		FROM study_sessions
+		WHERE user_id = ?
+			AND (? = 0 OR group_id = ?)
+			AND (? = 0 OR study_activity_id = ?)
+			AND (? IS NULL OR julianday(created_at) >= julianday(?))
+			AND (? IS NULL OR julianday(created_at) < julianday(?))
+	`, id, userID, scope.GroupID, scope.GroupID, scope.StudyActivityID, scope.StudyActivityID,
+		timeArg(from), timeArg(from), timeArg(to), timeArg(to))
+	if err != nil {
+		return nil, err
+	}
+
+	wordIDs, err := archivedWordIDs(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, reviews, err := moveHistory(tx, id, `
+		INSERT INTO archived_word_review_items
+			(id, word_id, study_session_id, correct, created_at, quality, answer, response_time_ms, direction, client_id, tense, person)
+		SELECT id, word_id, study_session_id, correct, created_at, quality, answer, response_time_ms, direction, client_id, tense, person
+		FROM word_review_items
+		WHERE study_session_id IN (SELECT id FROM archived_study_sessions WHERE reset_id = ?)
+	`, `
+		DELETE FROM word_review_items
+		WHERE study_session_id IN (SELECT id FROM archived_study_sessions WHERE reset_id = ?)
+	`, `
+		DELETE FROM study_sessions
+		WHERE id IN (SELECT id FROM archived_study_sessions WHERE reset_id = ?)
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE history_resets SET sessions_count = ?, reviews_count = ? WHERE id = ?
+	`, sessions, reviews, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rescheduleWords(tx, userID, wordIDs); err != nil {
+		return nil, err
+	}
+	return getHistoryReset(tx, id)
+}
+
+// moveHistory runs the statements moving the reviews and sessions of a
+// reset between the live and archive tables, each given the reset's id, and
+// returns how many sessions and reviews were moved
+func moveHistory(tx *sql.Tx, resetID int64, copyReviews, deleteReviews, deleteSessions string) (int64, int64, error) {
+	result, err := tx.Exec(copyReviews, resetID)
+	if err != nil {
+		return 0, 0, err
+	}
+	reviews, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := tx.Exec(deleteReviews, resetID); err != nil {
+		return 0, 0, err
+	}
+	result, err = tx.Exec(deleteSessions, resetID)
+	if err != nil {
+		return 0, 0, err
+	}
+	sessions, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+	return sessions, reviews, nil
+}
+
+// archivedWordIDs returns the words reviewed in the sessions a reset
+// covers, whether they are archived or live
+func archivedWordIDs(tx *sql.Tx, resetID int64) ([]int64, error) {
+	rows, err := tx.Query(`
+		SELECT word_id FROM word_review_items
+		WHERE study_session_id IN (SELECT id FROM archived_study_sessions WHERE reset_id = ?)
+		UNION
+		SELECT word_id FROM archived_word_review_items
+		WHERE study_session_id IN (SELECT id FROM archived_study_sessions WHERE reset_id = ?)
+		ORDER BY word_id
+	`, resetID, resetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var wordIDs []int64
+	for rows.Next() {
+		var wordID int64
+		if err := rows.Scan(&wordID); err != nil {
+			return nil, err
+		}
+		wordIDs = append(wordIDs, wordID)
+	}
+	return wordIDs, rows.Err()
+}
+
+// rescheduleWords replays the user's schedules of words after their review
+// history changed, with the scheduler of each word's schedule or the global
+// default for words that have none
+func rescheduleWords(tx *sql.Tx, userID int64, wordIDs []int64) error {
+	fallback, err := defaultScheduler(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, wordID := range wordIDs {
+		state, err := loadReviewState(tx, userID, wordID)
+		if err != nil {
+			return err
+		}
+		scheduler := fallback
+		if state != nil {
+			if named, ok := SchedulerByName(state.Scheduler); ok {
+				scheduler = named
+			}
+		}
+		if _, err := replayReviewState(tx, scheduler, userID, wordID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns a page of the resets of userID's history and the resets
+// they made of other users' histories, newest first
+func (s *HistoryResetService) List(userID int64, page, perPage int) ([]models.HistoryReset, int, error) {
+	offset := (page - 1) * perPage
+
+	var total int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM history_resets WHERE user_id = ? OR actor_id = ?
+	`, userID, userID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT `+historyResetColumns+`
+		FROM history_resets
+		WHERE user_id = ? OR actor_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, userID, userID, perPage, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	resets := []models.HistoryReset{}
+	for rows.Next() {
+		reset, err := scanHistoryReset(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		resets = append(resets, *reset)
+	}
+	return resets, total, rows.Err()
+}
+
+// Restore moves the sessions and reviews archived by a reset back into
+// userID's history and reschedules the words they reviewed. Only the user
+// whose history was reset and the user who reset it can restore it, once
+// and before it expires.
+func (s *HistoryResetService) Restore(userID, id int64) (*models.HistoryReset, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reset, err := getHistoryReset(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reset.UserID != userID && reset.ActorID != userID {
+		return nil, notFound("history reset", id)
+	}
+	switch reset.Status {
+	case ResetRestored:
+		return nil, &ConflictError{Message: "history reset is already restored"}
+	case ResetExpired:
+		return nil, &ConflictError{Message: "history reset has expired"}
+	}
+
+	wordIDs, err := archivedWordIDs(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO study_sessions
+			(id, user_id, group_id, study_activity_id, created_at, status, active_seconds, resumed_at, last_activity_at, ended_at)
+		SELECT id, user_id, group_id, study_activity_id, created_at, status, active_seconds, resumed_at, last_activity_at, ended_at
+		FROM archived_study_sessions
+		WHERE reset_id = ?
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, err = moveHistory(tx, id, `
+		INSERT INTO word_review_items
+			(id, word_id, study_session_id, correct, created_at, quality, answer, response_time_ms, direction, client_id, tense, person)
+		SELECT id, word_id, study_session_id, correct, created_at, quality, answer, response_time_ms, direction, client_id, tense, person
+		FROM archived_word_review_items
+		WHERE study_session_id IN (SELECT id FROM archived_study_sessions WHERE reset_id = ?)
+	`, `
+		DELETE FROM archived_word_review_items
+		WHERE study_session_id IN (SELECT id FROM archived_study_sessions WHERE reset_id = ?)
+	`, `
+		DELETE FROM archived_study_sessions WHERE reset_id = ?
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if _, err := tx.Exec("UPDATE history_resets SET restored_at = ? WHERE id = ?", now, id); err != nil {
+		return nil, err
+	}
+
+	if err := rescheduleWords(tx, reset.UserID, wordIDs); err != nil {
+		return nil, err
+	}
+
+	reset, err = getHistoryReset(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	return reset, tx.Commit()
+}
+
+// PurgeExpired deletes the resets past their expiry, with the sessions and
+// reviews they archived, and returns how many were deleted
+func (s *HistoryResetService) PurgeExpired() (int, error) {
+	result, err := s.db.Exec(`
+		DELETE FROM history_resets WHERE julianday(expires_at) <= julianday(?)
+	`, time.Now().UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
+
+// RunPurger purges expired history resets every interval until stop is
+// closed
+func (s *HistoryResetService) RunPurger(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeExpired()
+			if err != nil {
+				log.Printf("Failed to purge expired history resets: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Purged %d expired history resets", purged)
+			}
+		}
+	}
+}
+
+func getHistoryReset(q queryer, id int64) (*models.HistoryReset, error) {
+	reset, err := scanHistoryReset(q.QueryRow("SELECT "+historyResetColumns+" FROM history_resets WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, notFound("history reset", id)
+	}
+	return reset, err
+}
+
+func scanHistoryReset(row rowScanner) (*models.HistoryReset, error) {
+	var reset models.HistoryReset
+	var groupID, activityID sql.NullInt64
+	var from, to, restoredAt sql.NullTime
+	err := row.Scan(
+		&reset.ID,
+		&reset.UserID,
+		&reset.ActorID,
+		&groupID,
+		&activityID,
+		&from,
+		&to,
+		&reset.SessionsCount,
+		&reset.ReviewsCount,
+		&reset.CreatedAt,
+		&reset.ExpiresAt,
+		&restoredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupID.Valid {
+		reset.GroupID = &groupID.Int64
+	}
+	if activityID.Valid {
+		reset.StudyActivityID = &activityID.Int64
+	}
+	if from.Valid {
+		reset.From = &from.Time
+	}
+	if to.Valid {
+		reset.To = &to.Time
+	}
+	switch {
+	case restoredAt.Valid:
+		reset.RestoredAt = &restoredAt.Time
+		reset.Status = ResetRestored
+	case !time.Now().Before(reset.ExpiresAt):
+		reset.Status = ResetExpired
+	default:
+		reset.Status = ResetArchived
+	}
+	return &reset, nil
+}
+
+// nullID stores a zero id as NULL
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
@@ -0,0 +1,665 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+type ImportService struct {
+	db *sql.DB
+}
+
+func NewImportService() *ImportService {
+	return &ImportService{
+		db: storage.GetDB(),
+	}
+}
+
+// ImportOptions describe the file imported
+type ImportOptions struct {
+	Format string
+	// DryRun checks the file and reports what would be imported without
+	// storing anything
+	DryRun bool
+	// Pair is the language pair of CSV, TSV and apkg words. Empty languages
+	// default to French to English.
+	Pair LanguagePair
+	// Group names a group every CSV and TSV word is added to. It is created
+	// if the pair has no group of that name.
+	Group string
+	// Columns name what each CSV and TSV column holds, in place of the
+	// header row. Empty names and "-" skip a column.
+	Columns []string
+	// NoHeader is set when the first CSV or TSV row is a word. Columns are
+	// then required.
+	NoHeader bool
+	// Fields map the fields of Anki notes to word parts and attributes, by
+	// field name. See readApkg for the fields mapped without it.
+	Fields map[string]string
+	// Reviews imports the review history of Anki cards as reviews by UserID
+	Reviews bool
+	UserID  int64
+}
+
+// The statuses of imported words
+const (
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportFailed    = "error"
+	// ImportUnmatched is a word of a review import that matches no
+	// existing word
+	ImportUnmatched = "unmatched"
+)
+
+// ImportRow reports on one word of an imported file. Row is the line of a
+// CSV or TSV word and the position of a seed or bundle word, from 1.
+// WordID is the word created, or the existing word a duplicate matched.
+type ImportRow struct {
+	Row    int          `json:"row"`
+	Status string       `json:"status"`
+	WordID int64        `json:"word_id,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+type ImportSummary struct {
+	Rows              int `json:"rows"`
+	Created           int `json:"created"`
+	Duplicates        int `json:"duplicates"`
+	Errors            int `json:"errors"`
+	Unmatched         int `json:"unmatched"`
+	GroupsCreated     int `json:"groups_created"`
+	ActivitiesCreated int `json:"activities_created"`
+	ReviewsCreated    int `json:"reviews_created"`
+}
+
+// ImportReport is the outcome of an import, or of what an import would do
+// for a dry run
+type ImportReport struct {
+	Format  string        `json:"format"`
+	DryRun  bool          `json:"dry_run"`
+	Summary ImportSummary `json:"summary"`
+	Rows    []ImportRow   `json:"rows"`
+}
+
+// groupKey identifies a group by its name and language pair, the way
+// imported words name their groups
+type groupKey struct {
+	name string
+	pair LanguagePair
+}
+
+// importWord is a word read from a file, not yet checked
+type importWord struct {
+	row        int
+	pair       LanguagePair
+	parts      json.RawMessage
+	attributes json.RawMessage
+	groups     []groupKey
+	// errs are the problems found reading the word
+	errs []FieldError
+}
+
+// importBatch is everything read from a file
+type importBatch struct {
+	// groups are created even if no word is added to them
+	groups     []groupKey
+	words      []importWord
+	activities []BundleActivity
+	reviews    []importReview
+}
+
+// Import stores the words of a file with their groups, and the study
+// activities of a bundle or the review history of an Anki package. Words
+// that fail validation are reported and skipped; words with the same
+// language pair and parts as an existing word are reported as duplicates
+// and only added to their groups. Groups are reused by name and language
+// pair, and activities by name. Problems with the file as a whole, such as
+// an unknown column, fail the import with a ValidationError.
+func (s *ImportService) Import(data []byte, options ImportOptions) (*ImportReport, error) {
+	return s.importFile(data, options, false)
+}
+
+// ImportReviews imports the review history of an Anki package as reviews
+// by options.UserID, leaving vocabulary as it is. Notes are matched to
+// existing words like duplicates are, and reviews are studied in the
+// existing group named like their deck. Notes matching no word are
+// reported as unmatched, and their reviews and the reviews of decks without
+// a group are skipped.
+func (s *ImportService) ImportReviews(data []byte, options ImportOptions) (*ImportReport, error) {
+	if options.Format != FormatApkg {
+		return nil, newFieldsError([]FieldError{{Field: "format", Message: "must be " + FormatApkg}})
+	}
+	options.Reviews = true
+	return s.importFile(data, options, true)
+}
+
+// importFile imports a file, only matching its words and groups to existing
+// ones when matchOnly is set
+func (s *ImportService) importFile(data []byte, options ImportOptions, matchOnly bool) (*ImportReport, error) {
+	if err := checkFormat(options.Format); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var batch *importBatch
+	switch options.Format {
+	case FormatCSV:
+		batch, err = readDelimited(tx, data, ',', options)
+	case FormatTSV:
+		batch, err = readDelimited(tx, data, '\t', options)
+	case FormatSeed:
+		batch, err = readSeed(data)
+	case FormatBundle:
+		batch, err = readBundle(data)
+	case FormatApkg:
+		batch, err = readApkg(tx, data, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &importer{
+		tx:        tx,
+		report:    &ImportReport{Format: options.Format, DryRun: options.DryRun, Rows: []ImportRow{}},
+		groups:    make(map[groupKey]int64),
+		words:     make(map[string]int64),
+		created:   make(map[int64]bool),
+		matchOnly: matchOnly,
+	}
+	if err := imp.loadWords(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range batch.groups {
+		if _, err := imp.group(key); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := &imp.report.Summary
+	for _, word := range batch.words {
+		row, err := imp.importWord(word)
+		if err != nil {
+			return nil, err
+		}
+		imp.wordIDs = append(imp.wordIDs, row.WordID)
+
+		// The ids of words created by a dry run are not kept
+		if options.DryRun && imp.created[row.WordID] {
+			row.WordID = 0
+		}
+
+		summary.Rows++
+		switch row.Status {
+		case ImportCreated:
+			summary.Created++
+		case ImportDuplicate:
+			summary.Duplicates++
+		case ImportUnmatched:
+			summary.Unmatched++
+		default:
+			summary.Errors++
+		}
+		imp.report.Rows = append(imp.report.Rows, row)
+	}
+
+	for _, activity := range batch.activities {
+		if err := imp.activity(activity); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := imp.importReviews(options.UserID, batch.reviews); err != nil {
+		return nil, err
+	}
+
+	if options.DryRun {
+		return imp.report, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return imp.report, nil
+}
+
+// readDelimited reads a CSV or TSV file of words of the pair
+func readDelimited(q queryer, data []byte, comma rune, options ImportOptions) (*importBatch, error) {
+	pair := options.Pair.orDefault()
+	source, target, err := requireLanguagePair(q, pair)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]bool)
+	for _, language := range []*models.Language{source, target} {
+		for _, field := range language.Fields {
+			fields[field.Name] = true
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	// Tab separated files rarely quote their values, so quotes inside them
+	// are kept as they are
+	reader.LazyQuotes = comma == '\t'
+
+	columns := options.Columns
+	if !options.NoHeader {
+		header, err := reader.Read()
+		if err == io.EOF {
+			return nil, &ValidationError{Message: "the file is empty"}
+		}
+		if err != nil {
+			return nil, &ValidationError{Message: "the file is not valid: " + err.Error()}
+		}
+		if columns == nil {
+			columns = header
+		}
+	} else if columns == nil {
+		return nil, newFieldsError([]FieldError{{Field: "columns", Message: "is required when the file has no header row"}})
+	}
+
+	var errs []FieldError
+	columns = slices.Clone(columns)
+	for i, column := range columns {
+		column = strings.TrimSpace(column)
+		columns[i] = column
+		if column == "" || column == "-" || column == GroupsColumn || fields[column] || columnAttribute(column, fields) != "" {
+			continue
+		}
+		errs = append(errs, FieldError{
+			Field:   fmt.Sprintf("columns[%d]", i),
+			Message: fmt.Sprintf("%s is not a field of %s or %s, a word attribute or %s", column, source.Name, target.Name, GroupsColumn),
+		})
+	}
+	if len(errs) > 0 {
+		return nil, newFieldsError(errs)
+	}
+
+	batch := &importBatch{}
+	var groups []groupKey
+	if name := strings.TrimSpace(options.Group); name != "" {
+		groups = []groupKey{{name, pair}}
+		batch.groups = groups
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &ValidationError{Message: "the file is not valid: " + err.Error()}
+		}
+		line, _ := reader.FieldPos(0)
+
+		word := importWord{row: line, pair: pair, groups: slices.Clone(groups)}
+		if len(record) != len(columns) {
+			word.errs = append(word.errs, FieldError{
+				Field:   "columns",
+				Message: fmt.Sprintf("the row has %d columns instead of %d", len(record), len(columns)),
+			})
+		}
+
+		parts := make(map[string]string)
+		attributes := make(map[string]string)
+		for i, value := range record[:min(len(record), len(columns))] {
+			value = strings.TrimSpace(value)
+			switch column := columns[i]; {
+			case value == "" || column == "" || column == "-":
+			case column == GroupsColumn:
+				for _, name := range strings.Split(value, groupSeparator) {
+					if name = strings.TrimSpace(name); name != "" {
+						word.groups = append(word.groups, groupKey{name, pair})
+					}
+				}
+			case fields[column]:
+				parts[column] = value
+			default:
+				attributes[columnAttribute(column, fields)] = value
+			}
+		}
+
+		if word.parts, err = json.Marshal(parts); err != nil {
+			return nil, err
+		}
+		if len(attributes) > 0 {
+			if word.attributes, err = json.Marshal(attributes); err != nil {
+				return nil, err
+			}
+		}
+		batch.words = append(batch.words, word)
+	}
+
+	return batch, nil
+}
+
+// readSeed reads a file in the shape of db/seeds
+func readSeed(data []byte) (*importBatch, error) {
+	var file SeedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, &ValidationError{Message: "the file is not a valid seed file: " + err.Error()}
+	}
+
+	name := strings.TrimSpace(file.GroupName)
+	if name == "" {
+		return nil, newFieldsError([]FieldError{{Field: "group_name", Message: "is required"}})
+	}
+	group := groupKey{name, LanguagePair{file.SourceLanguage, file.TargetLanguage}.orDefault()}
+
+	batch := &importBatch{groups: []groupKey{group}}
+	for i, entry := range file.Words {
+		attributes := entry["attributes"]
+		delete(entry, "attributes")
+		parts, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		batch.words = append(batch.words, importWord{
+			row:        i + 1,
+			pair:       group.pair,
+			parts:      parts,
+			attributes: attributes,
+			groups:     []groupKey{group},
+		})
+	}
+	return batch, nil
+}
+
+// readBundle reads a bundle. Groups, links and activities must all be valid
+// for the bundle to be imported.
+func readBundle(data []byte) (*importBatch, error) {
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, &ValidationError{Message: "the file is not a valid bundle: " + err.Error()}
+	}
+	if bundle.Version != BundleVersion {
+		return nil, newFieldsError([]FieldError{{Field: "version", Message: fmt.Sprintf("must be %d", BundleVersion)}})
+	}
+
+	var errs []FieldError
+	batch := &importBatch{}
+
+	groups := make(map[int64]groupKey)
+	for i, group := range bundle.Groups {
+		name := strings.TrimSpace(group.Name)
+		if name == "" {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("groups[%d].name", i), Message: "is required"})
+			continue
+		}
+		if _, ok := groups[group.ID]; ok {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("groups[%d].id", i), Message: "is used by another group"})
+			continue
+		}
+		key := groupKey{name, LanguagePair{group.SourceLanguage, group.TargetLanguage}.orDefault()}
+		groups[group.ID] = key
+		batch.groups = append(batch.groups, key)
+	}
+
+	words := make(map[int64]int)
+	for i, word := range bundle.Words {
+		if _, ok := words[word.ID]; ok {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("words[%d].id", i), Message: "is used by another word"})
+			continue
+		}
+		words[word.ID] = len(batch.words)
+		batch.words = append(batch.words, importWord{
+			row:        i + 1,
+			pair:       LanguagePair{word.SourceLanguage, word.TargetLanguage}.orDefault(),
+			parts:      word.Parts,
+			attributes: word.Attributes,
+		})
+	}
+
+	for i, link := range bundle.WordGroups {
+		word, ok := words[link.WordID]
+		if !ok {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("word_groups[%d].word_id", i), Message: "is not a word of the bundle"})
+		}
+		group, found := groups[link.GroupID]
+		if !found {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("word_groups[%d].group_id", i), Message: "is not a group of the bundle"})
+		}
+		if ok && found {
+			batch.words[word].groups = append(batch.words[word].groups, group)
+		}
+	}
+
+	for i, activity := range bundle.StudyActivities {
+		checked := models.StudyActivity{Name: activity.Name, URL: activity.URL, Scheduler: activity.Scheduler}
+		if err := validateActivity(&checked); err != nil {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("study_activities[%d]", i), Message: err.Error()})
+		}
+	}
+	batch.activities = bundle.StudyActivities
+
+	if len(errs) > 0 {
+		return nil, newFieldsError(errs)
+	}
+	return batch, nil
+}
+
+// importer stores the words of a batch in a transaction
+type importer struct {
+	tx     *sql.Tx
+	report *ImportReport
+	// groups caches the ids of the groups used so far
+	groups map[groupKey]int64
+	// words maps the duplicate keys of existing and imported words to
+	// their ids
+	words map[string]int64
+	// created holds the ids of the words imported
+	created map[int64]bool
+	// wordIDs holds the id of each word of the batch, or 0 if it failed
+	wordIDs []int64
+	// matchOnly matches words and groups to existing ones without creating
+	// or changing any
+	matchOnly bool
+}
+
+// wordKey identifies words that duplicate each other: the same language
+// pair and parts, whatever the order of the parts
+func wordKey(pair LanguagePair, parts string) string {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(parts), &values); err == nil {
+		if canonical, err := json.Marshal(values); err == nil {
+			parts = string(canonical)
+		}
+	}
+	return pair.Source + "\x00" + pair.Target + "\x00" + parts
+}
+
+func (imp *importer) loadWords() error {
+	rows, err := imp.tx.Query("SELECT id, source_language, target_language, parts FROM words")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var pair LanguagePair
+		var parts string
+		if err := rows.Scan(&id, &pair.Source, &pair.Target, &parts); err != nil {
+			return err
+		}
+		key := wordKey(pair, parts)
+		if _, ok := imp.words[key]; !ok {
+			imp.words[key] = id
+		}
+	}
+	return rows.Err()
+}
+
+// group returns the id of the group, creating it if needed. A group that
+// does not exist is 0 when only matching.
+func (imp *importer) group(key groupKey) (int64, error) {
+	if id, ok := imp.groups[key]; ok {
+		return id, nil
+	}
+
+	var id int64
+	err := imp.tx.QueryRow(`
+		SELECT id FROM groups
+		WHERE name = ? AND source_language = ? AND target_language = ?
+		ORDER BY id
+		LIMIT 1
+	`, key.name, key.pair.Source, key.pair.Target).Scan(&id)
+	if err == sql.ErrNoRows && imp.matchOnly {
+		imp.groups[key] = 0
+		return 0, nil
+	}
+	if err == sql.ErrNoRows {
+		if _, _, err := requireLanguagePair(imp.tx, key.pair); err != nil {
+			return 0, &ValidationError{Message: fmt.Sprintf("group %s: %s", key.name, err.Error())}
+		}
+
+		result, err := imp.tx.Exec(`
+			INSERT INTO groups (name, source_language, target_language) VALUES (?, ?, ?)
+		`, key.name, key.pair.Source, key.pair.Target)
+		if err != nil {
+			return 0, err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return 0, err
+		}
+		imp.report.Summary.GroupsCreated++
+	} else if err != nil {
+		return 0, err
+	}
+
+	imp.groups[key] = id
+	return id, nil
+}
+
+// importWord stores a word unless it has errors or duplicates another, and
+// adds it to its groups. Validation problems are reported in the row; other
+// errors fail the import.
+func (imp *importer) importWord(word importWord) (ImportRow, error) {
+	row := ImportRow{Row: word.row, Status: ImportFailed}
+	errs := word.errs
+
+	parts, err := normalizeWordParts(imp.tx, word.pair, word.parts)
+	if errs, err = appendRowErrors(errs, err, "parts"); err != nil {
+		return row, err
+	}
+	attributes, err := applyWordAttributes(models.WordAttributes{}, word.attributes)
+	if errs, err = appendRowErrors(errs, err, "attributes"); err != nil {
+		return row, err
+	}
+
+	var groupIDs []int64
+	for _, key := range word.groups {
+		if len(errs) > 0 || imp.matchOnly {
+			break
+		}
+		if key.pair != word.pair {
+			errs = append(errs, FieldError{Field: GroupsColumn, Message: fmt.Sprintf("group %s is for other languages", key.name)})
+			continue
+		}
+		id, err := imp.group(key)
+		if err != nil {
+			return row, err
+		}
+		groupIDs = append(groupIDs, id)
+	}
+
+	if len(errs) > 0 {
+		row.Errors = errs
+		return row, nil
+	}
+
+	key := wordKey(word.pair, parts)
+	id, duplicate := imp.words[key]
+	switch {
+	case duplicate:
+		row.Status = ImportDuplicate
+	case imp.matchOnly:
+		row.Status = ImportUnmatched
+		return row, nil
+	default:
+		if id, err = insertWord(imp.tx, word.pair, parts, attributes); err != nil {
+			return row, err
+		}
+		imp.words[key] = id
+		imp.created[id] = true
+		row.Status = ImportCreated
+	}
+	row.WordID = id
+
+	for _, groupID := range groupIDs {
+		_, err := imp.tx.Exec(`
+			INSERT OR IGNORE INTO word_groups (word_id, group_id)
+			VALUES (?, ?)
+		`, id, groupID)
+		if err != nil {
+			return row, err
+		}
+	}
+	return row, nil
+}
+
+// appendRowErrors adds the problems of a ValidationError to errs, naming
+// field when the error has no field errors of its own. Other errors are
+// returned.
+func appendRowErrors(errs []FieldError, err error, field string) ([]FieldError, error) {
+	if err == nil {
+		return errs, nil
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return errs, err
+	}
+	if len(validationErr.Fields) > 0 {
+		return append(errs, validationErr.Fields...), nil
+	}
+	return append(errs, FieldError{Field: field, Message: validationErr.Message}), nil
+}
+
+// activity stores a study activity of a bundle unless one with its name
+// exists
+func (imp *importer) activity(activity BundleActivity) error {
+	var exists bool
+	err := imp.tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM study_activities WHERE name = ?)
+	`, strings.TrimSpace(activity.Name)).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+
+	stored := models.StudyActivity{
+		Name:         activity.Name,
+		URL:          activity.URL,
+		ThumbnailURL: activity.ThumbnailURL,
+		Description:  activity.Description,
+		Scheduler:    activity.Scheduler,
+	}
+	if err := validateActivity(&stored); err != nil {
+		return err
+	}
+
+	_, err = imp.tx.Exec(`
+		INSERT INTO study_activities (name, url, thumbnail_url, description, scheduler)
+		VALUES (?, ?, ?, ?, ?)
+	`, stored.Name, stored.URL, stored.ThumbnailURL, stored.Description, nullString(stored.Scheduler))
+	if err != nil {
+		return err
+	}
+	imp.report.Summary.ActivitiesCreated++
+	return nil
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+const (
+	// DefaultSourceLanguage and DefaultTargetLanguage are used for words and
+	// groups created without a language pair
+	DefaultSourceLanguage = "fr"
+	DefaultTargetLanguage = "en"
+)
+
+var (
+	languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]+)*$`)
+	fieldNamePattern    = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// LanguagePair selects the words and groups of one course. An empty code
+// matches any language.
+type LanguagePair struct {
+	Source string
+	Target string
+}
+
+// orDefault fills in the default language for empty codes
+func (p LanguagePair) orDefault() LanguagePair {
+	if p.Source == "" {
+		p.Source = DefaultSourceLanguage
+	}
+	if p.Target == "" {
+		p.Target = DefaultTargetLanguage
+	}
+	return p
+}
+
+// filter returns a condition restricting the source_language and
+// target_language columns of table to the pair, with its arguments
+func (p LanguagePair) filter(table string) (string, []interface{}) {
+	condition := fmt.Sprintf(
+		"(? = '' OR %[1]s.source_language = ?) AND (? = '' OR %[1]s.target_language = ?)",
+		table,
+	)
+	return condition, []interface{}{p.Source, p.Source, p.Target, p.Target}
+}
+
+type LanguageService struct {
+	db *sql.DB
+}
+
+func NewLanguageService() *LanguageService {
+	return &LanguageService{
+		db: storage.GetDB(),
+	}
+}
+
+// List returns every language with its fields
+func (s *LanguageService) List() ([]models.Language, error) {
+	rows, err := s.db.Query("SELECT code FROM languages ORDER BY code")
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	languages := []models.Language{}
+	for _, code := range codes {
+		language, err := loadLanguage(s.db, code)
+		if err != nil {
+			return nil, err
+		}
+		languages = append(languages, *language)
+	}
+
+	return languages, nil
+}
+
+// Get returns a language, or nil if it does not exist
+func (s *LanguageService) Get(code string) (*models.Language, error) {
+	return loadLanguage(s.db, code)
+}
+
+// Create adds a language with its fields
+func (s *LanguageService) Create(language models.Language) (*models.Language, error) {
+	if !languageCodePattern.MatchString(language.Code) {
+		return nil, &ValidationError{Message: "code must be a lowercase language code such as fr or pt-br"}
+	}
+	if err := validateLanguage(&language); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := loadLanguage(tx, language.Code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, &ConflictError{Message: fmt.Sprintf("language %s already exists", language.Code)}
+	}
+
+	if _, err := tx.Exec("INSERT INTO languages (code, name) VALUES (?, ?)", language.Code, language.Name); err != nil {
+		return nil, err
+	}
+	if err := saveLanguageFields(tx, language); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.Get(language.Code)
+}
+
+// Update replaces a language's name and fields. Words already stored are
+// not checked against the new fields until they are next changed.
+func (s *LanguageService) Update(code string, language models.Language) (*models.Language, error) {
+	language.Code = code
+	if err := validateLanguage(&language); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE languages SET name = ? WHERE code = ?", language.Name, code)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, &NotFoundError{Message: fmt.Sprintf("language %s not found", code)}
+	}
+
+	if err := saveLanguageFields(tx, language); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.Get(code)
+}
+
+// Delete removes a language that no word or group uses
+func (s *LanguageService) Delete(code string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists, used bool
+	err = tx.QueryRow(`
+		SELECT
+			EXISTS(SELECT 1 FROM languages WHERE code = ?),
+			EXISTS(SELECT 1 FROM words WHERE source_language = ? OR target_language = ?)
+			OR EXISTS(SELECT 1 FROM groups WHERE source_language = ? OR target_language = ?)
+	`, code, code, code, code, code).Scan(&exists, &used)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &NotFoundError{Message: fmt.Sprintf("language %s not found", code)}
+	}
+	if used {
+		return &ConflictError{Message: fmt.Sprintf("language %s is used by words or groups", code)}
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM language_fields WHERE language_code = ?;
+		DELETE FROM languages WHERE code = ?;
+	`, code, code)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// validateLanguage trims the name and checks that the fields are well formed
+func validateLanguage(language *models.Language) error {
+	language.Name = strings.TrimSpace(language.Name)
+	if language.Name == "" {
+		return &ValidationError{Message: "name is required"}
+	}
+	if len(language.Fields) == 0 {
+		return &ValidationError{Message: "fields must not be empty"}
+	}
+
+	names := make(map[string]bool)
+	for _, field := range language.Fields {
+		if !fieldNamePattern.MatchString(field.Name) {
+			return &ValidationError{Message: fmt.Sprintf("field name %q must be lowercase letters, digits and underscores", field.Name)}
+		}
+		if names[field.Name] {
+			return &ValidationError{Message: fmt.Sprintf("field %s is listed twice", field.Name)}
+		}
+		names[field.Name] = true
+
+		for _, value := range field.Values {
+			if strings.TrimSpace(value) == "" {
+				return &ValidationError{Message: fmt.Sprintf("field %s has an empty value", field.Name)}
+			}
+		}
+	}
+
+	return nil
+}
+
+func saveLanguageFields(tx *sql.Tx, language models.Language) error {
+	if _, err := tx.Exec("DELETE FROM language_fields WHERE language_code = ?", language.Code); err != nil {
+		return err
+	}
+
+	for i, field := range language.Fields {
+		var values interface{}
+		if len(field.Values) > 0 {
+			encoded, err := json.Marshal(field.Values)
+			if err != nil {
+				return err
+			}
+			values = string(encoded)
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO language_fields (language_code, name, required, allowed_values, position)
+			VALUES (?, ?, ?, ?, ?)
+		`, language.Code, field.Name, field.Required, values, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadLanguage returns a language with its fields in order, or nil if it
+// does not exist
+func loadLanguage(q queryer, code string) (*models.Language, error) {
+	language := models.Language{Code: code, Fields: []models.LanguageField{}}
+	err := q.QueryRow("SELECT name FROM languages WHERE code = ?", code).Scan(&language.Name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(`
+		SELECT name, required, allowed_values
+		FROM language_fields
+		WHERE language_code = ?
+		ORDER BY position, name
+	`, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var field models.LanguageField
+		var values sql.NullString
+		if err := rows.Scan(&field.Name, &field.Required, &values); err != nil {
+			return nil, err
+		}
+		if values.Valid {
+			if err := json.Unmarshal([]byte(values.String), &field.Values); err != nil {
+				return nil, err
+			}
+		}
+		language.Fields = append(language.Fields, field)
+	}
+
+	return &language, rows.Err()
+}
+
+// requireLanguagePair checks that both languages exist and differ
+func requireLanguagePair(q queryer, pair LanguagePair) (source, target *models.Language, err error) {
+	if pair.Source == pair.Target {
+		return nil, nil, &ValidationError{Message: "source_language and target_language must differ"}
+	}
+
+	if source, err = loadLanguage(q, pair.Source); err != nil {
+		return nil, nil, err
+	}
+	if source == nil {
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("source_language %s does not exist", pair.Source)}
+	}
+
+	if target, err = loadLanguage(q, pair.Target); err != nil {
+		return nil, nil, err
+	}
+	if target == nil {
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("target_language %s does not exist", pair.Target)}
+	}
+
+	return source, target, nil
+}
+
+// requireMatchingLanguages returns a ValidationError if a word and a group
+// linked through word_groups have different language pairs. column is
+// wg.word_id or wg.group_id and limits the check to one word or group.
+func requireMatchingLanguages(q queryer, column string, id int64) error {
+	var wordID, groupID int64
+	err := q.QueryRow(`
+		SELECT w.id, g.id
+		FROM word_groups wg
+		JOIN words w ON wg.word_id = w.id
+		JOIN groups g ON wg.group_id = g.id
+		WHERE `+column+` = ?
+		AND (w.source_language != g.source_language OR w.target_language != g.target_language)
+		LIMIT 1
+	`, id).Scan(&wordID, &groupID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return &ValidationError{Message: fmt.Sprintf("word %d and group %d have different languages", wordID, groupID)}
+}
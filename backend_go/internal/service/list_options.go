@@ -0,0 +1,270 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The orders a list can be sorted in
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// ListOptions sort and filter a list. They hold the raw query parameters and
+// are checked against what each list supports; empty options keep the
+// list's default order and match every row. From and To are dates such as
+// 2025-01-31 or times in RFC 3339, From inclusive and To exclusive; a date
+// To includes the whole day.
+type ListOptions struct {
+	SortBy     string
+	Order      string
+	GroupID    string
+	ActivityID string
+	From       string
+	To         string
+}
+
+// sortField is a sort_by value and the expression it orders by
+type sortField struct {
+	name       string
+	expression string
+}
+
+// listSpec describes how a list can be sorted and filtered
+type listSpec struct {
+	// sorts are the fields the list can be sorted by, the default first
+	sorts []sortField
+	// order is the default order
+	order string
+	// id orders rows that sort equal so pages are stable
+	id string
+	// words is the alias of the words table when the list can also be
+	// sorted by the fields of word parts, such as french or romaji
+	words string
+	// group and activity are conditions on the group_id and activity_id
+	// arguments, and created the time filtered by from and to. Empty if
+	// the list can't be filtered by them.
+	group    string
+	activity string
+	created  string
+}
+
+// listQuery is the SQL of checked list options: a condition to AND with the
+// list's own, its arguments and the ORDER BY clause
+type listQuery struct {
+	condition string
+	args      []interface{}
+	orderBy   string
+}
+
+// query checks the options against the list and returns them as SQL
+func (o ListOptions) query(q queryer, spec listSpec) (*listQuery, error) {
+	var errs []FieldError
+	query := &listQuery{condition: "1"}
+
+	expression, err := o.sortExpression(q, spec)
+	if err != nil {
+		return nil, err
+	}
+	if expression == "" {
+		errs = append(errs, FieldError{Field: "sort_by", Message: "must be one of " + spec.sortNames()})
+	}
+
+	order := spec.order
+	switch o.Order {
+	case "":
+	case OrderAsc, OrderDesc:
+		order = o.Order
+	default:
+		errs = append(errs, FieldError{Field: "order", Message: "must be asc or desc"})
+	}
+
+	query.orderBy = expression + " " + order
+	if expression != spec.id {
+		query.orderBy += ", " + spec.id + " " + order
+	}
+
+	filters := []struct {
+		field     string
+		value     string
+		condition string
+	}{
+		{"group_id", o.GroupID, spec.group},
+		{"activity_id", o.ActivityID, spec.activity},
+	}
+	for _, filter := range filters {
+		if filter.value == "" {
+			continue
+		}
+		if filter.condition == "" {
+			errs = append(errs, FieldError{Field: filter.field, Message: "is not supported by this list"})
+			continue
+		}
+		id, err := strconv.ParseInt(filter.value, 10, 64)
+		if err != nil || id <= 0 {
+			errs = append(errs, FieldError{Field: filter.field, Message: "must be an id"})
+			continue
+		}
+		query.condition += " AND " + filter.condition
+		query.args = append(query.args, id)
+	}
+
+	bounds := []struct {
+		field    string
+		value    string
+		operator string
+	}{
+		{"from", o.From, ">="},
+		{"to", o.To, "<"},
+	}
+	for _, bound := range bounds {
+		if bound.value == "" {
+			continue
+		}
+		if spec.created == "" {
+			errs = append(errs, FieldError{Field: bound.field, Message: "is not supported by this list"})
+			continue
+		}
+		t, ok := parseListTime(bound.value, bound.field == "to")
+		if !ok {
+			errs = append(errs, FieldError{Field: bound.field, Message: "must be a date such as 2025-01-31 or an RFC 3339 time"})
+			continue
+		}
+		query.condition += fmt.Sprintf(" AND julianday(%s) %s julianday(?)", spec.created, bound.operator)
+		query.args = append(query.args, t.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	if len(errs) > 0 {
+		return nil, newFieldsError(errs)
+	}
+	return query, nil
+}
+
+// sortExpression returns the expression to order by, or "" if the list
+// can't be sorted by SortBy
+func (o ListOptions) sortExpression(q queryer, spec listSpec) (string, error) {
+	if o.SortBy == "" {
+		return spec.sorts[0].expression, nil
+	}
+	for _, field := range spec.sorts {
+		if field.name == o.SortBy {
+			return field.expression, nil
+		}
+	}
+
+	if spec.words == "" || !fieldNamePattern.MatchString(o.SortBy) {
+		return "", nil
+	}
+	var exists bool
+	err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM language_fields WHERE name = ?)", o.SortBy).Scan(&exists)
+	if err != nil || !exists {
+		return "", err
+	}
+	return fmt.Sprintf("json_extract(%s.parts, '$.%s') COLLATE NOCASE", spec.words, o.SortBy), nil
+}
+
+func (spec listSpec) sortNames() string {
+	names := make([]string, len(spec.sorts))
+	for i, field := range spec.sorts {
+		names[i] = field.name
+	}
+	if spec.words != "" {
+		names = append(names, "a language field such as french")
+	}
+	return strings.Join(names, ", ")
+}
+
+// parseListTime parses a date or RFC 3339 time. A date that ends a range is
+// moved to the end of the day.
+func parseListTime(value string, end bool) (time.Time, bool) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, err == nil
+}
+
+// The lists of each service
+var (
+	wordListSpec = listSpec{
+		sorts: []sortField{
+			{"id", "w.id"},
+			{"correct_count", "correct_count"},
+			{"wrong_count", "wrong_count"},
+		},
+		order: OrderAsc,
+		id:    "w.id",
+		words: "w",
+		group: "w.id IN (SELECT word_id FROM word_groups WHERE group_id = ?)",
+	}
+
+	// memberWordListSpec lists the words of a group or study session
+	memberWordListSpec = listSpec{
+		sorts: []sortField{{"id", "w.id"}},
+		order: OrderAsc,
+		id:    "w.id",
+		words: "w",
+	}
+
+	groupListSpec = listSpec{
+		sorts: []sortField{
+			{"id", "groups.id"},
+			{"name", "groups.name COLLATE NOCASE"},
+			{"words_count", "groups.words_count"},
+		},
+		order: OrderAsc,
+		id:    "groups.id",
+	}
+
+	activityListSpec = listSpec{
+		sorts: []sortField{
+			{"id", "id"},
+			{"name", "name COLLATE NOCASE"},
+		},
+		order: OrderAsc,
+		id:    "id",
+	}
+
+	sessionListSpec = listSpec{
+		sorts: []sortField{
+			{"created_at", "julianday(ss.created_at)"},
+			{"id", "ss.id"},
+			{"ended_at", "julianday(ss.ended_at)"},
+			{"review_items_count", "review_items_count"},
+			{"group_name", "group_name COLLATE NOCASE"},
+			{"activity_name", "activity_name COLLATE NOCASE"},
+		},
+		order:    OrderDesc,
+		id:       "ss.id",
+		group:    "ss.group_id = ?",
+		activity: "ss.study_activity_id = ?",
+		created:  "ss.created_at",
+	}
+
+	// groupSessionListSpec and activitySessionListSpec list the sessions of
+	// one group or activity
+	groupSessionListSpec = listSpec{
+		sorts: []sortField{
+			{"created_at", "julianday(created_at)"},
+			{"id", "id"},
+		},
+		order:    OrderDesc,
+		id:       "id",
+		activity: "study_activity_id = ?",
+		created:  "created_at",
+	}
+
+	activitySessionListSpec = listSpec{
+		sorts:   groupSessionListSpec.sorts,
+		order:   OrderDesc,
+		id:      "id",
+		group:   "group_id = ?",
+		created: "created_at",
+	}
+)
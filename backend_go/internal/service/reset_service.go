@@ -0,0 +1,239 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+// Destructive actions that must be confirmed with a confirmation token
+const (
+	ActionResetHistory = "reset_history"
+	ActionFullReset    = "full_reset"
+)
+
+// ConfirmationTTL is how long a confirmation token can be used
+const ConfirmationTTL = 5 * time.Minute
+
+type ResetService struct {
+	db      *sql.DB
+	backups *BackupService
+}
+
+func NewResetService() *ResetService {
+	return &ResetService{
+		db:      storage.GetDB(),
+		backups: NewBackupService(),
+	}
+}
+
+// Confirmation is a single-use token that allows one destructive action
+type Confirmation struct {
+	Token     string    `json:"confirmation_token"`
+	Action    string    `json:"action"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RequestConfirmation issues a token that lets userID perform action once
+// within ConfirmationTTL
+func (s *ResetService) RequestConfirmation(userID int64, action string) (*Confirmation, error) {
+	if action != ActionResetHistory && action != ActionFullReset && action != ActionRestoreBackup {
+		return nil, &ValidationError{Message: "action must be one of reset_history, full_reset, restore_backup"}
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(ConfirmationTTL)
+
+	_, err = s.db.Exec(`
+		INSERT INTO confirmation_tokens (token_hash, user_id, action, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, hashToken(token), userID, action, now, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Confirmation{Token: token, Action: action, ExpiresAt: expiresAt}, nil
+}
+
+// ResetHistory archives the study sessions of targetUserID in scope and
+// their reviews on behalf of actorID, like HistoryResetService.Reset, and
+// returns the reset, which can be restored until it expires
+func (s *ResetService) ResetHistory(actorID, targetUserID int64, scope HistoryResetScope, confirmationToken string) (*models.HistoryReset, error) {
+	details := map[string]interface{}{"user_id": targetUserID}
+	if scope != (HistoryResetScope{}) {
+		details["scope"] = scope
+	}
+
+	var reset *models.HistoryReset
+	err := s.reset(actorID, ActionResetHistory, confirmationToken, details, func(tx *sql.Tx) error {
+		var err error
+		if reset, err = resetHistory(tx, actorID, targetUserID, scope); err != nil {
+			return err
+		}
+		details["reset_id"] = reset.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reset, nil
+}
+
+// FullReset deletes every user's study history and all vocabulary and
+// activities on behalf of actorID, including the archives of history resets.
+// Accounts and the audit log are kept.
+func (s *ResetService) FullReset(actorID int64, confirmationToken string) error {
+	return s.reset(actorID, ActionFullReset, confirmationToken, nil, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			DELETE FROM history_resets;
+			DELETE FROM word_review_items;
+			DELETE FROM word_review_states;
+			DELETE FROM study_sessions;
+			DELETE FROM class_assignments;
+			DELETE FROM word_groups;
+			DELETE FROM words;
+			DELETE FROM groups;
+			DELETE FROM study_activities;
+		`)
+		return err
+	})
+}
+
+// reset snapshots the database when backups are enabled, then runs wipe
+// after consuming the confirmation token and records the reset in the audit
+// log, all in one transaction. The snapshot is named in the audit entry.
+func (s *ResetService) reset(actorID int64, action, confirmationToken string, details map[string]interface{}, wipe func(tx *sql.Tx) error) error {
+	if s.backups.Enabled() {
+		// SQLite cannot snapshot inside a transaction, so the token is
+		// checked first to only snapshot for confirmed resets
+		if err := checkConfirmation(s.db, actorID, action, confirmationToken); err != nil {
+			return err
+		}
+		snapshot, err := s.backups.Snapshot(action)
+		if err != nil {
+			return err
+		}
+		if details == nil {
+			details = make(map[string]interface{})
+		}
+		details["snapshot"] = snapshot.Name
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := consumeConfirmation(tx, actorID, action, confirmationToken); err != nil {
+		return err
+	}
+
+	if err := wipe(tx); err != nil {
+		return err
+	}
+
+	// Resets without details are stored with NULL details, not "null"
+	var audited interface{}
+	if details != nil {
+		audited = details
+	}
+	if err := recordAudit(tx, actorID, action, audited); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// consumeConfirmation deletes the token and returns a ValidationError unless
+// it was issued to userID for action and has not expired
+func consumeConfirmation(tx *sql.Tx, userID int64, action, token string) error {
+	return confirmationQuery(tx, `
+		DELETE FROM confirmation_tokens
+		WHERE token_hash = ? AND user_id = ? AND action = ?
+		RETURNING expires_at
+	`, userID, action, token)
+}
+
+// checkConfirmation is consumeConfirmation without using up the token
+func checkConfirmation(q queryer, userID int64, action, token string) error {
+	return confirmationQuery(q, `
+		SELECT expires_at FROM confirmation_tokens
+		WHERE token_hash = ? AND user_id = ? AND action = ?
+	`, userID, action, token)
+}
+
+func confirmationQuery(q queryer, query string, userID int64, action, token string) error {
+	if token == "" {
+		return &ValidationError{Message: "confirmation_token is required, request one from POST /api/reset_confirmations"}
+	}
+
+	var expiresAt time.Time
+	err := q.QueryRow(query, hashToken(token), userID, action).Scan(&expiresAt)
+	if err == sql.ErrNoRows || (err == nil && !time.Now().Before(expiresAt)) {
+		return &ValidationError{Message: "confirmation_token is invalid or expired"}
+	}
+	return err
+}
+
+// AuditLog returns a page of audit entries, newest first
+func (s *ResetService) AuditLog(page, perPage int) ([]models.AuditEntry, int, error) {
+	offset := (page - 1) * perPage
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM audit_log").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT a.id, a.user_id, u.email, a.action, COALESCE(a.details, 'null'), a.created_at
+		FROM audit_log a
+		JOIN users u ON a.user_id = u.id
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT ? OFFSET ?
+	`, perPage, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		var entry models.AuditEntry
+		var details []byte
+		err := rows.Scan(&entry.ID, &entry.UserID, &entry.UserEmail, &entry.Action, &details, &entry.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		entry.Details = json.RawMessage(details)
+		entries = append(entries, entry)
+	}
+
+	return entries, total, rows.Err()
+}
+
+// recordAudit adds an entry to the audit log. details is stored as JSON.
+func recordAudit(tx *sql.Tx, userID int64, action string, details interface{}) error {
+	var encoded sql.NullString
+	if details != nil {
+		raw, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		encoded = sql.NullString{String: string(raw), Valid: true}
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO audit_log (user_id, action, details, created_at)
+		VALUES (?, ?, ?, ?)
+	`, userID, action, encoded, time.Now().UTC())
+	return err
+}
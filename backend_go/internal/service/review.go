@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/conjugation"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+)
+
+// Review directions
+const (
+	// DirectionRecognition shows the word in its source language and asks
+	// for the target language
+	DirectionRecognition = "recognition"
+	// DirectionProduction shows the word in its target language and asks for
+	// the source language
+	DirectionProduction = "production"
+)
+
+// passingQuality is the lowest quality that counts as a correct answer
+const passingQuality = 3
+
+// grades maps the again/hard/good/easy buttons onto the 0-5 quality scale
+var grades = map[string]int{
+	"again": 1,
+	"hard":  3,
+	"good":  4,
+	"easy":  5,
+}
+
+// ReviewInput is a graded answer to a word review. Tense and Person name
+// the form practiced in a conjugation drill.
+type ReviewInput struct {
+	Quality        int
+	Answer         string
+	ResponseTimeMs *int
+	Direction      string
+	Tense          string
+	Person         string
+}
+
+// QualityFromGrade converts an again/hard/good/easy grade to a quality
+func QualityFromGrade(grade string) (int, bool) {
+	quality, ok := grades[grade]
+	return quality, ok
+}
+
+// Correct reports whether the review counts as a correct answer
+func (r ReviewInput) Correct() bool {
+	return r.Quality >= passingQuality
+}
+
+// Validate checks the review's fields are within range
+func (r ReviewInput) Validate() error {
+	if r.Quality < 0 || r.Quality > 5 {
+		return &ValidationError{Message: "quality must be between 0 and 5"}
+	}
+	if r.ResponseTimeMs != nil && *r.ResponseTimeMs < 0 {
+		return &ValidationError{Message: "response_time_ms must not be negative"}
+	}
+	switch r.Direction {
+	case "", DirectionRecognition, DirectionProduction:
+	default:
+		return &ValidationError{Message: fmt.Sprintf("direction must be %q or %q", DirectionRecognition, DirectionProduction)}
+	}
+	if (r.Tense == "") != (r.Person == "") {
+		return &ValidationError{Message: "tense and person must be given together"}
+	}
+	if _, ok := conjugation.ParseTense(r.Tense); r.Tense != "" && !ok {
+		return &ValidationError{Message: "tense must be one of " + tenseNames()}
+	}
+	if _, ok := conjugation.ParsePerson(r.Person); r.Person != "" && !ok {
+		return &ValidationError{Message: "person must be one of je, tu, il, nous, vous, ils"}
+	}
+	return nil
+}
+
+const (
+	// MaxBatchReviews is the largest number of reviews accepted in one batch
+	MaxBatchReviews = 500
+	// maxClockSkew is how far in the future a client timestamp may be
+	maxClockSkew = 5 * time.Minute
+)
+
+// BatchReview is one review of a batch submission. ClientID is generated by
+// the client and identifies the review across retries. ReviewedAt defaults
+// to the time the batch is received.
+type BatchReview struct {
+	ClientID   string
+	WordID     int64
+	ReviewedAt *time.Time
+	Input      ReviewInput
+}
+
+// BatchReviewResponse reports the outcome of a batch submission. Items holds
+// the stored review for every submitted review in request order, including
+// duplicates.
+type BatchReviewResponse struct {
+	StudySessionID int64                   `json:"study_session_id"`
+	Created        int                     `json:"created"`
+	Duplicates     int                     `json:"duplicates"`
+	Items          []models.WordReviewItem `json:"items"`
+}
+
+func (r BatchReview) reviewedAt(now time.Time) time.Time {
+	if r.ReviewedAt == nil {
+		return now.UTC()
+	}
+	return r.ReviewedAt.UTC()
+}
+
+func validateBatch(reviews []BatchReview, now time.Time) error {
+	if len(reviews) == 0 {
+		return &ValidationError{Message: "reviews must not be empty"}
+	}
+	if len(reviews) > MaxBatchReviews {
+		return &ValidationError{Message: fmt.Sprintf("at most %d reviews can be sent at once", MaxBatchReviews)}
+	}
+
+	seen := make(map[string]bool, len(reviews))
+	for i, review := range reviews {
+		invalid := func(message string) error {
+			return &ValidationError{Message: fmt.Sprintf("reviews[%d]: %s", i, message)}
+		}
+
+		if review.ClientID == "" {
+			return invalid("id is required")
+		}
+		if len(review.ClientID) > 128 {
+			return invalid("id must be at most 128 characters")
+		}
+		if seen[review.ClientID] {
+			return invalid(fmt.Sprintf("id %q is used more than once", review.ClientID))
+		}
+		seen[review.ClientID] = true
+
+		if review.WordID <= 0 {
+			return invalid("word_id is required")
+		}
+		if review.ReviewedAt != nil && review.ReviewedAt.After(now.Add(maxClockSkew)) {
+			return invalid("reviewed_at is in the future")
+		}
+		if err := review.Input.Validate(); err != nil {
+			return invalid(err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+)
+
+// ReviewFilter selects the reviews listed. Zero fields match any review.
+type ReviewFilter struct {
+	WordID         int64
+	StudySessionID int64
+}
+
+// condition returns the filter and the user whose reviews are listed as a
+// condition on word_review_items, with its arguments
+func (f ReviewFilter) condition(userID int64) (string, []interface{}) {
+	return `
+		study_session_id IN (SELECT id FROM study_sessions WHERE user_id = ?)
+		AND (? = 0 OR word_id = ?)
+		AND (? = 0 OR study_session_id = ?)
+	`, []interface{}{userID, f.WordID, f.WordID, f.StudySessionID, f.StudySessionID}
+}
+
+// ListReviews returns a page of the user's word reviews matching the filter,
+// newest first
+func (s *SessionService) ListReviews(userID int64, filter ReviewFilter, page, perPage int) ([]models.WordReviewItem, int, error) {
+	offset := (page - 1) * perPage
+	condition, args := filter.condition(userID)
+
+	var total int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM word_review_items WHERE "+condition, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	reviews, err := s.queryReviews(`
+		SELECT `+reviewItemColumns+`
+		FROM word_review_items
+		WHERE `+condition+`
+		ORDER BY `+cursorOrder("created_at", "id")+`
+		LIMIT ? OFFSET ?
+	`, append(args, perPage, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+// ListReviewsAfter returns up to limit of the user's word reviews matching
+// the filter, newest first, starting after the cursor of a previous page.
+// It also returns the cursor of the next page, or "" if there is none.
+func (s *SessionService) ListReviewsAfter(userID int64, filter ReviewFilter, after string, limit int) ([]models.WordReviewItem, string, error) {
+	c, err := decodeCursor(after)
+	if err != nil {
+		return nil, "", err
+	}
+
+	condition, args := filter.condition(userID)
+	position, positionArgs := c.condition("created_at", "id")
+	args = append(args, positionArgs...)
+
+	// Fetch one more review than asked for to know if there is a next page
+	reviews, err := s.queryReviews(`
+		SELECT `+reviewItemColumns+`
+		FROM word_review_items
+		WHERE `+condition+` AND `+position+`
+		ORDER BY `+cursorOrder("created_at", "id")+`
+		LIMIT ?
+	`, append(args, limit+1)...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(reviews) <= limit {
+		return reviews, "", nil
+	}
+	reviews = reviews[:limit]
+	next, err := nextCursor(s.db, "word_review_items", reviews[limit-1].ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return reviews, next, nil
+}
+
+func (s *SessionService) queryReviews(query string, args ...interface{}) ([]models.WordReviewItem, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.WordReviewItem
+	for rows.Next() {
+		review, err := scanReviewItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+	return reviews, rows.Err()
+}
@@ -0,0 +1,61 @@
+package service
+
+import "sort"
+
+// User roles. Learners study, teachers also curate the vocabulary and
+// activities and run classes, and admins additionally manage accounts and
+// wipe data.
+const (
+	RoleAdmin   = "admin"
+	RoleTeacher = "teacher"
+	RoleLearner = "learner"
+)
+
+// Permission is an action guarded by role
+type Permission string
+
+const (
+	// PermStudy covers studying and reading the vocabulary
+	PermStudy Permission = "study"
+	// PermManageContent covers editing words, groups, activities and the
+	// default scheduler
+	PermManageContent Permission = "manage_content"
+	// PermTeach covers running classes: enrolling learners, assigning
+	// groups and following their progress
+	PermTeach Permission = "teach"
+	// PermManageUsers covers listing accounts and changing their roles
+	PermManageUsers Permission = "manage_users"
+	// PermResetData covers the destructive reset endpoints and their audit log
+	PermResetData Permission = "reset_data"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleLearner: {PermStudy},
+	RoleTeacher: {PermStudy, PermManageContent, PermTeach},
+	RoleAdmin:   {PermStudy, PermManageContent, PermTeach, PermManageUsers, PermResetData},
+}
+
+// HasPermission reports whether users with role may perform perm
+func HasPermission(role string, perm Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles returns the names of all roles
+func Roles() []string {
+	roles := make([]string, 0, len(rolePermissions))
+	for role := range rolePermissions {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+func validRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+)
+
+// DefaultSchedulerName is used when neither the study activity nor the
+// settings table choose a scheduler
+const DefaultSchedulerName = "sm2"
+
+// Scheduler turns the outcome of a review into the next schedule of a word.
+// Quality uses the SM-2 scale from 0 (complete blackout) to 5 (perfect).
+type Scheduler interface {
+	Name() string
+	Next(state models.ReviewState, quality int, now time.Time) models.ReviewState
+}
+
+var schedulers = map[string]Scheduler{
+	"sm2":     sm2Scheduler{},
+	"leitner": leitnerScheduler{},
+	"fsrs":    fsrsScheduler{},
+}
+
+// SchedulerByName returns the registered scheduler with the given name
+func SchedulerByName(name string) (Scheduler, bool) {
+	scheduler, ok := schedulers[name]
+	return scheduler, ok
+}
+
+// SchedulerNames returns the names of all registered schedulers
+func SchedulerNames() []string {
+	names := make([]string, 0, len(schedulers))
+	for name := range schedulers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// markReviewed stamps the common fields every scheduler updates
+func markReviewed(state models.ReviewState, scheduler string, now time.Time) models.ReviewState {
+	reviewedAt := now
+	state.Scheduler = scheduler
+	state.LastReviewedAt = &reviewedAt
+	state.DueAt = now.AddDate(0, 0, state.IntervalDays)
+	return state
+}
+
+// sm2Scheduler implements the SuperMemo 2 algorithm
+type sm2Scheduler struct{}
+
+func (sm2Scheduler) Name() string { return "sm2" }
+
+func (sm2Scheduler) Next(state models.ReviewState, quality int, now time.Time) models.ReviewState {
+	if state.EaseFactor == 0 {
+		state.EaseFactor = defaultEaseFactor
+	}
+
+	if quality < 3 {
+		state.Repetitions = 0
+		state.IntervalDays = 1
+		state.Lapses++
+	} else {
+		state.Repetitions++
+		switch state.Repetitions {
+		case 1:
+			state.IntervalDays = 1
+		case 2:
+			state.IntervalDays = 6
+		default:
+			state.IntervalDays = int(math.Round(float64(state.IntervalDays) * state.EaseFactor))
+		}
+	}
+
+	q := float64(5 - quality)
+	state.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if state.EaseFactor < minEaseFactor {
+		state.EaseFactor = minEaseFactor
+	}
+
+	return markReviewed(state, "sm2", now)
+}
+
+// leitnerIntervals are the review intervals in days of each Leitner box
+var leitnerIntervals = []int{1, 2, 4, 8, 16}
+
+// leitnerScheduler moves words up one box on a pass and back to the first
+// box on a fail
+type leitnerScheduler struct{}
+
+func (leitnerScheduler) Name() string { return "leitner" }
+
+func (leitnerScheduler) Next(state models.ReviewState, quality int, now time.Time) models.ReviewState {
+	if quality < 3 {
+		state.Box = 1
+		state.Repetitions = 0
+		state.Lapses++
+	} else {
+		state.Box++
+		if state.Box > len(leitnerIntervals) {
+			state.Box = len(leitnerIntervals)
+		}
+		state.Repetitions++
+	}
+
+	state.IntervalDays = leitnerIntervals[state.Box-1]
+
+	return markReviewed(state, "leitner", now)
+}
+
+// fsrsWeights are the default FSRS v4 model parameters
+var fsrsWeights = [17]float64{
+	0.4, 0.6, 2.4, 5.8, 4.93, 0.94, 0.86, 0.01, 1.49,
+	0.14, 0.94, 2.18, 0.05, 0.34, 1.26, 0.29, 2.61,
+}
+
+// fsrsDecay and fsrsFactor shape the FSRS v4 forgetting curve
+// R = (1 + t/9S)^-1, which is 0.9 after S days
+const (
+	fsrsDecay           = -1.0
+	fsrsFactor          = 1.0 / 9.0
+	fsrsRequestedRecall = 0.9
+)
+
+// fsrsScheduler implements version 4 of the Free Spaced Repetition Scheduler
+// using its default parameters
+type fsrsScheduler struct{}
+
+func (fsrsScheduler) Name() string { return "fsrs" }
+
+func (fsrsScheduler) Next(state models.ReviewState, quality int, now time.Time) models.ReviewState {
+	w := fsrsWeights
+	grade := fsrsGrade(quality)
+
+	if state.Stability == 0 || state.LastReviewedAt == nil {
+		state.Stability = w[grade-1]
+		state.Difficulty = fsrsInitialDifficulty(grade)
+	} else {
+		elapsed := now.Sub(*state.LastReviewedAt).Hours() / 24
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		recall := math.Pow(1+fsrsFactor*elapsed/state.Stability, fsrsDecay)
+
+		lastDifficulty := state.Difficulty
+		difficulty := lastDifficulty - w[6]*float64(grade-3)
+		// Difficulty reverts toward the initial difficulty of a good answer
+		state.Difficulty = clamp(w[7]*fsrsInitialDifficulty(3)+(1-w[7])*difficulty, 1, 10)
+
+		if grade == 1 {
+			state.Stability = w[11] *
+				math.Pow(lastDifficulty, -w[12]) *
+				(math.Pow(state.Stability+1, w[13]) - 1) *
+				math.Exp(w[14]*(1-recall))
+		} else {
+			hardPenalty, easyBonus := 1.0, 1.0
+			if grade == 2 {
+				hardPenalty = w[15]
+			}
+			if grade == 4 {
+				easyBonus = w[16]
+			}
+			state.Stability *= 1 + math.Exp(w[8])*
+				(11-lastDifficulty)*
+				math.Pow(state.Stability, -w[9])*
+				(math.Exp(w[10]*(1-recall))-1)*
+				hardPenalty*easyBonus
+		}
+	}
+
+	if grade == 1 {
+		state.Repetitions = 0
+		state.Lapses++
+	} else {
+		state.Repetitions++
+	}
+
+	interval := state.Stability / fsrsFactor * (math.Pow(fsrsRequestedRecall, 1/fsrsDecay) - 1)
+	state.IntervalDays = int(math.Max(1, math.Round(interval)))
+
+	return markReviewed(state, "fsrs", now)
+}
+
+// fsrsGrade maps a 0-5 quality onto the FSRS again/hard/good/easy grades
+func fsrsGrade(quality int) int {
+	switch {
+	case quality < 3:
+		return 1
+	case quality == 3:
+		return 2
+	case quality == 4:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func fsrsInitialDifficulty(grade int) float64 {
+	return clamp(fsrsWeights[4]-float64(grade-3)*fsrsWeights[5], 1, 10)
+}
+
+func clamp(value, lo, hi float64) float64 {
+	return math.Min(math.Max(value, lo), hi)
+}
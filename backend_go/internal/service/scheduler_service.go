@@ -0,0 +1,130 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+type SchedulerService struct {
+	db *sql.DB
+}
+
+func NewSchedulerService() *SchedulerService {
+	return &SchedulerService{
+		db: storage.GetDB(),
+	}
+}
+
+// SchedulersResponse lists the available schedulers and the global default
+type SchedulersResponse struct {
+	Items   []string `json:"items"`
+	Default string   `json:"default"`
+}
+
+// List returns the available schedulers and the global default
+func (s *SchedulerService) List() (*SchedulersResponse, error) {
+	scheduler, err := defaultScheduler(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SchedulersResponse{
+		Items:   SchedulerNames(),
+		Default: scheduler.Name(),
+	}, nil
+}
+
+// SetDefault changes the scheduler used by activities that do not choose one
+func (s *SchedulerService) SetDefault(name string) (*SchedulersResponse, error) {
+	if _, ok := SchedulerByName(name); !ok {
+		return nil, &ValidationError{Message: "unknown scheduler " + name}
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO settings (key, value) VALUES ('review_scheduler', ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value
+	`, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.List()
+}
+
+// Replay rebuilds the user's schedules of the given words, or of every word
+// they reviewed when wordIDs is empty, by feeding their review history
+// through the named scheduler. Returns the number of words rescheduled, or a
+// ValidationError naming every given word that does not exist.
+func (s *SchedulerService) Replay(userID int64, name string, wordIDs []int64) (int, error) {
+	scheduler, ok := SchedulerByName(name)
+	if !ok {
+		return 0, &NotFoundError{Message: "scheduler " + name + " not found"}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if len(wordIDs) == 0 {
+		wordIDs, err = reviewedWordIDs(tx, userID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var missing []FieldError
+	for i, wordID := range wordIDs {
+		exists, err := rowExists(tx, "words", wordID)
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			missing = append(missing, FieldError{Field: fmt.Sprintf("word_ids[%d]", i), Message: fmt.Sprintf("word %d does not exist", wordID)})
+		}
+	}
+	if len(missing) > 0 {
+		return 0, newFieldsError(missing)
+	}
+
+	replayed := 0
+	for _, wordID := range wordIDs {
+		state, err := replayReviewState(tx, scheduler, userID, wordID)
+		if err != nil {
+			return 0, err
+		}
+		if state != nil {
+			replayed++
+		}
+	}
+
+	return replayed, tx.Commit()
+}
+
+func reviewedWordIDs(tx *sql.Tx, userID int64) ([]int64, error) {
+	rows, err := tx.Query(`
+		SELECT DISTINCT wri.word_id
+		FROM word_review_items wri
+		JOIN study_sessions ss ON wri.study_session_id = ss.id
+		WHERE ss.user_id = ?
+		ORDER BY wri.word_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var wordIDs []int64
+	for rows.Next() {
+		var wordID int64
+		if err := rows.Scan(&wordID); err != nil {
+			return nil, err
+		}
+		wordIDs = append(wordIDs, wordID)
+	}
+
+	return wordIDs, rows.Err()
+}
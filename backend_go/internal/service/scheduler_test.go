@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+)
+
+func TestFSRSMatchesVersion4(t *testing.T) {
+	scheduler, ok := service.SchedulerByName("fsrs")
+	if !ok {
+		t.Fatal("Expected the fsrs scheduler to be registered")
+	}
+
+	// Stability, difficulty and interval of the FSRS v4 reference model
+	// with its default parameters: good, good 3 days later, then again 10
+	// days after that
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	steps := []struct {
+		days       int
+		quality    int
+		stability  float64
+		difficulty float64
+		interval   int
+	}{
+		{0, 4, 2.4, 4.93, 2},
+		{3, 4, 9.3457, 4.93, 9},
+		{10, 1, 2.7918, 6.6328, 3},
+	}
+
+	var state models.ReviewState
+	for i, step := range steps {
+		now = now.AddDate(0, 0, step.days)
+		state = scheduler.Next(state, step.quality, now)
+
+		if math.Abs(state.Stability-step.stability) > 1e-3 || math.Abs(state.Difficulty-step.difficulty) > 1e-3 || state.IntervalDays != step.interval {
+			t.Errorf("Step %d: expected stability %.4f, difficulty %.4f and a %d day interval, got %.4f, %.4f and %d days",
+				i+1, step.stability, step.difficulty, step.interval, state.Stability, state.Difficulty, state.IntervalDays)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// notBlank is the pattern of required fields: at least one non-space
+// character
+const notBlank = `\S`
+
+// JSONSchema is the subset of JSON Schema used to describe word parts.
+// Language names the language a property belongs to, so clients can tell
+// the two sides of a word apart.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Language             string                 `json:"x-language,omitempty"`
+	Order                []string               `json:"x-order,omitempty"`
+}
+
+// Validate checks value against the schema and returns an error for every
+// field that does not match. path names value in the messages.
+func (s *JSONSchema) Validate(path string, value json.RawMessage) []FieldError {
+	switch s.Type {
+	case "object":
+		return s.validateObject(path, value)
+	case "string":
+		return s.validateString(path, value)
+	}
+	return nil
+}
+
+func (s *JSONSchema) validateObject(path string, value json.RawMessage) []FieldError {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(value, &object); err != nil || object == nil {
+		return []FieldError{{Field: path, Message: "must be a JSON object"}}
+	}
+
+	var errs []FieldError
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, FieldError{Field: path + "." + name, Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				errs = append(errs, FieldError{Field: path + "." + name, Message: "is not allowed"})
+			}
+			continue
+		}
+		errs = append(errs, property.Validate(path+"."+name, object[name])...)
+	}
+
+	return errs
+}
+
+func (s *JSONSchema) validateString(path string, value json.RawMessage) []FieldError {
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return []FieldError{{Field: path, Message: "must be a string"}}
+	}
+
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err == nil && !pattern.MatchString(text) {
+			message := "must match " + s.Pattern
+			if s.Pattern == notBlank {
+				message = "must not be blank"
+			}
+			return []FieldError{{Field: path, Message: message}}
+		}
+	}
+
+	// An empty optional value is absent rather than one of the values
+	if len(s.Enum) > 0 && text != "" && !slices.Contains(s.Enum, text) {
+		return []FieldError{{Field: path, Message: "must be one of " + strings.Join(s.Enum, ", ")}}
+	}
+
+	return nil
+}
+
+// SchemaService is the registry of word part schemas. Schemas are built from
+// the language fields and named after what they describe:
+//
+//	fr        the fields of one language
+//	fr_en     the parts of a French to English word
+//	group_12  the parts of the words in group 12
+type SchemaService struct {
+	db *sql.DB
+}
+
+func NewSchemaService() *SchemaService {
+	return &SchemaService{
+		db: storage.GetDB(),
+	}
+}
+
+// Get returns the schema with the given name, or nil if there is none
+func (s *SchemaService) Get(name string) (*JSONSchema, error) {
+	if id, ok := strings.CutPrefix(name, "group_"); ok {
+		groupID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, nil
+		}
+
+		var pair LanguagePair
+		err = s.db.QueryRow(`
+			SELECT source_language, target_language FROM groups WHERE id = ?
+		`, groupID).Scan(&pair.Source, &pair.Target)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := wordSchema(s.db, pair)
+		if err != nil {
+			return nil, err
+		}
+		schema.ID = schemaID(name)
+		return schema, nil
+	}
+
+	if source, target, ok := strings.Cut(name, "_"); ok {
+		schema, err := wordSchema(s.db, LanguagePair{Source: source, Target: target})
+		if _, invalid := err.(*ValidationError); invalid {
+			return nil, nil
+		}
+		return schema, err
+	}
+
+	language, err := loadLanguage(s.db, name)
+	if err != nil || language == nil {
+		return nil, err
+	}
+
+	schema := languageSchema(language)
+	schema.Schema = jsonSchemaDraft
+	schema.ID = schemaID(name)
+	return schema, nil
+}
+
+// normalizeWordParts validates parts against the schema of the pair's
+// languages and returns the compacted JSON to store
+func normalizeWordParts(q queryer, pair LanguagePair, parts json.RawMessage) (string, error) {
+	if len(parts) == 0 {
+		return "", &ValidationError{Message: "parts is required"}
+	}
+
+	schema, err := wordSchema(q, pair)
+	if err != nil {
+		return "", err
+	}
+
+	if errs := schema.Validate("parts", parts); len(errs) > 0 {
+		return "", newFieldsError(errs)
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, parts); err != nil {
+		return "", &ValidationError{Message: "parts is not valid JSON"}
+	}
+
+	return compacted.String(), nil
+}
+
+// wordSchema returns the schema of the parts of words in a language pair:
+// the fields of both languages, nothing else
+func wordSchema(q queryer, pair LanguagePair) (*JSONSchema, error) {
+	source, target, err := requireLanguagePair(q, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &JSONSchema{
+		Schema:               jsonSchemaDraft,
+		ID:                   schemaID(pair.Source + "_" + pair.Target),
+		Title:                fmt.Sprintf("%s to %s word", source.Name, target.Name),
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: boolPtr(false),
+	}
+
+	for _, language := range []*models.Language{source, target} {
+		side := languageSchema(language)
+		for _, name := range side.Order {
+			if _, ok := schema.Properties[name]; !ok {
+				schema.Order = append(schema.Order, name)
+			}
+			schema.Properties[name] = side.Properties[name]
+		}
+		for _, name := range side.Required {
+			if !slices.Contains(schema.Required, name) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+// languageSchema returns the schema of the fields of one language
+func languageSchema(language *models.Language) *JSONSchema {
+	schema := &JSONSchema{
+		Title:                language.Name,
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: boolPtr(false),
+	}
+
+	for _, field := range language.Fields {
+		property := &JSONSchema{
+			Title:    field.Name,
+			Type:     "string",
+			Enum:     field.Values,
+			Language: language.Code,
+		}
+		if field.Required {
+			property.Pattern = notBlank
+			schema.Required = append(schema.Required, field.Name)
+		}
+
+		schema.Properties[field.Name] = property
+		schema.Order = append(schema.Order, field.Name)
+	}
+
+	return schema
+}
+
+func schemaID(name string) string {
+	return "/api/schemas/" + name
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+// SeedManifestFile is the file of a seed directory listing its seed files
+const SeedManifestFile = "manifest.json"
+
+// SeedManifest lists the seed files of a directory by what they hold.
+// Vocabulary files are SeedFiles and activity files SeedActivityFiles.
+type SeedManifest struct {
+	Vocabulary []string `json:"vocabulary"`
+	Activities []string `json:"activities"`
+}
+
+// SeedActivityFile is a seed file of study activities
+type SeedActivityFile struct {
+	Activities []BundleActivity `json:"activities"`
+}
+
+// SeedCounts counts the rows a seed inserted, updated and left as they were
+type SeedCounts struct {
+	Inserted  int
+	Updated   int
+	Unchanged int
+}
+
+func (c SeedCounts) String() string {
+	return fmt.Sprintf("%d inserted, %d updated, %d unchanged", c.Inserted, c.Updated, c.Unchanged)
+}
+
+// SeedFileReport is what seeding a file did
+type SeedFileReport struct {
+	File       string
+	Groups     SeedCounts
+	Words      SeedCounts
+	Activities SeedCounts
+}
+
+type SeedService struct {
+	db *sql.DB
+}
+
+func NewSeedService() *SeedService {
+	return &SeedService{
+		db: storage.GetDB(),
+	}
+}
+
+// Seed stores the seed files of a directory, as its manifest lists them.
+// Seeding is idempotent: groups are found by name and language pair, words
+// by language pair and the first field of each language, and activities by
+// name. Words and activities found are updated to match their seed file,
+// and words are added to the group of their file without leaving their
+// other groups. All files are seeded in one transaction, so an invalid file
+// leaves the database as it was. JSON files of the directory missing from
+// the manifest are an error, as they would otherwise be silently skipped.
+func (s *SeedService) Seed(dir string) ([]SeedFileReport, error) {
+	manifest, err := readSeedManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var reports []SeedFileReport
+	for _, name := range manifest.Vocabulary {
+		report, err := seedVocabulary(tx, filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("seed file %s: %w", name, err)
+		}
+		report.File = name
+		reports = append(reports, *report)
+	}
+	for _, name := range manifest.Activities {
+		report, err := seedActivities(tx, filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("seed file %s: %w", name, err)
+		}
+		report.File = name
+		reports = append(reports, *report)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func readSeedManifest(dir string) (*SeedManifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, SeedManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest SeedManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("%s is not a valid seed manifest: %w", SeedManifestFile, err)
+	}
+
+	listed := map[string]bool{SeedManifestFile: true}
+	for _, name := range append(append([]string{}, manifest.Vocabulary...), manifest.Activities...) {
+		if listed[name] {
+			return nil, fmt.Errorf("%s lists %s more than once", SeedManifestFile, name)
+		}
+		listed[name] = true
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var unlisted []string
+	for _, file := range files {
+		if name := filepath.Base(file); !listed[name] {
+			unlisted = append(unlisted, name)
+		}
+	}
+	if len(unlisted) > 0 {
+		sort.Strings(unlisted)
+		return nil, fmt.Errorf("seed files missing from %s: %s", SeedManifestFile, strings.Join(unlisted, ", "))
+	}
+	return &manifest, nil
+}
+
+func seedVocabulary(tx *sql.Tx, path string) (*SeedFileReport, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file SeedFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, &ValidationError{Message: "not a valid seed file: " + err.Error()}
+	}
+
+	name := strings.TrimSpace(file.GroupName)
+	if name == "" {
+		return nil, newFieldsError([]FieldError{{Field: "group_name", Message: "is required"}})
+	}
+	pair := LanguagePair{file.SourceLanguage, file.TargetLanguage}.orDefault()
+	source, target, err := requireLanguagePair(tx, pair)
+	if err != nil {
+		return nil, err
+	}
+	// Words are found by the first field of each language, such as their
+	// French and English, so their other parts and attributes can change
+	keyFields := []string{source.Fields[0].Name, target.Fields[0].Name}
+
+	report := &SeedFileReport{}
+	groupID, inserted, err := seedGroup(tx, name, pair)
+	if err != nil {
+		return nil, err
+	}
+	if inserted {
+		report.Groups.Inserted++
+	} else {
+		report.Groups.Unchanged++
+	}
+
+	for i, entry := range file.Words {
+		attributes := entry["attributes"]
+		delete(entry, "attributes")
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		parts, err := normalizeWordParts(tx, pair, raw)
+		if err != nil {
+			return nil, fmt.Errorf("word %d: %w", i+1, err)
+		}
+		wordAttributes, err := applyWordAttributes(models.WordAttributes{}, attributes)
+		if err != nil {
+			return nil, fmt.Errorf("word %d: %w", i+1, err)
+		}
+
+		status, err := seedWord(tx, groupID, pair, keyFields, parts, wordAttributes)
+		if err != nil {
+			return nil, err
+		}
+		report.Words.count(status)
+	}
+	return report, nil
+}
+
+// The outcomes of seeding a row
+const (
+	seedInserted = iota
+	seedUpdated
+	seedUnchanged
+)
+
+func (c *SeedCounts) count(status int) {
+	switch status {
+	case seedInserted:
+		c.Inserted++
+	case seedUpdated:
+		c.Updated++
+	default:
+		c.Unchanged++
+	}
+}
+
+// seedGroup returns the id of the group of a seed file, creating it if
+// needed
+func seedGroup(tx *sql.Tx, name string, pair LanguagePair) (int64, bool, error) {
+	var id int64
+	err := tx.QueryRow(`
+		SELECT id FROM groups
+		WHERE name = ? AND source_language = ? AND target_language = ?
+		ORDER BY id
+		LIMIT 1
+	`, name, pair.Source, pair.Target).Scan(&id)
+	if err != sql.ErrNoRows {
+		return id, false, err
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO groups (name, source_language, target_language) VALUES (?, ?, ?)
+	`, name, pair.Source, pair.Target)
+	if err != nil {
+		return 0, false, err
+	}
+	id, err = result.LastInsertId()
+	return id, true, err
+}
+
+// seedWord inserts or updates a word of a seed file and adds it to the
+// file's group
+func seedWord(tx *sql.Tx, groupID int64, pair LanguagePair, keyFields []string, parts string, attributes models.WordAttributes) (int, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(parts), &values); err != nil {
+		return 0, err
+	}
+
+	condition := "w.source_language = ? AND w.target_language = ?"
+	args := []interface{}{pair.Source, pair.Target}
+	for _, field := range keyFields {
+		condition += " AND COALESCE(json_extract(w.parts, ?), '') = ?"
+		args = append(args, `$."`+field+`"`, partText(values[field]))
+	}
+
+	var id int64
+	var stored string
+	var storedAttributes models.WordAttributes
+	err := tx.QueryRow(`
+		SELECT w.id, w.parts, `+wordAttributeColumns+`
+		FROM words w
+		WHERE `+condition+`
+		ORDER BY w.id
+		LIMIT 1
+	`, args...).Scan(append([]interface{}{&id, &stored}, attributeTargets(&storedAttributes)...)...)
+
+	status := seedUnchanged
+	switch {
+	case err == sql.ErrNoRows:
+		if id, err = insertWord(tx, pair, parts, attributes); err != nil {
+			return 0, err
+		}
+		status = seedInserted
+	case err != nil:
+		return 0, err
+	case wordKey(pair, stored) != wordKey(pair, parts) || storedAttributes != attributes:
+		args := append([]interface{}{parts}, attributeArgs(attributes)...)
+		_, err := tx.Exec(`
+			UPDATE words SET
+				parts = ?, part_of_speech = ?, gender = ?, plural = ?, ipa = ?, register = ?, notes = ?
+			WHERE id = ?
+		`, append(args, id)...)
+		if err != nil {
+			return 0, err
+		}
+		status = seedUpdated
+	}
+
+	result, err := tx.Exec(`
+		INSERT OR IGNORE INTO word_groups (word_id, group_id)
+		VALUES (?, ?)
+	`, id, groupID)
+	if err != nil {
+		return 0, err
+	}
+	linked, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if linked > 0 && status == seedUnchanged {
+		status = seedUpdated
+	}
+	return status, nil
+}
+
+func seedActivities(tx *sql.Tx, path string) (*SeedFileReport, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file SeedActivityFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, &ValidationError{Message: "not a valid activity seed file: " + err.Error()}
+	}
+
+	report := &SeedFileReport{}
+	for i, activity := range file.Activities {
+		seeded := models.StudyActivity{
+			Name:         activity.Name,
+			URL:          activity.URL,
+			ThumbnailURL: activity.ThumbnailURL,
+			Description:  activity.Description,
+			Scheduler:    activity.Scheduler,
+		}
+		if err := validateActivity(&seeded); err != nil {
+			return nil, fmt.Errorf("activity %d: %w", i+1, err)
+		}
+
+		status, err := seedActivity(tx, seeded)
+		if err != nil {
+			return nil, err
+		}
+		report.Activities.count(status)
+	}
+	return report, nil
+}
+
+// seedActivity inserts or updates an activity of a seed file. The scheduler
+// of an activity is only changed when the seed file names one.
+func seedActivity(tx *sql.Tx, activity models.StudyActivity) (int, error) {
+	var stored models.StudyActivity
+	err := tx.QueryRow(`
+		SELECT id, url, COALESCE(thumbnail_url, ''), COALESCE(description, ''), COALESCE(scheduler, '')
+		FROM study_activities
+		WHERE name = ?
+		ORDER BY id
+		LIMIT 1
+	`, activity.Name).Scan(&stored.ID, &stored.URL, &stored.ThumbnailURL, &stored.Description, &stored.Scheduler)
+	if err == sql.ErrNoRows {
+		_, err := tx.Exec(`
+			INSERT INTO study_activities (name, url, thumbnail_url, description, scheduler)
+			VALUES (?, ?, ?, ?, ?)
+		`, activity.Name, activity.URL, activity.ThumbnailURL, activity.Description, nullString(activity.Scheduler))
+		return seedInserted, err
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if activity.Scheduler == "" {
+		activity.Scheduler = stored.Scheduler
+	}
+	if activity.URL == stored.URL && activity.ThumbnailURL == stored.ThumbnailURL &&
+		activity.Description == stored.Description && activity.Scheduler == stored.Scheduler {
+		return seedUnchanged, nil
+	}
+
+	_, err = tx.Exec(`
+		UPDATE study_activities
+		SET url = ?, thumbnail_url = ?, description = ?, scheduler = ?
+		WHERE id = ?
+	`, activity.URL, activity.ThumbnailURL, activity.Description, nullString(activity.Scheduler), stored.ID)
+	return seedUpdated, err
+}
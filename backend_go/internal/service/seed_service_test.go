@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/testutil"
+)
+
+func setupSeed(t *testing.T) (*service.SeedService, *sql.DB) {
+	db := testutil.SetupTestDB(t)
+	testutil.SetTestDB(db)
+	return service.NewSeedService(), db
+}
+
+func writeSeeds(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func total(reports []service.SeedFileReport) (groups, words, activities service.SeedCounts) {
+	for _, report := range reports {
+		groups.Inserted += report.Groups.Inserted
+		groups.Updated += report.Groups.Updated
+		groups.Unchanged += report.Groups.Unchanged
+		words.Inserted += report.Words.Inserted
+		words.Updated += report.Words.Updated
+		words.Unchanged += report.Words.Unchanged
+		activities.Inserted += report.Activities.Inserted
+		activities.Updated += report.Activities.Updated
+		activities.Unchanged += report.Activities.Unchanged
+	}
+	return groups, words, activities
+}
+
+func TestSeedIsIdempotent(t *testing.T) {
+	seeds, db := setupSeed(t)
+	defer db.Close()
+
+	reports, err := seeds.Seed("../../db/seeds")
+	if err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+	groups, words, activities := total(reports)
+	if groups.Inserted != 3 || words.Inserted == 0 || activities.Inserted != 3 {
+		t.Fatalf("Expected the seed files to be inserted, got groups %s, words %s, activities %s", groups, words, activities)
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM groups WHERE name = ''"); n != 0 {
+		t.Errorf("Expected no unnamed group, got %d", n)
+	}
+	wordCount := testutil.Count(t, db, "SELECT COUNT(*) FROM words")
+
+	reports, err = seeds.Seed("../../db/seeds")
+	if err != nil {
+		t.Fatalf("Failed to seed again: %v", err)
+	}
+	groups, words, activities = total(reports)
+	if groups.Unchanged != 3 || words.Inserted+words.Updated != 0 || activities.Unchanged != 3 {
+		t.Errorf("Expected seeding again to change nothing, got groups %s, words %s, activities %s", groups, words, activities)
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM words"); n != wordCount {
+		t.Errorf("Expected %d words after seeding again, got %d", wordCount, n)
+	}
+}
+
+func TestSeedUpdates(t *testing.T) {
+	seeds, db := setupSeed(t)
+	defer db.Close()
+
+	manifest := `{"vocabulary": ["animals.json", "pets.json"], "activities": ["activities.json"]}`
+	dir := writeSeeds(t, map[string]string{
+		"manifest.json":   manifest,
+		"animals.json":    `{"group_name": "Animals", "words": [{"french": "chat", "english": "cat"}, {"french": "chien", "english": "dog"}]}`,
+		"pets.json":       `{"group_name": "Pets", "words": []}`,
+		"activities.json": `{"activities": [{"name": "Quiz", "url": "http://localhost:3000/quiz"}]}`,
+	})
+	if _, err := seeds.Seed(dir); err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+
+	dir = writeSeeds(t, map[string]string{
+		"manifest.json":   manifest,
+		"animals.json":    `{"group_name": "Animals", "words": [{"french": "chat", "english": "cat", "attributes": {"gender": "masculine"}}, {"french": "chien", "english": "dog"}, {"french": "oiseau", "english": "bird"}]}`,
+		"pets.json":       `{"group_name": "Pets", "words": [{"french": "chien", "english": "dog"}]}`,
+		"activities.json": `{"activities": [{"name": "Quiz", "url": "http://localhost:3000/quiz", "description": "Test yourself"}]}`,
+	})
+	reports, err := seeds.Seed(dir)
+	if err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+	groups, words, activities := total(reports)
+	if want := (service.SeedCounts{Unchanged: 2}); groups != want {
+		t.Errorf("Expected groups %s, got %s", want, groups)
+	}
+	// chat gains a gender, chien is unchanged in Animals and joins Pets and
+	// oiseau is new
+	if want := (service.SeedCounts{Inserted: 1, Updated: 2, Unchanged: 1}); words != want {
+		t.Errorf("Expected words %s, got %s", want, words)
+	}
+	if want := (service.SeedCounts{Updated: 1}); activities != want {
+		t.Errorf("Expected activities %s, got %s", want, activities)
+	}
+
+	for _, query := range []string{
+		"SELECT COUNT(*) FROM words WHERE gender = 'masculine'",
+		"SELECT COUNT(*) FROM study_activities WHERE description = 'Test yourself'",
+		"SELECT COUNT(*) FROM groups WHERE name = 'Pets' AND words_count = 1",
+	} {
+		if testutil.Count(t, db, query) != 1 {
+			t.Errorf("Expected a match for %s", query)
+		}
+	}
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM words"); n != 3 {
+		t.Errorf("Expected 3 words, got %d", n)
+	}
+}
+
+func TestSeedErrors(t *testing.T) {
+	seeds, db := setupSeed(t)
+	defer db.Close()
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			"unlisted file",
+			map[string]string{"manifest.json": `{"vocabulary": []}`, "extra.json": `{}`},
+			"extra.json",
+		},
+		{
+			"missing manifest",
+			map[string]string{"animals.json": `{}`},
+			"manifest.json",
+		},
+		{
+			"invalid word",
+			map[string]string{
+				"manifest.json": `{"vocabulary": ["a.json", "b.json"]}`,
+				"a.json":        `{"group_name": "Animals", "words": [{"french": "chat", "english": "cat"}]}`,
+				"b.json":        `{"group_name": "Birds", "words": [{"english": "bird"}]}`,
+			},
+			"b.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := seeds.Seed(writeSeeds(t, tt.files))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Expected an error naming %s, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if n := testutil.Count(t, db, "SELECT COUNT(*) FROM words"); n != 0 {
+		t.Errorf("Expected failed seeds to store nothing, got %d words", n)
+	}
+}
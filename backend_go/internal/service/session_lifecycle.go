@@ -0,0 +1,258 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Study session statuses. Reviews are only accepted while a session is
+// active; completed and abandoned sessions can no longer change.
+const (
+	SessionActive    = "active"
+	SessionPaused    = "paused"
+	SessionCompleted = "completed"
+	SessionAbandoned = "abandoned"
+)
+
+// sessionState is the lifecycle part of a study session row
+type sessionState struct {
+	ID             int64
+	Status         string
+	ActiveSeconds  int64
+	ResumedAt      sql.NullTime
+	LastActivityAt sql.NullTime
+	EndedAt        sql.NullTime
+}
+
+// activeSeconds returns the active time of the session up to now
+func (st *sessionState) activeSeconds(now time.Time) int64 {
+	return st.ActiveSeconds + st.runningSeconds(now)
+}
+
+// runningSeconds returns the length of the current active stretch at until
+func (st *sessionState) runningSeconds(until time.Time) int64 {
+	if st.Status != SessionActive || !st.ResumedAt.Valid {
+		return 0
+	}
+
+	seconds := int64(until.Sub(st.ResumedAt.Time) / time.Second)
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+// stop folds the current active stretch into the accumulated duration
+func (st *sessionState) stop(at time.Time) {
+	st.ActiveSeconds += st.runningSeconds(at)
+	st.ResumedAt = sql.NullTime{}
+}
+
+// loadSessionState returns the lifecycle state of one of the user's study
+// sessions, or nil if the user has no session with that id
+func loadSessionState(q queryer, userID, id int64) (*sessionState, error) {
+	var st sessionState
+	err := q.QueryRow(`
+		SELECT id, status, active_seconds, resumed_at, last_activity_at, ended_at
+		FROM study_sessions
+		WHERE id = ? AND user_id = ?
+	`, id, userID).Scan(
+		&st.ID,
+		&st.Status,
+		&st.ActiveSeconds,
+		&st.ResumedAt,
+		&st.LastActivityAt,
+		&st.EndedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &st, nil
+}
+
+func saveSessionState(tx *sql.Tx, st *sessionState) error {
+	_, err := tx.Exec(`
+		UPDATE study_sessions
+		SET status = ?, active_seconds = ?, resumed_at = ?, last_activity_at = ?, ended_at = ?
+		WHERE id = ?
+	`, st.Status, st.ActiveSeconds, st.ResumedAt, st.LastActivityAt, st.EndedAt, st.ID)
+	return err
+}
+
+// requireActive returns a ConflictError unless reviews may be recorded in
+// the session
+func (st *sessionState) requireActive() error {
+	if st.Status != SessionActive {
+		return &ConflictError{Message: fmt.Sprintf("study session is %s", st.Status)}
+	}
+	return nil
+}
+
+// End completes an active or paused study session
+func (s *SessionService) End(userID, id int64) (*SessionResponse, error) {
+	return s.transition(userID, id, func(st *sessionState, now time.Time) error {
+		if st.Status != SessionActive && st.Status != SessionPaused {
+			return &ConflictError{Message: fmt.Sprintf("study session is already %s", st.Status)}
+		}
+
+		st.stop(now)
+		st.Status = SessionCompleted
+		st.EndedAt = sql.NullTime{Time: now, Valid: true}
+		return nil
+	})
+}
+
+// Pause stops the clock of an active study session
+func (s *SessionService) Pause(userID, id int64) (*SessionResponse, error) {
+	return s.transition(userID, id, func(st *sessionState, now time.Time) error {
+		if st.Status != SessionActive {
+			return &ConflictError{Message: fmt.Sprintf("only active study sessions can be paused, session is %s", st.Status)}
+		}
+
+		st.stop(now)
+		st.Status = SessionPaused
+		return nil
+	})
+}
+
+// Resume restarts the clock of a paused study session
+func (s *SessionService) Resume(userID, id int64) (*SessionResponse, error) {
+	return s.transition(userID, id, func(st *sessionState, now time.Time) error {
+		if st.Status != SessionPaused {
+			return &ConflictError{Message: fmt.Sprintf("only paused study sessions can be resumed, session is %s", st.Status)}
+		}
+
+		st.Status = SessionActive
+		st.ResumedAt = sql.NullTime{Time: now, Valid: true}
+		return nil
+	})
+}
+
+// transition applies change to the lifecycle state of one of the user's
+// sessions and returns the updated session
+func (s *SessionService) transition(userID, id int64, change func(st *sessionState, now time.Time) error) (*SessionResponse, error) {
+	now := time.Now().UTC()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	st, err := loadSessionState(tx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, notFound("study session", id)
+	}
+
+	if err := change(st, now); err != nil {
+		return nil, err
+	}
+	st.LastActivityAt = sql.NullTime{Time: now, Valid: true}
+
+	if err := saveSessionState(tx, st); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.Get(userID, id)
+}
+
+// SweepStale marks sessions abandoned when nothing has happened in them for
+// longer than idleTimeout, or pausedTimeout for paused sessions. Active time
+// is only counted up to the last activity. Returns the number of sessions
+// abandoned.
+func (s *SessionService) SweepStale(idleTimeout, pausedTimeout time.Duration) (int, error) {
+	now := time.Now().UTC()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, status, active_seconds, resumed_at, last_activity_at, ended_at
+		FROM study_sessions
+		WHERE status IN (?, ?)
+	`, SessionActive, SessionPaused)
+	if err != nil {
+		return 0, err
+	}
+
+	var stale []*sessionState
+	for rows.Next() {
+		var st sessionState
+		err := rows.Scan(
+			&st.ID,
+			&st.Status,
+			&st.ActiveSeconds,
+			&st.ResumedAt,
+			&st.LastActivityAt,
+			&st.EndedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		timeout := idleTimeout
+		if st.Status == SessionPaused {
+			timeout = pausedTimeout
+		}
+		if st.LastActivityAt.Valid && now.Sub(st.LastActivityAt.Time) > timeout {
+			stale = append(stale, &st)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, st := range stale {
+		st.stop(st.LastActivityAt.Time)
+		st.Status = SessionAbandoned
+		st.EndedAt = st.LastActivityAt
+		if err := saveSessionState(tx, st); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(stale), nil
+}
+
+// RunSweeper calls SweepStale every interval until stop is closed
+func (s *SessionService) RunSweeper(interval, idleTimeout, pausedTimeout time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			abandoned, err := s.SweepStale(idleTimeout, pausedTimeout)
+			if err != nil {
+				log.Printf("Failed to sweep stale study sessions: %v", err)
+			} else if abandoned > 0 {
+				log.Printf("Marked %d stale study sessions as abandoned", abandoned)
+			}
+		}
+	}
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"database/sql"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
@@ -18,63 +20,124 @@ func NewSessionService() *SessionService {
 	}
 }
 
-// List returns a paginated list of study sessions
-func (s *SessionService) List(page, perPage int) ([]SessionResponse, int, error) {
+// List returns a paginated list of the user's study sessions of groups in a
+// language pair
+func (s *SessionService) List(userID int64, pair LanguagePair, options ListOptions, page, perPage int) ([]SessionResponse, int, error) {
 	offset := (page - 1) * perPage
-
-	var total int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM study_sessions").Scan(&total)
+	list, err := options.query(s.db, sessionListSpec)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	rows, err := s.db.Query(`
-		SELECT 
-			ss.id,
-			sa.name as activity_name,
-			g.name as group_name,
-			strftime('%Y-%m-%d %H:%M:%S', ss.created_at) as start_time,
-			strftime('%Y-%m-%d %H:%M:%S', COALESCE(MAX(wri.created_at), ss.created_at)) as end_time,
-			COUNT(wri.id) as review_items_count
+	languages, languageArgs := pair.filter("g")
+	languages += " AND " + list.condition
+	args := append(append([]interface{}{userID}, languageArgs...), list.args...)
+
+	var total int
+	err = s.db.QueryRow(`
+		SELECT COUNT(*)
 		FROM study_sessions ss
-		JOIN study_activities sa ON ss.study_activity_id = sa.id
 		JOIN groups g ON ss.group_id = g.id
-		LEFT JOIN word_review_items wri ON ss.id = wri.study_session_id
+		WHERE ss.user_id = ? AND `+languages, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(sessionResponseQuery+`
+		WHERE ss.user_id = ? AND `+languages+`
 		GROUP BY ss.id
-		ORDER BY ss.created_at DESC
+		ORDER BY `+list.orderBy+`
 		LIMIT ? OFFSET ?
-	`, perPage, offset)
+	`, append(args, perPage, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
+	now := time.Now().UTC()
 	var sessions []SessionResponse
 	for rows.Next() {
-		var session SessionResponse
-		err := rows.Scan(
-			&session.ID,
-			&session.ActivityName,
-			&session.GroupName,
-			&session.StartTime,
-			&session.EndTime,
-			&session.ReviewItemsCount,
-		)
+		session, err := scanSessionResponse(rows, now)
 		if err != nil {
 			return nil, 0, err
 		}
-		sessions = append(sessions, session)
+		sessions = append(sessions, *session)
 	}
 
 	return sessions, total, nil
 }
 
-// Create creates a new study session
-func (s *SessionService) Create(groupID, studyActivityID int64) (*SessionResponse, error) {
+// ListAfter returns up to limit of the user's study sessions of groups in a
+// language pair, newest first, starting after the cursor of a previous
+// page. It also returns the cursor of the next page, or "" if there is
+// none. Sessions are filtered by the options but can't be sorted.
+func (s *SessionService) ListAfter(userID int64, pair LanguagePair, options ListOptions, after string, limit int) ([]SessionResponse, string, error) {
+	if options.SortBy != "" || options.Order != "" {
+		return nil, "", &ValidationError{Message: "sort_by and order can't be combined with cursor"}
+	}
+	list, err := options.query(s.db, sessionListSpec)
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := decodeCursor(after)
+	if err != nil {
+		return nil, "", err
+	}
+
+	languages, languageArgs := pair.filter("g")
+	position, positionArgs := c.condition("ss.created_at", "ss.id")
+	args := append([]interface{}{userID}, languageArgs...)
+	args = append(append(args, list.args...), positionArgs...)
+
+	// Fetch one more session than asked for to know if there is a next page
+	rows, err := s.db.Query(sessionResponseQuery+`
+		WHERE ss.user_id = ? AND `+languages+` AND `+list.condition+` AND `+position+`
+		GROUP BY ss.id
+		ORDER BY `+cursorOrder("ss.created_at", "ss.id")+`
+		LIMIT ?
+	`, append(args, limit+1)...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	now := time.Now().UTC()
+	var sessions []SessionResponse
+	for rows.Next() {
+		session, err := scanSessionResponse(rows, now)
+		if err != nil {
+			return nil, "", err
+		}
+		sessions = append(sessions, *session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(sessions) <= limit {
+		return sessions, "", nil
+	}
+	sessions = sessions[:limit]
+	next, err := nextCursor(s.db, "study_sessions", sessions[limit-1].ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return sessions, next, nil
+}
+
+// Create starts a new study session for the user
+func (s *SessionService) Create(userID, groupID, studyActivityID int64) (*SessionResponse, error) {
+	if err := requireReference(s.db, "groups", "group", groupID); err != nil {
+		return nil, err
+	}
+	if err := requireReference(s.db, "study_activities", "study activity", studyActivityID); err != nil {
+		return nil, err
+	}
+
 	result, err := s.db.Exec(`
-		INSERT INTO study_sessions (group_id, study_activity_id, created_at)
-		VALUES (?, ?, ?)
-	`, groupID, studyActivityID, time.Now())
+		INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at)
+		VALUES (?, ?, ?, ?)
+	`, userID, groupID, studyActivityID, time.Now().UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -84,45 +147,83 @@ func (s *SessionService) Create(groupID, studyActivityID int64) (*SessionRespons
 		return nil, err
 	}
 
-	return s.Get(id)
+	return s.Get(userID, id)
 }
 
-// Add this struct for the session response
+// SessionResponse is a study session with its lifecycle status. EndTime is
+// nil until the session is completed or abandoned, and DurationSeconds only
+// counts time spent active. ActivityName is empty for sessions imported
+// without an activity, such as Anki reviews.
 type SessionResponse struct {
-	ID               int64  `json:"id"`
-	ActivityName     string `json:"activity_name"`
-	GroupName        string `json:"group_name"`
-	StartTime        string `json:"start_time"`
-	EndTime          string `json:"end_time"`
-	ReviewItemsCount int    `json:"review_items_count"`
+	ID               int64   `json:"id"`
+	ActivityName     string  `json:"activity_name"`
+	GroupName        string  `json:"group_name"`
+	Status           string  `json:"status"`
+	StartTime        string  `json:"start_time"`
+	EndTime          *string `json:"end_time"`
+	DurationSeconds  int64   `json:"duration_seconds"`
+	ReviewItemsCount int     `json:"review_items_count"`
 }
 
-// Update the Get method to include these fields
-func (s *SessionService) Get(id int64) (*SessionResponse, error) {
+const sessionResponseQuery = `
+	SELECT
+		ss.id,
+		COALESCE(sa.name, '') as activity_name,
+		g.name as group_name,
+		ss.status,
+		ss.active_seconds,
+		ss.resumed_at,
+		strftime('%Y-%m-%d %H:%M:%S', ss.created_at) as start_time,
+		strftime('%Y-%m-%d %H:%M:%S', ss.ended_at) as end_time,
+		COUNT(wri.id) as review_items_count
+	FROM study_sessions ss
+	LEFT JOIN study_activities sa ON ss.study_activity_id = sa.id
+	JOIN groups g ON ss.group_id = g.id
+	LEFT JOIN word_review_items wri ON ss.id = wri.study_session_id
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSessionResponse(row rowScanner, now time.Time) (*SessionResponse, error) {
 	var session SessionResponse
-	err := s.db.QueryRow(`
-		SELECT 
-			ss.id,
-			sa.name as activity_name,
-			g.name as group_name,
-			strftime('%Y-%m-%d %H:%M:%S', ss.created_at) as start_time,
-			strftime('%Y-%m-%d %H:%M:%S', COALESCE(MAX(wri.created_at), ss.created_at)) as end_time,
-			COUNT(wri.id) as review_items_count
-		FROM study_sessions ss
-		JOIN study_activities sa ON ss.study_activity_id = sa.id
-		JOIN groups g ON ss.group_id = g.id
-		LEFT JOIN word_review_items wri ON ss.id = wri.study_session_id
-		WHERE ss.id = ?
-		GROUP BY ss.id
-	`, id).Scan(
+	var st sessionState
+	var endTime sql.NullString
+
+	err := row.Scan(
 		&session.ID,
 		&session.ActivityName,
 		&session.GroupName,
+		&st.Status,
+		&st.ActiveSeconds,
+		&st.ResumedAt,
 		&session.StartTime,
-		&session.EndTime,
+		&endTime,
 		&session.ReviewItemsCount,
 	)
+	if err != nil {
+		return nil, err
+	}
 
+	session.Status = st.Status
+	session.DurationSeconds = st.activeSeconds(now)
+	if endTime.Valid {
+		session.EndTime = &endTime.String
+	}
+
+	return &session, nil
+}
+
+// Get returns one of the user's study sessions, or nil if the user has no
+// session with that id
+func (s *SessionService) Get(userID, id int64) (*SessionResponse, error) {
+	row := s.db.QueryRow(sessionResponseQuery+`
+		WHERE ss.id = ? AND ss.user_id = ?
+		GROUP BY ss.id
+	`, id, userID)
+
+	session, err := scanSessionResponse(row, time.Now().UTC())
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -130,48 +231,271 @@ func (s *SessionService) Get(id int64) (*SessionResponse, error) {
 		return nil, err
 	}
 
-	return &session, nil
+	return session, nil
 }
 
-// ReviewWord records a word review in a study session
-func (s *SessionService) ReviewWord(sessionID, wordID int64, correct bool) (*models.WordReviewItem, error) {
-	result, err := s.db.Exec(`
-		INSERT INTO word_review_items (word_id, study_session_id, correct, created_at)
-		VALUES (?, ?, ?, ?)
-	`, wordID, sessionID, correct, time.Now())
+// ReviewWord records a graded word review in a study session and advances
+// the word's spaced repetition schedule in the same transaction. Returns a
+// ConflictError if the session is not active.
+func (s *SessionService) ReviewWord(userID, sessionID, wordID int64, input ReviewInput) (*models.WordReviewItem, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	tx, err := s.db.Begin()
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	id, err := result.LastInsertId()
+	session, err := loadSessionState(tx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, notFound("study session", sessionID)
+	}
+	if err := session.requireActive(); err != nil {
+		return nil, err
+	}
+	if err := requireExists(tx, "words", "word", wordID); err != nil {
+		return nil, err
+	}
+
+	scheduler, err := sessionScheduler(tx, sessionID)
 	if err != nil {
 		return nil, err
 	}
 
-	var review models.WordReviewItem
-	err = s.db.QueryRow(`
-		SELECT id, word_id, study_session_id, correct, created_at
+	id, _, err := recordReview(tx, scheduler, userID, sessionID, wordID, "", input, now)
+	if err != nil {
+		return nil, err
+	}
+
+	session.LastActivityAt = sql.NullTime{Time: now, Valid: true}
+	if err := saveSessionState(tx, session); err != nil {
+		return nil, err
+	}
+
+	review, err := getReviewItem(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// ReviewBatch records several reviews of a study session in one transaction.
+// Reviews whose client id was already recorded for the session are left
+// untouched and reported as duplicates, so a batch can safely be resent.
+// Returns a ConflictError if the session is not active and the batch contains
+// new reviews.
+func (s *SessionService) ReviewBatch(userID, sessionID int64, reviews []BatchReview) (*BatchReviewResponse, error) {
+	now := time.Now().UTC()
+	if err := validateBatch(reviews, now); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := loadSessionState(tx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, notFound("study session", sessionID)
+	}
+
+	for i, review := range reviews {
+		if err := requireReference(tx, "words", "word", review.WordID); err != nil {
+			return nil, &ValidationError{Message: fmt.Sprintf("reviews[%d]: %v", i, err)}
+		}
+	}
+
+	scheduler, err := sessionScheduler(tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schedule in the order the answers were given
+	ordered := make([]BatchReview, len(reviews))
+	copy(ordered, reviews)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].reviewedAt(now).Before(ordered[j].reviewedAt(now))
+	})
+
+	response := &BatchReviewResponse{StudySessionID: sessionID}
+	for _, review := range ordered {
+		_, created, err := recordReview(tx, scheduler, userID, sessionID, review.WordID, review.ClientID, review.Input, review.reviewedAt(now))
+		if err != nil {
+			return nil, err
+		}
+
+		if !created {
+			response.Duplicates++
+			continue
+		}
+		if err := session.requireActive(); err != nil {
+			return nil, err
+		}
+		response.Created++
+	}
+
+	if response.Created > 0 {
+		session.LastActivityAt = sql.NullTime{Time: now, Valid: true}
+		if err := saveSessionState(tx, session); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, review := range reviews {
+		item, err := getReviewItemByClientID(tx, sessionID, review.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		response.Items = append(response.Items, *item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// recordReview stores a review and advances the user's schedule of the
+// word. A review with a client id that the session already has is skipped
+// and reported as not created.
+func recordReview(tx *sql.Tx, scheduler Scheduler, userID, sessionID, wordID int64, clientID string, input ReviewInput, reviewedAt time.Time) (int64, bool, error) {
+	result, err := tx.Exec(`
+		INSERT INTO word_review_items (
+			word_id, study_session_id, client_id, correct, quality, answer, response_time_ms, direction,
+			tense, person, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (study_session_id, client_id) DO NOTHING
+	`,
+		wordID,
+		sessionID,
+		nullString(clientID),
+		input.Correct(),
+		input.Quality,
+		nullString(input.Answer),
+		input.ResponseTimeMs,
+		nullString(input.Direction),
+		nullString(input.Tense),
+		nullString(input.Person),
+		reviewedAt,
+	)
+	if err != nil {
+		return 0, false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return 0, false, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, false, err
+	}
+
+	if _, err := scheduleReview(tx, scheduler, userID, wordID, input.Quality, reviewedAt); err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
+
+const reviewItemColumns = `
+	id,
+	word_id,
+	study_session_id,
+	client_id,
+	correct,
+	COALESCE(quality, CASE WHEN correct THEN 4 ELSE 1 END),
+	answer,
+	response_time_ms,
+	direction,
+	tense,
+	person,
+	created_at
+`
+
+func getReviewItem(q queryer, id int64) (*models.WordReviewItem, error) {
+	return scanReviewItem(q.QueryRow(`
+		SELECT `+reviewItemColumns+`
 		FROM word_review_items
 		WHERE id = ?
-	`, id).Scan(&review.ID, &review.WordID, &review.StudySessionID, &review.Correct, &review.CreatedAt)
+	`, id))
+}
+
+func getReviewItemByClientID(q queryer, sessionID int64, clientID string) (*models.WordReviewItem, error) {
+	return scanReviewItem(q.QueryRow(`
+		SELECT `+reviewItemColumns+`
+		FROM word_review_items
+		WHERE study_session_id = ? AND client_id = ?
+	`, sessionID, clientID))
+}
+
+func scanReviewItem(row rowScanner) (*models.WordReviewItem, error) {
+	var review models.WordReviewItem
+	var clientID, answer, direction, tense, person sql.NullString
+	err := row.Scan(
+		&review.ID,
+		&review.WordID,
+		&review.StudySessionID,
+		&clientID,
+		&review.Correct,
+		&review.Quality,
+		&answer,
+		&review.ResponseTimeMs,
+		&direction,
+		&tense,
+		&person,
+		&review.CreatedAt,
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	review.ClientID = clientID.String
+	review.Answer = answer.String
+	review.Direction = direction.String
+	review.Tense = tense.String
+	review.Person = person.String
+
 	return &review, nil
 }
 
-// ListWords returns words reviewed in a study session
-func (s *SessionService) ListWords(sessionID int64, page, perPage int) ([]models.Word, int, error) {
+// ListWords returns words reviewed in one of the user's study sessions
+func (s *SessionService) ListWords(userID, sessionID int64, options ListOptions, page, perPage int) ([]models.Word, int, error) {
 	offset := (page - 1) * perPage
 
+	list, err := options.query(s.db, memberWordListSpec)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	var total int
-	err := s.db.QueryRow(`
+	err = s.db.QueryRow(`
 		SELECT COUNT(DISTINCT w.id)
 		FROM words w
 		JOIN word_review_items wri ON w.id = wri.word_id
-		WHERE wri.study_session_id = ?
-	`, sessionID).Scan(&total)
+		JOIN study_sessions ss ON wri.study_session_id = ss.id
+		WHERE wri.study_session_id = ? AND ss.user_id = ?
+	`, sessionID, userID).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -180,9 +504,11 @@ func (s *SessionService) ListWords(sessionID int64, page, perPage int) ([]models
 		SELECT DISTINCT w.id, json(w.parts) as parts
 		FROM words w
 		JOIN word_review_items wri ON w.id = wri.word_id
-		WHERE wri.study_session_id = ?
+		JOIN study_sessions ss ON wri.study_session_id = ss.id
+		WHERE wri.study_session_id = ? AND ss.user_id = ?
+		ORDER BY `+list.orderBy+`
 		LIMIT ? OFFSET ?
-	`, sessionID, perPage, offset)
+	`, sessionID, userID, perPage, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+)
+
+const (
+	defaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+// DueWordResponse is a word that should be studied now together with its
+// current schedule. ReviewState is nil for words that were never reviewed.
+type DueWordResponse struct {
+	ID             int64                 `json:"id"`
+	SourceLanguage string                `json:"source_language"`
+	TargetLanguage string                `json:"target_language"`
+	Parts          json.RawMessage       `json:"parts"`
+	Attributes     models.WordAttributes `json:"attributes"`
+	ReviewState    *models.ReviewState   `json:"review_state"`
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+const reviewStateColumns = `
+	word_id, scheduler, ease_factor, interval_days, repetitions, lapses,
+	box, stability, difficulty, due_at, last_reviewed_at
+`
+
+// newReviewState returns the schedule of a word that has never been reviewed
+func newReviewState(wordID int64, now time.Time) models.ReviewState {
+	return models.ReviewState{
+		WordID:     wordID,
+		EaseFactor: defaultEaseFactor,
+		DueAt:      now,
+	}
+}
+
+// defaultScheduler returns the globally configured scheduler
+func defaultScheduler(q queryer) (Scheduler, error) {
+	var name string
+	err := q.QueryRow("SELECT value FROM settings WHERE key = 'review_scheduler'").Scan(&name)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if scheduler, ok := SchedulerByName(name); ok {
+		return scheduler, nil
+	}
+	return schedulers[DefaultSchedulerName], nil
+}
+
+// sessionScheduler returns the scheduler chosen by the session's study
+// activity, falling back to the global default
+func sessionScheduler(q queryer, sessionID int64) (Scheduler, error) {
+	var name sql.NullString
+	err := q.QueryRow(`
+		SELECT sa.scheduler
+		FROM study_sessions ss
+		JOIN study_activities sa ON ss.study_activity_id = sa.id
+		WHERE ss.id = ?
+	`, sessionID).Scan(&name)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if scheduler, ok := SchedulerByName(name.String); ok {
+		return scheduler, nil
+	}
+	return defaultScheduler(q)
+}
+
+// scheduleReview advances the user's stored schedule of a word inside tx.
+// If the word was scheduled by a different algorithm, or the review happened
+// before the last one that was scheduled, the user's whole review history of
+// the word, which must already include this review, is replayed instead.
+func scheduleReview(tx *sql.Tx, scheduler Scheduler, userID, wordID int64, quality int, now time.Time) (*models.ReviewState, error) {
+	state, err := loadReviewState(tx, userID, wordID)
+	if err != nil {
+		return nil, err
+	}
+
+	if state != nil && state.Scheduler != scheduler.Name() {
+		return replayReviewState(tx, scheduler, userID, wordID)
+	}
+	if state != nil && state.LastReviewedAt != nil && now.Before(*state.LastReviewedAt) {
+		return replayReviewState(tx, scheduler, userID, wordID)
+	}
+
+	if state == nil {
+		initial := newReviewState(wordID, now)
+		state = &initial
+	}
+
+	next := scheduler.Next(*state, quality, now)
+	if err := saveReviewState(tx, userID, next); err != nil {
+		return nil, err
+	}
+
+	return &next, nil
+}
+
+// replayReviewState rebuilds a user's schedule of a word by feeding their
+// full review history of it through scheduler. Returns nil if the user never
+// reviewed the word.
+func replayReviewState(tx *sql.Tx, scheduler Scheduler, userID, wordID int64) (*models.ReviewState, error) {
+	rows, err := tx.Query(`
+		SELECT COALESCE(wri.quality, CASE WHEN wri.correct THEN 4 ELSE 1 END), wri.created_at
+		FROM word_review_items wri
+		JOIN study_sessions ss ON wri.study_session_id = ss.id
+		WHERE wri.word_id = ? AND ss.user_id = ?
+		ORDER BY wri.created_at, wri.id
+	`, wordID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var state *models.ReviewState
+	for rows.Next() {
+		var quality int
+		var reviewedAt time.Time
+		if err := rows.Scan(&quality, &reviewedAt); err != nil {
+			return nil, err
+		}
+
+		if state == nil {
+			initial := newReviewState(wordID, reviewedAt)
+			state = &initial
+		}
+		next := scheduler.Next(*state, quality, reviewedAt)
+		state = &next
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if state == nil {
+		_, err := tx.Exec("DELETE FROM word_review_states WHERE user_id = ? AND word_id = ?", userID, wordID)
+		return nil, err
+	}
+
+	if err := saveReviewState(tx, userID, *state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func loadReviewState(q queryer, userID, wordID int64) (*models.ReviewState, error) {
+	var state models.ReviewState
+	err := q.QueryRow(`
+		SELECT `+reviewStateColumns+`
+		FROM word_review_states
+		WHERE user_id = ? AND word_id = ?
+	`, userID, wordID).Scan(
+		&state.WordID,
+		&state.Scheduler,
+		&state.EaseFactor,
+		&state.IntervalDays,
+		&state.Repetitions,
+		&state.Lapses,
+		&state.Box,
+		&state.Stability,
+		&state.Difficulty,
+		&state.DueAt,
+		&state.LastReviewedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func saveReviewState(tx *sql.Tx, userID int64, state models.ReviewState) error {
+	_, err := tx.Exec(`
+		INSERT INTO word_review_states (user_id, `+reviewStateColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, word_id) DO UPDATE SET
+			scheduler = excluded.scheduler,
+			ease_factor = excluded.ease_factor,
+			interval_days = excluded.interval_days,
+			repetitions = excluded.repetitions,
+			lapses = excluded.lapses,
+			box = excluded.box,
+			stability = excluded.stability,
+			difficulty = excluded.difficulty,
+			due_at = excluded.due_at,
+			last_reviewed_at = excluded.last_reviewed_at
+	`,
+		userID,
+		state.WordID,
+		state.Scheduler,
+		state.EaseFactor,
+		state.IntervalDays,
+		state.Repetitions,
+		state.Lapses,
+		state.Box,
+		state.Stability,
+		state.Difficulty,
+		srsTime(state.DueAt),
+		srsTimePtr(state.LastReviewedAt),
+	)
+	return err
+}
+
+// listDueWords returns words whose review by the user is due at now, most
+// overdue first, optionally followed by words the user never reviewed. A
+// groupID of 0 searches all words of the language pair.
+func listDueWords(db *sql.DB, userID, groupID int64, pair LanguagePair, includeNew bool, now time.Time, page, perPage int) ([]DueWordResponse, int, error) {
+	offset := (page - 1) * perPage
+	languages, languageArgs := pair.filter("w")
+
+	from := `
+		FROM words w
+		LEFT JOIN word_review_states rs ON rs.word_id = w.id AND rs.user_id = ?
+		WHERE (rs.due_at <= ? OR (? AND rs.word_id IS NULL))
+		AND (? = 0 OR w.id IN (SELECT word_id FROM word_groups WHERE group_id = ?))
+		AND ` + languages + `
+	`
+	args := append([]interface{}{userID, srsTime(now), includeNew, groupID, groupID}, languageArgs...)
+
+	var total int
+	err := db.QueryRow("SELECT COUNT(*) "+from, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.Query(`
+		SELECT
+			w.id,
+			w.source_language,
+			w.target_language,
+			json(w.parts) as parts,
+			`+wordAttributeColumns+`,
+			rs.word_id IS NOT NULL,
+			COALESCE(rs.scheduler, ''),
+			COALESCE(rs.ease_factor, 0),
+			COALESCE(rs.interval_days, 0),
+			COALESCE(rs.repetitions, 0),
+			COALESCE(rs.lapses, 0),
+			COALESCE(rs.box, 0),
+			COALESCE(rs.stability, 0),
+			COALESCE(rs.difficulty, 0),
+			rs.due_at,
+			rs.last_reviewed_at
+		`+from+`
+		ORDER BY rs.word_id IS NULL, rs.due_at, w.id
+		LIMIT ? OFFSET ?
+	`, append(args, perPage, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var words []DueWordResponse
+	for rows.Next() {
+		var word DueWordResponse
+		var parts []byte
+		var scheduled bool
+		var state models.ReviewState
+		var dueAt sql.NullTime
+
+		targets := []interface{}{&word.ID, &word.SourceLanguage, &word.TargetLanguage, &parts}
+		targets = append(targets, attributeTargets(&word.Attributes)...)
+		err := rows.Scan(append(targets,
+			&scheduled,
+			&state.Scheduler,
+			&state.EaseFactor,
+			&state.IntervalDays,
+			&state.Repetitions,
+			&state.Lapses,
+			&state.Box,
+			&state.Stability,
+			&state.Difficulty,
+			&dueAt,
+			&state.LastReviewedAt,
+		)...)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		word.Parts = json.RawMessage(parts)
+		if scheduled {
+			state.WordID = word.ID
+			state.DueAt = dueAt.Time
+			word.ReviewState = &state
+		}
+		words = append(words, word)
+	}
+
+	return words, total, nil
+}
+
+// srsTime normalises schedule timestamps to whole UTC seconds so that they
+// compare correctly as stored text
+func srsTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+func srsTimePtr(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return srsTime(*t)
+}
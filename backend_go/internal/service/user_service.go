@@ -0,0 +1,121 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+)
+
+type UserService struct {
+	db *sql.DB
+}
+
+func NewUserService() *UserService {
+	return &UserService{
+		db: storage.GetDB(),
+	}
+}
+
+const userColumns = "id, email, name, role, created_at"
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// getUser returns a user, or a NotFoundError if it does not exist
+func getUser(q queryer, id int64) (*models.User, error) {
+	user, err := scanUser(q.QueryRow("SELECT "+userColumns+" FROM users WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, notFound("user", id)
+	}
+	return user, err
+}
+
+// List returns a page of user accounts
+func (s *UserService) List(page, perPage int) ([]models.User, int, error) {
+	offset := (page - 1) * perPage
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT `+userColumns+`
+		FROM users
+		ORDER BY id
+		LIMIT ? OFFSET ?
+	`, perPage, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, total, rows.Err()
+}
+
+// SetRole changes a user's role on behalf of actorID and records it in the
+// audit log. The last admin cannot be demoted, so the portal always keeps
+// someone who can manage it.
+func (s *UserService) SetRole(actorID, id int64, role string) (*models.User, error) {
+	if !validRole(role) {
+		return nil, &ValidationError{Message: "role must be one of " + strings.Join(Roles(), ", ")}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	user, err := getUser(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == RoleAdmin && role != RoleAdmin {
+		var admins int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM users WHERE role = ?", RoleAdmin).Scan(&admins); err != nil {
+			return nil, err
+		}
+		if admins == 1 {
+			return nil, &ConflictError{Message: "cannot demote the last admin"}
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE users SET role = ? WHERE id = ?", role, id); err != nil {
+		return nil, err
+	}
+
+	err = recordAudit(tx, actorID, "set_role", map[string]interface{}{
+		"user_id": id,
+		"from":    user.Role,
+		"to":      role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+)
+
+// The formats vocabulary is imported and exported in
+const (
+	// FormatCSV and FormatTSV hold one word per row. The header row names
+	// the word part, attribute or groups column each column holds.
+	FormatCSV = "csv"
+	FormatTSV = "tsv"
+	// FormatSeed is the db/seeds shape: one group and its words
+	FormatSeed = "seed"
+	// FormatBundle holds groups, words, the links between them and study
+	// activities
+	FormatBundle = "bundle"
+	// FormatApkg is an Anki deck package. Its decks are groups and its notes
+	// words.
+	FormatApkg = "apkg"
+)
+
+// Formats lists every import and export format
+var Formats = []string{FormatCSV, FormatTSV, FormatSeed, FormatBundle, FormatApkg}
+
+func checkFormat(format string) error {
+	if !slices.Contains(Formats, format) {
+		return newFieldsError([]FieldError{{Field: "format", Message: "must be one of " + strings.Join(Formats, ", ")}})
+	}
+	return nil
+}
+
+// GroupsColumn is the CSV and TSV column holding the names of a word's
+// groups, separated by groupSeparator
+const GroupsColumn = "groups"
+
+const groupSeparator = ";"
+
+// attributeColumns are the CSV and TSV columns of word attributes, in
+// export order
+var attributeColumns = []string{"part_of_speech", "gender", "plural", "ipa", "register", "notes"}
+
+// attributePrefix starts the name of an attribute column when a field of
+// the language pair has the attribute's name, such as the gender of French
+// words. It can start any attribute column.
+const attributePrefix = "attributes."
+
+// attributeColumn returns the column of an attribute among the fields of a
+// language pair
+func attributeColumn(name string, fields map[string]bool) string {
+	if fields[name] {
+		return attributePrefix + name
+	}
+	return name
+}
+
+// columnAttribute returns the attribute a column holds, or "" if it holds
+// none
+func columnAttribute(column string, fields map[string]bool) string {
+	name := strings.TrimPrefix(column, attributePrefix)
+	if (name != column || !fields[column]) && slices.Contains(attributeColumns, name) {
+		return name
+	}
+	return ""
+}
+
+// attributeValues returns the attributes in the order of attributeColumns
+func attributeValues(attributes models.WordAttributes) []string {
+	return []string{
+		attributes.PartOfSpeech,
+		attributes.Gender,
+		attributes.Plural,
+		attributes.IPA,
+		attributes.Register,
+		attributes.Notes,
+	}
+}
+
+// SeedFile is a file of db/seeds: a group of a language pair and its words.
+// Each word holds its parts and optionally its attributes under
+// "attributes".
+type SeedFile struct {
+	GroupName      string                       `json:"group_name"`
+	SourceLanguage string                       `json:"source_language,omitempty"`
+	TargetLanguage string                       `json:"target_language,omitempty"`
+	Words          []map[string]json.RawMessage `json:"words"`
+}
+
+// BundleVersion is the version of the bundles written by exports
+const BundleVersion = 1
+
+// Bundle is a full export of vocabulary. Ids only link the items of the
+// bundle; imports give them new ones.
+type Bundle struct {
+	Version         int              `json:"version"`
+	Groups          []BundleGroup    `json:"groups"`
+	Words           []BundleWord     `json:"words"`
+	WordGroups      []BundleLink     `json:"word_groups"`
+	StudyActivities []BundleActivity `json:"study_activities"`
+}
+
+type BundleGroup struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	SourceLanguage string `json:"source_language"`
+	TargetLanguage string `json:"target_language"`
+}
+
+type BundleWord struct {
+	ID             int64           `json:"id"`
+	SourceLanguage string          `json:"source_language"`
+	TargetLanguage string          `json:"target_language"`
+	Parts          json.RawMessage `json:"parts"`
+	Attributes     json.RawMessage `json:"attributes,omitempty"`
+}
+
+// BundleLink puts a word of a bundle in one of its groups
+type BundleLink struct {
+	WordID  int64 `json:"word_id"`
+	GroupID int64 `json:"group_id"`
+}
+
+type BundleActivity struct {
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Scheduler    string `json:"scheduler,omitempty"`
+}
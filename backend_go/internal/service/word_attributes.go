@@ -0,0 +1,154 @@
+package service
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
+)
+
+// The values allowed for word attributes, kept in line with the CHECK
+// constraints on the words table
+var (
+	PartsOfSpeech = []string{"noun", "verb", "adjective", "adverb", "pronoun", "determiner", "preposition", "conjunction", "interjection", "phrase"}
+	Genders       = []string{"masculine", "feminine", "neuter", "common"}
+	Registers     = []string{"formal", "neutral", "informal", "slang", "vulgar"}
+)
+
+// wordAttributeColumns selects the attributes of the words aliased w in the
+// order scanned by attributeTargets
+const wordAttributeColumns = `
+	COALESCE(w.part_of_speech, ''),
+	COALESCE(w.gender, ''),
+	COALESCE(w.plural, ''),
+	COALESCE(w.ipa, ''),
+	COALESCE(w.register, ''),
+	COALESCE(w.notes, '')
+`
+
+func attributeTargets(attributes *models.WordAttributes) []interface{} {
+	return []interface{}{
+		&attributes.PartOfSpeech,
+		&attributes.Gender,
+		&attributes.Plural,
+		&attributes.IPA,
+		&attributes.Register,
+		&attributes.Notes,
+	}
+}
+
+// attributeArgs returns the attributes to store, with NULL for unknown ones
+func attributeArgs(attributes models.WordAttributes) []interface{} {
+	return []interface{}{
+		nullString(attributes.PartOfSpeech),
+		nullString(attributes.Gender),
+		nullString(attributes.Plural),
+		nullString(attributes.IPA),
+		nullString(attributes.Register),
+		nullString(attributes.Notes),
+	}
+}
+
+// WordFilter selects the words listed. Empty fields match any word.
+type WordFilter struct {
+	LanguagePair
+	PartOfSpeech string
+	Gender       string
+	Register     string
+}
+
+// condition returns the filter as a condition on the words aliased w, with
+// its arguments
+func (f WordFilter) condition() (string, []interface{}, error) {
+	errs := checkAttributeValues(models.WordAttributes{
+		PartOfSpeech: f.PartOfSpeech,
+		Gender:       f.Gender,
+		Register:     f.Register,
+	}, "")
+	if len(errs) > 0 {
+		return "", nil, newFieldsError(errs)
+	}
+
+	languages, args := f.LanguagePair.filter("w")
+	condition := languages + `
+		AND (? = '' OR w.part_of_speech = ?)
+		AND (? = '' OR w.gender = ?)
+		AND (? = '' OR w.register = ?)
+	`
+	args = append(args, f.PartOfSpeech, f.PartOfSpeech, f.Gender, f.Gender, f.Register, f.Register)
+	return condition, args, nil
+}
+
+// applyWordAttributes sets the attributes named in changes, a JSON object,
+// on attributes. A null or empty value clears the attribute.
+func applyWordAttributes(attributes models.WordAttributes, changes json.RawMessage) (models.WordAttributes, error) {
+	if len(changes) == 0 {
+		return attributes, nil
+	}
+
+	var values map[string]*string
+	if err := json.Unmarshal(changes, &values); err != nil {
+		return attributes, &ValidationError{Message: "attributes must be a JSON object of strings"}
+	}
+
+	fields := map[string]*string{
+		"part_of_speech": &attributes.PartOfSpeech,
+		"gender":         &attributes.Gender,
+		"plural":         &attributes.Plural,
+		"ipa":            &attributes.IPA,
+		"register":       &attributes.Register,
+		"notes":          &attributes.Notes,
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []FieldError
+	for _, name := range names {
+		field, ok := fields[name]
+		if !ok {
+			errs = append(errs, FieldError{Field: "attributes." + name, Message: "is not allowed"})
+			continue
+		}
+
+		*field = ""
+		if value := values[name]; value != nil {
+			*field = strings.TrimSpace(*value)
+		}
+	}
+
+	errs = append(errs, checkAttributeValues(attributes, "attributes.")...)
+	if len(errs) > 0 {
+		return attributes, newFieldsError(errs)
+	}
+
+	return attributes, nil
+}
+
+// checkAttributeValues returns an error for every attribute limited to a
+// fixed set that has another value. prefix is put before the field names.
+func checkAttributeValues(attributes models.WordAttributes, prefix string) []FieldError {
+	var errs []FieldError
+	for _, attribute := range []struct {
+		name    string
+		value   string
+		allowed []string
+	}{
+		{"gender", attributes.Gender, Genders},
+		{"part_of_speech", attributes.PartOfSpeech, PartsOfSpeech},
+		{"register", attributes.Register, Registers},
+	} {
+		if attribute.value != "" && !slices.Contains(attribute.allowed, attribute.value) {
+			errs = append(errs, FieldError{
+				Field:   prefix + attribute.name,
+				Message: "must be one of " + strings.Join(attribute.allowed, ", "),
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// The sides of a word a search looks at. Searching both sides finds words
+// from either language of the pair.
+const (
+	SearchBoth   = ""
+	SearchSource = "source"
+	SearchTarget = "target"
+)
+
+func init() {
+	storage.RegisterFunction("search_match", searchMatch)
+}
+
+// MaxSearchTerms bounds the words of a search query
+const MaxSearchTerms = 10
+
+// WordSearch is a full-text search for words. Every term of Query must
+// match the start of a word in the parts searched, ignoring case and
+// accents, so partially typed queries find words as they are typed.
+type WordSearch struct {
+	LanguagePair
+	Query     string
+	Direction string
+}
+
+// searchColumns returns the columns of the words_search index to match
+func (q WordSearch) searchColumns() ([]string, error) {
+	switch q.Direction {
+	case SearchBoth:
+		return []string{"source_text", "target_text"}, nil
+	case SearchSource:
+		return []string{"source_text"}, nil
+	case SearchTarget:
+		return []string{"target_text"}, nil
+	}
+	return nil, newFieldsError([]FieldError{{Field: "direction", Message: "must be source or target"}})
+}
+
+// searchTerms splits a query into lower case words, dropping the
+// punctuation that has a meaning in FTS queries
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(fold(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchExpression returns the FTS query matching every term as a prefix,
+// restricted to one column when a single side is searched. FTS4 terms are
+// left unquoted; searchTerms only keeps letters and digits.
+func matchExpression(terms, columns []string, fts5 bool) string {
+	expressions := make([]string, len(terms))
+	for i, term := range terms {
+		switch {
+		case fts5 && len(columns) == 1:
+			expressions[i] = columns[0] + `: "` + term + `"*`
+		case fts5:
+			expressions[i] = `"` + term + `"*`
+		case len(columns) == 1:
+			expressions[i] = columns[0] + ":" + term + "*"
+		default:
+			expressions[i] = term + "*"
+		}
+	}
+	return strings.Join(expressions, " ")
+}
+
+// usesFTS5 reports whether words_search is the FTS5 index. SQLite builds
+// without the sqlite_fts5 tag skip the migration creating it and keep the
+// FTS4 index, which has no bm25().
+func usesFTS5(q queryer) (bool, error) {
+	var fts5 bool
+	err := q.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM sqlite_master WHERE name = 'words_search' AND sql LIKE '%USING fts5%'
+		)
+	`).Scan(&fts5)
+	return fts5, err
+}
+
+var accentFolder = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// fold lower cases s and removes its accents the way the words_search
+// tokenizer does
+func fold(s string) string {
+	folded, _, err := transform.String(accentFolder, strings.ToLower(s))
+	if err != nil {
+		return strings.ToLower(s)
+	}
+	return folded
+}
+
+// The ways a part can match the terms of a query, from best to worst
+const (
+	matchPartial = iota
+	matchPrefix
+	matchExact
+)
+
+// partMatch returns how well the best part of a side of the index matches
+// the terms. Parts are separated by " | " in the index.
+func partMatch(text string, terms []string) int {
+	best := matchPartial
+	for _, part := range strings.Split(text, " | ") {
+		words := searchTerms(part)
+		if slices.Equal(words, terms) {
+			return matchExact
+		}
+		if startsWith(words, terms) {
+			best = matchPrefix
+		}
+	}
+	return best
+}
+
+// startsWith reports whether words start with the terms, the last of which
+// may be partially typed
+func startsWith(words, terms []string) bool {
+	if len(words) < len(terms) {
+		return false
+	}
+	last := len(terms) - 1
+	return slices.Equal(words[:last], terms[:last]) && strings.HasPrefix(words[last], terms[last])
+}
+
+// searchMatch returns how well a row of the words_search index matches a
+// query on the sides searched in direction. Search orders by it from SQL.
+func searchMatch(source, target, direction, query string) int {
+	terms := searchTerms(query)
+	match := matchPartial
+	if direction != SearchTarget {
+		match = partMatch(source, terms)
+	}
+	if direction != SearchSource {
+		match = max(match, partMatch(target, terms))
+	}
+	return match
+}
+
+// Search returns a page of the words matching a full-text search with the
+// user's review counts, most relevant first. Words with a part that is
+// exactly the query come first, then words with a part starting with it,
+// then the others by their bm25 rank, or by length on the FTS4 index.
+func (s *WordService) Search(userID int64, search WordSearch, page, perPage int) ([]WordResponse, int, error) {
+	columns, err := search.searchColumns()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	terms := searchTerms(search.Query)
+	if len(terms) == 0 {
+		return nil, 0, newFieldsError([]FieldError{{Field: "q", Message: "must contain a letter or digit"}})
+	}
+	if len(terms) > MaxSearchTerms {
+		return nil, 0, newFieldsError([]FieldError{{Field: "q", Message: fmt.Sprintf("must have at most %d words", MaxSearchTerms)}})
+	}
+
+	fts5, err := usesFTS5(s.db)
+	if err != nil {
+		return nil, 0, err
+	}
+	// FTS4 has no bm25(); shorter parts are closer matches there
+	score := "length(COALESCE(source_text, '')) + length(COALESCE(target_text, ''))"
+	if fts5 {
+		score = "bm25(words_search)"
+	}
+
+	match := matchExpression(terms, columns, fts5)
+	languages, languageArgs := search.LanguagePair.filter("w")
+
+	var total int
+	err = s.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM words_search
+		JOIN words w ON w.id = words_search.rowid
+		WHERE words_search MATCH ? AND `+languages,
+		append([]interface{}{match}, languageArgs...)...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The hits are materialized so bm25() runs in the full-text query
+	// rather than in the aggregate joining the review counts
+	args := []interface{}{search.Direction, search.Query, match, userID}
+	args = append(args, languageArgs...)
+	rows, err := s.db.Query(`
+		WITH hits AS MATERIALIZED (
+			SELECT
+				rowid AS id,
+				search_match(COALESCE(source_text, ''), COALESCE(target_text, ''), ?, ?) AS tier,
+				`+score+` AS score
+			FROM words_search
+			WHERE words_search MATCH ?
+		)
+		SELECT
+			w.id,
+			w.source_language,
+			w.target_language,
+			json(w.parts) as parts,
+			`+wordAttributeColumns+`,
+			COALESCE(SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END), 0) as correct_count,
+			COALESCE(SUM(CASE WHEN NOT wri.correct THEN 1 ELSE 0 END), 0) as wrong_count
+		FROM hits
+		JOIN words w ON w.id = hits.id
+		LEFT JOIN word_review_items wri ON w.id = wri.word_id
+			AND wri.study_session_id IN (SELECT id FROM study_sessions WHERE user_id = ?)
+		WHERE `+languages+`
+		GROUP BY w.id
+		ORDER BY hits.tier DESC, hits.score, w.id
+		LIMIT ? OFFSET ?
+	`, append(args, perPage, (page-1)*perPage)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	words := []WordResponse{}
+	for rows.Next() {
+		word, err := scanWordResponse(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		words = append(words, *word)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return words, total, nil
+}
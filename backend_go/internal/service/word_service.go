@@ -3,7 +3,9 @@ package service
 import (
 	"database/sql"
 	"encoding/json"
+	"time"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
 )
 
@@ -19,33 +21,65 @@ func NewWordService() *WordService {
 
 // Add this struct for word response
 type WordResponse struct {
-	ID           int64           `json:"id"`
-	Parts        json.RawMessage `json:"parts"`
-	CorrectCount int             `json:"correct_count"`
-	WrongCount   int             `json:"wrong_count"`
+	ID             int64                 `json:"id"`
+	SourceLanguage string                `json:"source_language"`
+	TargetLanguage string                `json:"target_language"`
+	Parts          json.RawMessage       `json:"parts"`
+	Attributes     models.WordAttributes `json:"attributes"`
+	CorrectCount   int                   `json:"correct_count"`
+	WrongCount     int                   `json:"wrong_count"`
 }
 
-// Update the List method to include counts
-func (s *WordService) List(page, perPage int) ([]WordResponse, int, error) {
+// WordInput is a word to write. Attributes is a JSON object of the
+// attributes to set; nil leaves them unchanged. GroupIDs replaces the word's
+// groups unless it is nil.
+type WordInput struct {
+	Pair       LanguagePair
+	Parts      json.RawMessage
+	Attributes json.RawMessage
+	GroupIDs   []int64
+}
+
+// List returns a page of the words matching the filter and options with
+// the user's review counts
+func (s *WordService) List(userID int64, filter WordFilter, options ListOptions, page, perPage int) ([]WordResponse, int, error) {
 	offset := (page - 1) * perPage
+	condition, conditionArgs, err := filter.condition()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list, err := options.query(s.db, wordListSpec)
+	if err != nil {
+		return nil, 0, err
+	}
+	condition += " AND " + list.condition
+	conditionArgs = append(conditionArgs, list.args...)
 
 	var total int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM words").Scan(&total)
+	err = s.db.QueryRow("SELECT COUNT(*) FROM words w WHERE "+condition, conditionArgs...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	args := append([]interface{}{userID}, conditionArgs...)
 	rows, err := s.db.Query(`
 		SELECT 
 			w.id, 
+			w.source_language,
+			w.target_language,
 			json(w.parts) as parts,
+			`+wordAttributeColumns+`,
 			COALESCE(SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END), 0) as correct_count,
 			COALESCE(SUM(CASE WHEN NOT wri.correct THEN 1 ELSE 0 END), 0) as wrong_count
 		FROM words w
 		LEFT JOIN word_review_items wri ON w.id = wri.word_id
+			AND wri.study_session_id IN (SELECT id FROM study_sessions WHERE user_id = ?)
+		WHERE `+condition+`
 		GROUP BY w.id
+		ORDER BY `+list.orderBy+`
 		LIMIT ? OFFSET ?
-	`, perPage, offset)
+	`, append(args, perPage, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -53,46 +87,34 @@ func (s *WordService) List(page, perPage int) ([]WordResponse, int, error) {
 
 	var words []WordResponse
 	for rows.Next() {
-		var scanWord struct {
-			ID           int64  `json:"id"`
-			Parts        []byte `json:"parts"`
-			CorrectCount int    `json:"correct_count"`
-			WrongCount   int    `json:"wrong_count"`
-		}
-		err := rows.Scan(&scanWord.ID, &scanWord.Parts, &scanWord.CorrectCount, &scanWord.WrongCount)
+		word, err := scanWordResponse(rows)
 		if err != nil {
 			return nil, 0, err
 		}
-		words = append(words, WordResponse{
-			ID:           scanWord.ID,
-			Parts:        json.RawMessage(scanWord.Parts),
-			CorrectCount: scanWord.CorrectCount,
-			WrongCount:   scanWord.WrongCount,
-		})
+		words = append(words, *word)
 	}
 
 	return words, total, nil
 }
 
-// Update the Get method to include counts
-func (s *WordService) Get(id int64) (*WordResponse, error) {
-	var scanWord struct {
-		ID           int64  `json:"id"`
-		Parts        []byte `json:"parts"`
-		CorrectCount int    `json:"correct_count"`
-		WrongCount   int    `json:"wrong_count"`
-	}
-	err := s.db.QueryRow(`
+// Get returns a word with the user's review counts, or nil if it does not
+// exist
+func (s *WordService) Get(userID, id int64) (*WordResponse, error) {
+	word, err := scanWordResponse(s.db.QueryRow(`
 		SELECT 
 			w.id, 
+			w.source_language,
+			w.target_language,
 			json(w.parts) as parts,
+			`+wordAttributeColumns+`,
 			COALESCE(SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END), 0) as correct_count,
 			COALESCE(SUM(CASE WHEN NOT wri.correct THEN 1 ELSE 0 END), 0) as wrong_count
 		FROM words w
 		LEFT JOIN word_review_items wri ON w.id = wri.word_id
+			AND wri.study_session_id IN (SELECT id FROM study_sessions WHERE user_id = ?)
 		WHERE w.id = ?
 		GROUP BY w.id
-	`, id).Scan(&scanWord.ID, &scanWord.Parts, &scanWord.CorrectCount, &scanWord.WrongCount)
+	`, userID, id))
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -101,10 +123,288 @@ func (s *WordService) Get(id int64) (*WordResponse, error) {
 		return nil, err
 	}
 
-	return &WordResponse{
-		ID:           scanWord.ID,
-		Parts:        json.RawMessage(scanWord.Parts),
-		CorrectCount: scanWord.CorrectCount,
-		WrongCount:   scanWord.WrongCount,
-	}, nil
+	return word, nil
+}
+
+func scanWordResponse(row rowScanner) (*WordResponse, error) {
+	var word WordResponse
+	var parts []byte
+	targets := []interface{}{&word.ID, &word.SourceLanguage, &word.TargetLanguage, &parts}
+	targets = append(targets, attributeTargets(&word.Attributes)...)
+	targets = append(targets, &word.CorrectCount, &word.WrongCount)
+	if err := row.Scan(targets...); err != nil {
+		return nil, err
+	}
+	word.Parts = json.RawMessage(parts)
+	return &word, nil
+}
+
+// ListDue returns words of a language pair whose review by the user is due
+// now, most overdue first. With includeNew set, words the user never
+// reviewed are listed after them.
+func (s *WordService) ListDue(userID int64, pair LanguagePair, includeNew bool, page, perPage int) ([]DueWordResponse, int, error) {
+	return listDueWords(s.db, userID, 0, pair, includeNew, time.Now(), page, perPage)
+}
+
+// Create stores a new word and links it to the given groups, which must be
+// for the same language pair. Empty languages default to French to English.
+func (s *WordService) Create(userID int64, input WordInput) (*WordResponse, error) {
+	pair := input.Pair.orDefault()
+	normalized, err := normalizeWordParts(s.db, pair, input.Parts)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := applyWordAttributes(models.WordAttributes{}, input.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	id, err := insertWord(tx, pair, normalized, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setWordGroups(tx, id, input.GroupIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.Get(userID, id)
+}
+
+// Update replaces a word's parts and, for the languages given, its language
+// pair. Its attributes are replaced when input has any.
+func (s *WordService) Update(userID, id int64, input WordInput) (*WordResponse, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := loadWord(tx, id, input.Pair)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := normalizeWordParts(tx, existing.pair, input.Parts)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := existing.attributes
+	if len(input.Attributes) > 0 {
+		attributes, err = applyWordAttributes(models.WordAttributes{}, input.Attributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeWord(tx, id, existing.pair, normalized, attributes, input.GroupIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.Get(userID, id)
+}
+
+// Patch merges the given top-level keys into a word's existing parts and
+// attributes and changes the languages given. The word is read in the
+// transaction that writes it, so concurrent patches do not overwrite each
+// other's keys.
+func (s *WordService) Patch(userID, id int64, input WordInput) (*WordResponse, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := loadWord(tx, id, input.Pair)
+	if err != nil {
+		return nil, err
+	}
+
+	combined := json.RawMessage(existing.parts)
+	if len(input.Parts) > 0 {
+		merged := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(existing.parts, &merged); err != nil {
+			return nil, err
+		}
+
+		var changes map[string]json.RawMessage
+		if err := json.Unmarshal(input.Parts, &changes); err != nil {
+			return nil, &ValidationError{Message: "parts must be a JSON object"}
+		}
+		for key, value := range changes {
+			merged[key] = value
+		}
+
+		combined, err = json.Marshal(merged)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	normalized, err := normalizeWordParts(tx, existing.pair, combined)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := applyWordAttributes(existing.attributes, input.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeWord(tx, id, existing.pair, normalized, attributes, input.GroupIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.Get(userID, id)
+}
+
+// storedWord is a word as it is stored
+type storedWord struct {
+	pair       LanguagePair
+	parts      []byte
+	attributes models.WordAttributes
+}
+
+// loadWord returns a stored word with the languages given in pair replaced
+func loadWord(q queryer, id int64, pair LanguagePair) (*storedWord, error) {
+	var word storedWord
+	targets := []interface{}{&word.pair.Source, &word.pair.Target, &word.parts}
+	err := q.QueryRow(`
+		SELECT w.source_language, w.target_language, w.parts, `+wordAttributeColumns+`
+		FROM words w
+		WHERE w.id = ?
+	`, id).Scan(append(targets, attributeTargets(&word.attributes)...)...)
+	if err == sql.ErrNoRows {
+		return nil, notFound("word", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if pair.Source != "" {
+		word.pair.Source = pair.Source
+	}
+	if pair.Target != "" {
+		word.pair.Target = pair.Target
+	}
+
+	return &word, nil
+}
+
+// Delete removes a word along with its group links and review history
+func (s *WordService) Delete(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := requireExists(tx, "words", "word", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM word_review_items WHERE word_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM word_review_states WHERE word_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM word_groups WHERE word_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM words WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// writeWord replaces the stored parts, languages and attributes of a word
+// and, unless groupIDs is nil, its groups
+func writeWord(tx *sql.Tx, id int64, pair LanguagePair, parts string, attributes models.WordAttributes, groupIDs []int64) error {
+	args := append([]interface{}{parts, pair.Source, pair.Target}, attributeArgs(attributes)...)
+	result, err := tx.Exec(`
+		UPDATE words SET
+			parts = ?, source_language = ?, target_language = ?,
+			part_of_speech = ?, gender = ?, plural = ?, ipa = ?, register = ?, notes = ?
+		WHERE id = ?
+	`, append(args, id)...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound("word", id)
+	}
+
+	if groupIDs != nil {
+		return setWordGroups(tx, id, groupIDs)
+	}
+	return requireMatchingLanguages(tx, "wg.word_id", id)
+}
+
+// insertWord stores a word whose parts and attributes were checked and
+// returns its id
+func insertWord(tx *sql.Tx, pair LanguagePair, parts string, attributes models.WordAttributes) (int64, error) {
+	args := append([]interface{}{parts, pair.Source, pair.Target}, attributeArgs(attributes)...)
+	result, err := tx.Exec(`
+		INSERT INTO words (
+			parts, source_language, target_language,
+			part_of_speech, gender, plural, ipa, register, notes
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// setWordGroups replaces the groups a word belongs to
+func setWordGroups(tx *sql.Tx, wordID int64, groupIDs []int64) error {
+	for _, groupID := range groupIDs {
+		if err := requireReference(tx, "groups", "group", groupID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM word_groups WHERE word_id = ?", wordID); err != nil {
+		return err
+	}
+
+	for _, groupID := range groupIDs {
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO word_groups (word_id, group_id)
+			VALUES (?, ?)
+		`, wordID, groupID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return requireMatchingLanguages(tx, "wg.word_id", wordID)
 }
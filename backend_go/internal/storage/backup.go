@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var backupDir string
+
+// BackupDir returns the directory database snapshots are written to, or ""
+// when snapshots are disabled
+func BackupDir() string {
+	return backupDir
+}
+
+// SetBackupDir sets the directory database snapshots are written to
+func SetBackupDir(dir string) {
+	backupDir = dir
+}
+
+// Snapshot writes a consistent copy of conn's database to path, which must
+// not exist. Other connections keep reading and writing while it runs.
+func Snapshot(conn *sql.DB, path string) error {
+	_, err := conn.Exec("VACUUM INTO ?", path)
+	return err
+}
+
+// Restore replaces the content of conn's database with the database at
+// path using SQLite's online backup API. Connections of conn's pool see the
+// restored database once it returns.
+func Restore(conn *sql.DB, path string) error {
+	ctx := context.Background()
+
+	source, err := Open("file:" + path + "?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	src, err := source.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	return dst.Raw(func(dstConn interface{}) error {
+		return src.Raw(func(srcConn interface{}) error {
+			to, ok := dstConn.(*sqlite3.SQLiteConn)
+			from, fromOK := srcConn.(*sqlite3.SQLiteConn)
+			if !ok || !fromOK {
+				return errors.New("restoring needs SQLite connections")
+			}
+
+			backup, err := to.Backup("main", from, "main")
+			if err != nil {
+				return err
+			}
+			if _, err := backup.Step(-1); err != nil {
+				backup.Finish()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
@@ -2,103 +2,99 @@ package storage
 
 import (
 	"database/sql"
+	"log"
+	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/db/migrations"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage/migrate"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// driverName is the go-sqlite3 driver with the SQL functions registered by
+// RegisterFunction
+const driverName = "sqlite3_portal"
+
+var (
+	db        *sql.DB
+	functions = map[string]interface{}{}
 )
 
-var db *sql.DB
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			for name, impl := range functions {
+				if err := conn.RegisterFunc(name, impl, true); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterFunction makes the pure Go function impl callable from SQL as
+// name on the connections opened afterwards. Call it from an init function.
+func RegisterFunction(name string, impl interface{}) {
+	functions[name] = impl
+}
 
-// InitDB initializes the database connection
+// Open opens a SQLite database with foreign key constraints enforced and the
+// registered functions available on every connection of the pool
+func Open(dataSourceName string) (*sql.DB, error) {
+	separator := "?"
+	if strings.Contains(dataSourceName, "?") {
+		separator = "&"
+	}
+
+	return sql.Open(driverName, dataSourceName+separator+"_foreign_keys=on")
+}
+
+// InitDB initializes the database connection and applies pending migrations
 func InitDB(dataSourceName string) error {
 	var err error
-	db, err = sql.Open("sqlite3", dataSourceName)
+	db, err = Open(dataSourceName)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Ping(); err != nil {
+		return err
+	}
+
+	return Migrate(db)
+}
+
+// NewMigrator returns a migrator for the embedded db/migrations files
+func NewMigrator(conn *sql.DB) (*migrate.Migrator, error) {
+	return migrate.New(conn, migrations.FS)
+}
+
+// Migrate applies all pending embedded migrations to conn
+func Migrate(conn *sql.DB) error {
+	migrator, err := NewMigrator(conn)
 	if err != nil {
 		return err
 	}
 
-	// Run migrations
-	migrationSQL := `
-		-- Create words table
-		CREATE TABLE IF NOT EXISTS words (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			parts TEXT NOT NULL
-		);
-
-		-- Create groups table
-		CREATE TABLE IF NOT EXISTS groups (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL,
-			words_count INTEGER DEFAULT 0
-		);
-
-		-- Create word_groups table
-		CREATE TABLE IF NOT EXISTS word_groups (
-			word_id INTEGER,
-			group_id INTEGER,
-			FOREIGN KEY (word_id) REFERENCES words(id),
-			FOREIGN KEY (group_id) REFERENCES groups(id),
-			PRIMARY KEY (word_id, group_id)
-		);
-
-		-- Create study_activities table
-		CREATE TABLE IF NOT EXISTS study_activities (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL,
-			url TEXT NOT NULL,
-			thumbnail_url TEXT,
-			description TEXT
-		);
-
-		-- Create study_sessions table
-		CREATE TABLE IF NOT EXISTS study_sessions (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			group_id INTEGER,
-			study_activity_id INTEGER,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (group_id) REFERENCES groups(id),
-			FOREIGN KEY (study_activity_id) REFERENCES study_activities(id)
-		);
-
-		-- Create word_review_items table
-		CREATE TABLE IF NOT EXISTS word_review_items (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			word_id INTEGER,
-			study_session_id INTEGER,
-			correct BOOLEAN NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (word_id) REFERENCES words(id),
-			FOREIGN KEY (study_session_id) REFERENCES study_sessions(id)
-		);
-
-		-- Insert some test data
-		INSERT INTO words (parts) VALUES 
-			('{"french":"bonjour","english":"hello"}'),
-			('{"french":"au revoir","english":"goodbye"}'),
-			('{"french":"merci","english":"thank you"}');
-
-		-- Insert some groups
-		INSERT INTO groups (name, words_count) VALUES 
-			('Basics', 2),
-			('Greetings', 1);
-
-		-- Link words to groups
-		INSERT INTO word_groups (word_id, group_id) VALUES 
-			(1, 1),
-			(2, 1),
-			(1, 2);
-
-		-- Insert study activities
-		INSERT INTO study_activities (name, url, thumbnail_url, description) VALUES
-			('Flashcards', '/activities/flashcards', '/thumbnails/flashcards.jpg', 'Practice with flashcards'),
-			('Quiz', '/activities/quiz', '/thumbnails/quiz.jpg', 'Test your knowledge');
-	`
-
-	_, err = db.Exec(migrationSQL)
+	if _, err := migrator.Up(false); err != nil {
+		return err
+	}
+
+	// Migrations needing a module this SQLite build lacks stay pending
+	pending, err := migrator.Pending()
 	if err != nil {
 		return err
 	}
+	for _, migration := range pending {
+		missing, err := migrator.MissingModules(migration)
+		if err != nil {
+			return err
+		}
+		log.Printf("Skipped migration %04d_%s: SQLite lacks the %s module", migration.Version, migration.Name, strings.Join(missing, ", "))
+	}
 
-	return db.Ping()
+	return nil
 }
 
 // GetDB returns the database instance
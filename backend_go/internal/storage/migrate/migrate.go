@@ -0,0 +1,557 @@
+// Package migrate applies versioned SQL migrations and records each applied
+// version in the schema_migrations table.
+//
+// Migrations are read from an fs.FS (normally the embedded db/migrations
+// directory) and must be named NNNN_name.up.sql with an optional matching
+// NNNN_name.down.sql.
+//
+// Each migration runs in its own transaction with foreign key enforcement
+// switched off, so tables can be rebuilt, and is only committed if
+// PRAGMA foreign_key_check finds no new dangling references afterwards.
+//
+// A migration creating a virtual table with a module the SQLite build lacks,
+// such as fts5, is skipped and stays pending until the database is migrated
+// by a build that has the module.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChecksumMismatch is returned when an applied migration has been edited
+// after it was run.
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// ErrModuleUnavailable is returned when an applied migration created a
+// virtual table with a module the SQLite build lacks.
+var ErrModuleUnavailable = errors.New("SQLite module unavailable")
+
+var (
+	fileNamePattern     = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+	virtualTablePattern = regexp.MustCompile(`(?i)CREATE\s+VIRTUAL\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?\S+\s+USING\s+(\w+)`)
+)
+
+// moduleBuildTags are the go-sqlite3 build tags that compile in the modules
+// left out of its default build
+var moduleBuildTags = map[string]string{
+	"fts5": "sqlite_fts5",
+}
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+	// Modules are the virtual table modules the up script creates tables
+	// with
+	Modules []string
+}
+
+// Status describes whether a migration has been applied to the database
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Modified  bool       `json:"modified"`
+	Missing   bool       `json:"missing"`
+}
+
+// Migrator runs migrations against a database
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// Load reads and orders all migrations found in the root of fsys
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %v", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			if strings.HasSuffix(entry.Name(), ".sql") {
+				return nil, fmt.Errorf("migration file %s does not match NNNN_name.(up|down).sql", entry.Name())
+			}
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %v", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration file %s: %v", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		m.Checksum = checksum(m.Up)
+		m.Modules = modules(m.Up)
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// New creates a Migrator for the migrations in fsys
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Migrations returns all known migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Status reports every known or recorded migration and whether it has run
+func (m *Migrator) Status() ([]Status, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = record.checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	// Versions recorded in the database whose files no longer exist
+	for version, record := range applied {
+		appliedAt := record.appliedAt
+		statuses = append(statuses, Status{
+			Version:   version,
+			Name:      record.name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet
+func (m *Migrator) Pending() ([]Migration, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	return m.pending()
+}
+
+// pending returns the migrations that have not been applied yet without
+// creating schema_migrations, so dry runs write nothing
+func (m *Migrator) pending() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if !ok {
+			pending = append(pending, migration)
+			continue
+		}
+		if record.checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies all pending migrations in order, each in its own transaction,
+// and returns the ones applied. Migrations needing a module the SQLite build
+// lacks are skipped and left pending. With dryRun set nothing is executed
+// and the migrations that would be applied are returned.
+func (m *Migrator) Up(dryRun bool) ([]Migration, error) {
+	if !dryRun {
+		if err := m.ensureTable(); err != nil {
+			return nil, err
+		}
+	}
+
+	pending, err := m.pending()
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := m.availableModules()
+	if err != nil {
+		return nil, err
+	}
+
+	isPending := make(map[int]bool, len(pending))
+	for _, migration := range pending {
+		isPending[migration.Version] = true
+	}
+	for _, migration := range m.migrations {
+		if module := missingModule(migration, available); module != "" && !isPending[migration.Version] {
+			return nil, fmt.Errorf("%w: migration %04d_%s created a %s table%s", ErrModuleUnavailable, migration.Version, migration.Name, module, buildTagHint(module))
+		}
+	}
+
+	var runnable []Migration
+	for _, migration := range pending {
+		if missingModule(migration, available) == "" {
+			runnable = append(runnable, migration)
+		}
+	}
+
+	if dryRun {
+		return runnable, nil
+	}
+
+	for i, migration := range runnable {
+		err := m.run(migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+				INSERT INTO schema_migrations (version, name, checksum, applied_at)
+				VALUES (?, ?, ?, ?)
+			`, migration.Version, migration.Name, migration.Checksum, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return runnable[:i], fmt.Errorf("error applying migration %04d_%s: %v", migration.Version, migration.Name, err)
+		}
+	}
+
+	return runnable, nil
+}
+
+// MissingModules returns the virtual table modules a migration needs that
+// the SQLite build lacks
+func (m *Migrator) MissingModules(migration Migration) ([]string, error) {
+	available, err := m.availableModules()
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, module := range migration.Modules {
+		if !available[module] {
+			missing = append(missing, module)
+		}
+	}
+	return missing, nil
+}
+
+// availableModules returns the virtual table modules of the SQLite build
+func (m *Migrator) availableModules() (map[string]bool, error) {
+	rows, err := m.db.Query("SELECT name FROM pragma_module_list")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	available := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		available[name] = true
+	}
+
+	return available, rows.Err()
+}
+
+// missingModule returns the first module of a migration that is not
+// available, or "" if it can run
+func missingModule(migration Migration, available map[string]bool) string {
+	for _, module := range migration.Modules {
+		if !available[module] {
+			return module
+		}
+	}
+	return ""
+}
+
+// buildTagHint names the build tag that compiles module into go-sqlite3
+func buildTagHint(module string) string {
+	if tag, ok := moduleBuildTags[module]; ok {
+		return fmt.Sprintf("; build with -tags %s", tag)
+	}
+	return ""
+}
+
+// Down reverts the most recently applied migrations, at most steps of them.
+// With dryRun set nothing is executed and the migrations that would be
+// reverted are returned.
+func (m *Migrator) Down(steps int, dryRun bool) ([]Migration, error) {
+	if !dryRun {
+		if err := m.ensureTable(); err != nil {
+			return nil, err
+		}
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+	if steps < 0 {
+		steps = 0
+	}
+	if steps < len(versions) {
+		versions = versions[:steps]
+	}
+
+	var targets []Migration
+	for _, version := range versions {
+		migration, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("applied migration %04d_%s has no file to revert", version, applied[version].name)
+		}
+		if migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no down file", migration.Version, migration.Name)
+		}
+		targets = append(targets, migration)
+	}
+
+	if dryRun {
+		return targets, nil
+	}
+
+	for i, migration := range targets {
+		err := m.run(migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+			return err
+		})
+		if err != nil {
+			return targets[:i], fmt.Errorf("error reverting migration %04d_%s: %v", migration.Version, migration.Name, err)
+		}
+	}
+
+	return targets, nil
+}
+
+// Version returns the highest applied migration version, or 0 if none
+func (m *Migrator) Version() (int, error) {
+	if err := m.ensureTable(); err != nil {
+		return 0, err
+	}
+
+	var version int
+	err := m.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
+func (m *Migrator) run(script string, record func(tx *sql.Tx) error) error {
+	ctx := context.Background()
+
+	// PRAGMA foreign_keys only applies to one connection and cannot change
+	// inside a transaction, so pin a connection for the whole migration
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var foreignKeys bool
+	if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		return err
+	}
+	if foreignKeys {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Only references broken by this migration count; older dangling rows
+	// are left for the migration that cleans them up
+	before, err := danglingReferences(tx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+
+	after, err := danglingReferences(tx)
+	if err != nil {
+		return err
+	}
+	if after > before {
+		return fmt.Errorf("migration leaves %d rows referencing missing rows", after-before)
+	}
+
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// danglingReferences counts rows that reference a missing parent row
+func danglingReferences(tx *sql.Tx) (int, error) {
+	rows, err := tx.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+
+	return count, rows.Err()
+}
+
+func (m *Migrator) ensureTable() error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+type appliedRecord struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// applied returns the recorded migrations by version, none if
+// schema_migrations does not exist yet
+func (m *Migrator) applied() (map[int]appliedRecord, error) {
+	var exists bool
+	err := m.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')
+	`).Scan(&exists)
+	if err != nil || !exists {
+		return map[int]appliedRecord{}, err
+	}
+
+	rows, err := m.db.Query("SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedRecord)
+	for rows.Next() {
+		var version int
+		var record appliedRecord
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = record
+	}
+
+	return applied, rows.Err()
+}
+
+// modules returns the lower case modules of the virtual tables a script
+// creates
+func modules(script string) []string {
+	var found []string
+	for _, match := range virtualTablePattern.FindAllStringSubmatch(script, -1) {
+		module := strings.ToLower(match[1])
+		if !slices.Contains(found, module) {
+			found = append(found, module)
+		}
+	}
+	return found
+}
+
+func checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,223 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY);")},
+		"0001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"0002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER PRIMARY KEY); INSERT INTO b (id) VALUES (1);")},
+		"0002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"README.md":              {Data: []byte("ignored")},
+	}
+}
+
+func TestUpIsIdempotent(t *testing.T) {
+	db := setupTestDB(t)
+
+	m, err := New(db, testFS())
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	pending, err := m.Up(true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("Expected 2 pending migrations, got %d", len(pending))
+	}
+
+	var tables int
+	db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name IN ('a', 'b', 'schema_migrations')").Scan(&tables)
+	if tables != 0 {
+		t.Errorf("Expected dry run to create no tables, found %d", tables)
+	}
+
+	if _, err := m.Up(false); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	applied, err := m.Up(false)
+	if err != nil {
+		t.Fatalf("Second Up failed: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("Expected second Up to apply nothing, applied %d", len(applied))
+	}
+
+	var rows int
+	db.QueryRow("SELECT COUNT(*) FROM b").Scan(&rows)
+	if rows != 1 {
+		t.Errorf("Expected 1 row in b, got %d", rows)
+	}
+
+	version, err := m.Version()
+	if err != nil || version != 2 {
+		t.Errorf("Expected version 2, got %d (%v)", version, err)
+	}
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	db := setupTestDB(t)
+
+	m, _ := New(db, testFS())
+	if _, err := m.Up(false); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	edited := testFS()
+	edited["0001_create_a.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY, name TEXT);")}
+
+	m, _ = New(db, edited)
+	if _, err := m.Up(false); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !statuses[0].Modified || statuses[1].Modified {
+		t.Errorf("Expected only migration 1 to be modified, got %+v", statuses)
+	}
+}
+
+func TestDown(t *testing.T) {
+	db := setupTestDB(t)
+
+	m, _ := New(db, testFS())
+	if _, err := m.Up(false); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	reverted, err := m.Down(1, false)
+	if err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+	if len(reverted) != 1 || reverted[0].Version != 2 {
+		t.Fatalf("Expected migration 2 to be reverted, got %+v", reverted)
+	}
+
+	var tables int
+	db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'b'").Scan(&tables)
+	if tables != 0 {
+		t.Error("Expected table b to be dropped")
+	}
+
+	pending, _ := m.Pending()
+	if len(pending) != 1 || pending[0].Version != 2 {
+		t.Errorf("Expected migration 2 to be pending again, got %+v", pending)
+	}
+}
+
+func TestUnavailableModule(t *testing.T) {
+	db := setupTestDB(t)
+
+	fsys := testFS()
+	fsys["0002_create_b.up.sql"] = &fstest.MapFile{Data: []byte("CREATE VIRTUAL TABLE b USING nosuchmodule(text);")}
+	fsys["0003_create_c.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE c (id INTEGER PRIMARY KEY);")}
+
+	// The migration is skipped and the later ones still run
+	m, _ := New(db, fsys)
+	applied, err := m.Up(false)
+	if err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if len(applied) != 2 || applied[0].Version != 1 || applied[1].Version != 3 {
+		t.Fatalf("Expected migrations 1 and 3 to be applied, got %+v", applied)
+	}
+
+	pending, _ := m.Pending()
+	if len(pending) != 1 || pending[0].Version != 2 {
+		t.Fatalf("Expected migration 2 to stay pending, got %+v", pending)
+	}
+	missing, err := m.MissingModules(pending[0])
+	if err != nil || len(missing) != 1 || missing[0] != "nosuchmodule" {
+		t.Errorf("Expected nosuchmodule to be missing, got %v (%v)", missing, err)
+	}
+
+	// A database migrated by a build with the module cannot be used
+	_, err = db.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES (2, 'create_b', ?)", pending[0].Checksum)
+	if err != nil {
+		t.Fatalf("Failed to record the migration: %v", err)
+	}
+	if _, err := m.Up(false); !errors.Is(err, ErrModuleUnavailable) {
+		t.Errorf("Expected ErrModuleUnavailable, got %v", err)
+	}
+}
+
+func TestLoadRejectsBadNames(t *testing.T) {
+	_, err := Load(fstest.MapFS{"init.sql": {Data: []byte("SELECT 1;")}})
+	if err == nil {
+		t.Error("Expected an error for a migration without a version")
+	}
+
+	_, err = Load(fstest.MapFS{"0001_init.down.sql": {Data: []byte("SELECT 1;")}})
+	if err == nil {
+		t.Error("Expected an error for a migration without an up file")
+	}
+}
+
+func TestForeignKeyCheck(t *testing.T) {
+	db := setupTestDB(t)
+	db.Exec("PRAGMA foreign_keys = ON")
+
+	fsys := fstest.MapFS{
+		"0001_create.up.sql": {Data: []byte(`
+			CREATE TABLE parent (id INTEGER PRIMARY KEY);
+			CREATE TABLE child (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES parent(id));
+			INSERT INTO parent (id) VALUES (1);
+			INSERT INTO child (id, parent_id) VALUES (1, 1);
+		`)},
+		// Rebuilding a referenced table is allowed while references stay valid
+		"0002_rebuild.up.sql": {Data: []byte(`
+			CREATE TABLE parent_new (id INTEGER PRIMARY KEY, name TEXT);
+			INSERT INTO parent_new (id) SELECT id FROM parent;
+			DROP TABLE parent;
+			ALTER TABLE parent_new RENAME TO parent;
+		`)},
+		"0003_dangling.up.sql": {Data: []byte("INSERT INTO child (id, parent_id) VALUES (2, 99);")},
+	}
+
+	m, _ := New(db, fsys)
+	applied, err := m.Up(false)
+	if err == nil {
+		t.Fatal("Expected the dangling reference to fail the migration")
+	}
+	if len(applied) != 2 {
+		t.Errorf("Expected 2 migrations to be applied, got %d", len(applied))
+	}
+
+	var children int
+	db.QueryRow("SELECT COUNT(*) FROM child").Scan(&children)
+	if children != 1 {
+		t.Errorf("Expected the failed migration to be rolled back, found %d children", children)
+	}
+
+	var foreignKeys bool
+	db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys)
+	if !foreignKeys {
+		t.Error("Expected foreign keys to be enforced again after migrating")
+	}
+}
@@ -1,15 +1,17 @@
 package testutil
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/api/auth"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/models"
 	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
 	"github.com/gin-gonic/gin"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 // SetupTestDB creates a test database and returns a connection
@@ -17,74 +19,49 @@ func SetupTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 
 	// Create temporary database
-	db, err := sql.Open("sqlite3", ":memory:")
+	db, err := storage.Open(":memory:")
 	if err != nil {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
+	// Every connection to ":memory:" is a separate database, so keep one
+	db.SetMaxOpenConns(1)
+
 	// Run migrations
-	migrationSQL := `
-		-- Create words table
-		CREATE TABLE IF NOT EXISTS words (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			parts TEXT NOT NULL
-		);
-
-		-- Create groups table
-		CREATE TABLE IF NOT EXISTS groups (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL,
-			words_count INTEGER DEFAULT 0
-		);
-
-		-- Create word_groups table
-		CREATE TABLE IF NOT EXISTS word_groups (
-			word_id INTEGER,
-			group_id INTEGER,
-			FOREIGN KEY (word_id) REFERENCES words(id),
-			FOREIGN KEY (group_id) REFERENCES groups(id),
-			PRIMARY KEY (word_id, group_id)
-		);
-
-		-- Create study_activities table
-		CREATE TABLE IF NOT EXISTS study_activities (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL,
-			url TEXT NOT NULL,
-			thumbnail_url TEXT,
-			description TEXT
-		);
-
-		-- Create study_sessions table
-		CREATE TABLE IF NOT EXISTS study_sessions (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			group_id INTEGER,
-			study_activity_id INTEGER,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (group_id) REFERENCES groups(id),
-			FOREIGN KEY (study_activity_id) REFERENCES study_activities(id)
-		);
-
-		-- Create word_review_items table
-		CREATE TABLE IF NOT EXISTS word_review_items (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			word_id INTEGER,
-			study_session_id INTEGER,
-			correct BOOLEAN NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (word_id) REFERENCES words(id),
-			FOREIGN KEY (study_session_id) REFERENCES study_sessions(id)
-		);
-	`
-
-	_, err = db.Exec(migrationSQL)
-	if err != nil {
+	if err := storage.Migrate(db); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	return db
 }
 
+// CreateTestUser inserts a user account with the given role and an
+// unusable password
+func CreateTestUser(t *testing.T, db *sql.DB, email, role string) *models.User {
+	t.Helper()
+
+	result, err := db.Exec("INSERT INTO users (email, name, password_hash, role) VALUES (?, ?, '', ?)", email, email, role)
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+
+	return &models.User{ID: id, Email: email, Name: email, Role: role}
+}
+
+// AsUser returns middleware that authenticates every request as user, in
+// place of auth.Middleware
+func AsUser(user *models.User) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth.SetUser(c, user)
+		c.Next()
+	}
+}
+
 // ExecuteRequest performs a test HTTP request and returns the response
 func ExecuteRequest(r *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
 	w := httptest.NewRecorder()
@@ -92,6 +69,86 @@ func ExecuteRequest(r *gin.Engine, req *http.Request) *httptest.ResponseRecorder
 	return w
 }
 
+// Request performs a test HTTP request with a JSON body
+func Request(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	return ExecuteRequest(r, req)
+}
+
+// PostJSON performs a test POST request with body encoded as JSON
+func PostJSON(r *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	return ExecuteRequest(r, req)
+}
+
+// Count runs a query returning a single number
+func Count(t *testing.T, db *sql.DB, query string, args ...interface{}) int {
+	t.Helper()
+
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("Failed to count: %v", err)
+	}
+	return n
+}
+
+// InsertHistory adds two words, the groups Animals and Pets and the study
+// activities Flashcards and Quiz. Each user then gets three completed
+// sessions, one a day from 2025-01-01: Animals with Flashcards, Animals
+// with Quiz and Pets with Flashcards. Word 1 is reviewed in both groups and
+// word 2 only in Animals, wrongly the second time.
+func InsertHistory(t *testing.T, db *sql.DB, userIDs ...int64) {
+	t.Helper()
+
+	_, err := db.Exec(`
+		INSERT INTO words (parts) VALUES ('{"french":"chat","english":"cat"}'), ('{"french":"chien","english":"dog"}');
+		INSERT INTO groups (name) VALUES ('Animals'), ('Pets');
+		INSERT INTO study_activities (name, url) VALUES ('Flashcards', 'http://test.com'), ('Quiz', 'http://test.com');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	for _, userID := range userIDs {
+		var sessions []int64
+		for _, session := range []struct {
+			groupID, activityID int64
+			createdAt           string
+		}{
+			{1, 1, "2025-01-01 10:00:00"},
+			{1, 2, "2025-01-02 10:00:00"},
+			{2, 1, "2025-01-03 10:00:00"},
+		} {
+			result, err := db.Exec(`
+				INSERT INTO study_sessions (user_id, group_id, study_activity_id, created_at, status)
+				VALUES (?, ?, ?, ?, 'completed')
+			`, userID, session.groupID, session.activityID, session.createdAt)
+			if err != nil {
+				t.Fatalf("Failed to insert test data: %v", err)
+			}
+			id, err := result.LastInsertId()
+			if err != nil {
+				t.Fatalf("Failed to insert test data: %v", err)
+			}
+			sessions = append(sessions, id)
+		}
+
+		_, err := db.Exec(`
+			INSERT INTO word_review_items (word_id, study_session_id, correct, created_at, client_id) VALUES
+			(1, ?, true, '2025-01-01 10:00:00', 'a'),
+			(2, ?, true, '2025-01-01 10:01:00', 'b'),
+			(2, ?, false, '2025-01-02 10:00:00', 'c'),
+			(1, ?, true, '2025-01-03 10:00:00', 'd')
+		`, sessions[0], sessions[0], sessions[1], sessions[2])
+		if err != nil {
+			t.Fatalf("Failed to insert test data: %v", err)
+		}
+	}
+}
+
 // CheckResponseCode verifies the HTTP status code
 func CheckResponseCode(t *testing.T, expected, actual int) {
 	t.Helper()
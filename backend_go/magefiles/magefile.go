@@ -4,17 +4,46 @@ package main
 
 import (
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
-	"sort"
+	"os/exec"
 	"strings"
 
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/service"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage"
+	"github.com/free-genai-bootcamp-2025/lang-portal/backend_go/internal/storage/migrate"
+
 	_ "github.com/mattn/go-sqlite3"
 )
 
-const dbName = "words.db"
+const (
+	dbName = "words.db"
+	// backupDir is where the server keeps its snapshots unless BACKUP_DIR
+	// says otherwise
+	backupDir = "backups"
+	// buildTags compiles SQLite's FTS5 extension, which ranks word search
+	// with bm25, into go-sqlite3. Mage itself must be run with
+	// GOFLAGS=-tags=sqlite_fts5 for the tasks that migrate the database to
+	// use it; without it the FTS5 migration is skipped.
+	buildTags = "sqlite_fts5"
+)
+
+// Run starts the server
+func Run() error {
+	return goCmd("run", "-tags", buildTags, "./cmd/server")
+}
+
+// Test runs the tests
+func Test() error {
+	return goCmd("test", "-tags", buildTags, "./...")
+}
+
+func goCmd(args ...string) error {
+	cmd := exec.Command("go", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
 
 // InitDB initializes the SQLite database
 func InitDB() error {
@@ -33,179 +62,246 @@ func InitDB() error {
 	return nil
 }
 
-// Migrate runs all database migrations
+// Migrate applies all pending database migrations
 func Migrate() error {
-	db, err := sql.Open("sqlite3", dbName)
-	if err != nil {
-		return fmt.Errorf("error opening database: %v", err)
-	}
-	defer db.Close()
-
-	files, err := filepath.Glob("db/migrations/*.sql")
-	if err != nil {
-		return fmt.Errorf("error finding migration files: %v", err)
-	}
+	return withMigrator(func(migrator *migrate.Migrator) error {
+		applied, err := migrator.Up(false)
+		for _, m := range applied {
+			fmt.Printf("Applied migration %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
 
-	sort.Strings(files)
+		pending, err := migrator.Pending()
+		if err != nil {
+			return err
+		}
+		for _, m := range pending {
+			missing, err := migrator.MissingModules(m)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Skipped migration %04d_%s: SQLite lacks the %s module\n", m.Version, m.Name, strings.Join(missing, ", "))
+		}
 
-	for _, file := range files {
-		fmt.Printf("Running migration %s\n", file)
+		if len(applied) == 0 && len(pending) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		return nil
+	})
+}
 
-		content, err := os.ReadFile(file)
+// MigrateDryRun lists the migrations Migrate would apply without running them
+func MigrateDryRun() error {
+	return withMigrator(func(migrator *migrate.Migrator) error {
+		pending, err := migrator.Up(true)
 		if err != nil {
-			return fmt.Errorf("error reading migration file %s: %v", file, err)
+			return err
 		}
 
-		statements := strings.Split(string(content), ";")
+		if len(pending) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		for _, m := range pending {
+			fmt.Printf("Would apply migration %04d_%s\n", m.Version, m.Name)
+		}
+		return nil
+	})
+}
 
-		for _, stmt := range statements {
-			stmt = strings.TrimSpace(stmt)
-			if stmt == "" {
-				continue
-			}
+// MigrateStatus prints every migration and whether it has been applied
+func MigrateStatus() error {
+	return withMigrator(func(migrator *migrate.Migrator) error {
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
 
-			_, err = db.Exec(stmt)
-			if err != nil {
-				return fmt.Errorf("error executing migration %s: %v", file, err)
+		for _, status := range statuses {
+			state := "pending"
+			switch {
+			case status.Missing:
+				state = "applied, file missing"
+			case status.Modified:
+				state = "applied, MODIFIED since it ran"
+			case status.Applied:
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
 			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, state)
 		}
-	}
+		return nil
+	})
+}
 
-	return nil
+// Rollback reverts the most recently applied migration
+func Rollback() error {
+	return withMigrator(func(migrator *migrate.Migrator) error {
+		reverted, err := migrator.Down(1, false)
+		for _, m := range reverted {
+			fmt.Printf("Reverted migration %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(reverted) == 0 {
+			fmt.Println("No migrations to revert")
+		}
+		return nil
+	})
 }
 
-// Seed imports seed data into the database
-func Seed() error {
-	db, err := sql.Open("sqlite3", dbName)
+func withMigrator(fn func(migrator *migrate.Migrator) error) error {
+	db, err := storage.Open(dbName)
 	if err != nil {
 		return fmt.Errorf("error opening database: %v", err)
 	}
 	defer db.Close()
 
-	files, err := filepath.Glob("db/seeds/*.json")
+	migrator, err := storage.NewMigrator(db)
 	if err != nil {
-		return fmt.Errorf("error finding seed files: %v", err)
+		return fmt.Errorf("error loading migrations: %v", err)
 	}
 
-	for _, file := range files {
-		fmt.Printf("Processing seed file %s\n", file)
-
-		content, err := os.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("error reading seed file %s: %v", file, err)
-		}
+	return fn(migrator)
+}
 
-		var seedData struct {
-			GroupName string `json:"group_name"`
-			Words     []struct {
-				French  string `json:"french"`
-				English string `json:"english"`
-			} `json:"words"`
-		}
+// Seed upserts the seed files listed in db/seeds/manifest.json. Running it
+// again only changes what the seed files changed.
+func Seed() error {
+	db, err := storage.Open(dbName)
+	if err != nil {
+		return fmt.Errorf("error opening database: %v", err)
+	}
+	defer db.Close()
 
-		if err := json.Unmarshal(content, &seedData); err != nil {
-			return fmt.Errorf("error parsing seed file %s: %v", file, err)
-		}
+	// Words are checked against the schema of their languages like any
+	// other write
+	storage.SetDB(db)
+	reports, err := service.NewSeedService().Seed("db/seeds")
+	if err != nil {
+		return fmt.Errorf("error seeding: %v", err)
+	}
 
-		// Create group
-		result, err := db.Exec("INSERT INTO groups (name) VALUES (?)", seedData.GroupName)
-		if err != nil {
-			return fmt.Errorf("error creating group: %v", err)
-		}
+	var groups, words, activities service.SeedCounts
+	for _, report := range reports {
+		fmt.Printf("Seeded %s\n", report.File)
+		groups = addCounts(groups, report.Groups)
+		words = addCounts(words, report.Words)
+		activities = addCounts(activities, report.Activities)
+	}
+	fmt.Printf("Groups: %s\n", groups)
+	fmt.Printf("Words: %s\n", words)
+	fmt.Printf("Study activities: %s\n", activities)
+	return nil
+}
 
-		groupID, err := result.LastInsertId()
-		if err != nil {
-			return fmt.Errorf("error getting group ID: %v", err)
-		}
+func addCounts(a, b service.SeedCounts) service.SeedCounts {
+	return service.SeedCounts{
+		Inserted:  a.Inserted + b.Inserted,
+		Updated:   a.Updated + b.Updated,
+		Unchanged: a.Unchanged + b.Unchanged,
+	}
+}
 
-		// Insert words and create word_group relationships
-		for _, word := range seedData.Words {
-			parts, err := json.Marshal(map[string]string{
-				"french":  word.French,
-				"english": word.English,
-			})
-			if err != nil {
-				return fmt.Errorf("error marshaling word parts: %v", err)
-			}
+// RepairCounts recomputes groups.words_count wherever it has drifted
+func RepairCounts() error {
+	db, err := storage.Open(dbName)
+	if err != nil {
+		return fmt.Errorf("error opening database: %v", err)
+	}
+	defer db.Close()
 
-			result, err := db.Exec("INSERT INTO words (parts) VALUES (?)", parts)
-			if err != nil {
-				return fmt.Errorf("error creating word: %v", err)
-			}
+	storage.SetDB(db)
+	fixed, err := service.NewGroupService().RepairWordsCounts()
+	if err != nil {
+		return fmt.Errorf("error repairing words_count: %v", err)
+	}
 
-			wordID, err := result.LastInsertId()
-			if err != nil {
-				return fmt.Errorf("error getting word ID: %v", err)
-			}
+	fmt.Printf("Repaired words_count for %d groups\n", fixed)
+	return nil
+}
 
-			_, err = db.Exec("INSERT INTO word_groups (word_id, group_id) VALUES (?, ?)", wordID, groupID)
-			if err != nil {
-				return fmt.Errorf("error creating word_group: %v", err)
-			}
+// Reset resets all data in the database after taking a snapshot of it
+func Reset() error {
+	return withBackups(func(db *sql.DB, backups *service.BackupService) error {
+		snapshot, err := backups.Snapshot(service.ActionFullReset)
+		if err != nil {
+			return fmt.Errorf("error taking a snapshot: %v", err)
 		}
+		fmt.Printf("Saved snapshot %s\n", snapshot.Name)
+
+		_, err = db.Exec(`
+			DELETE FROM history_resets;
+			DELETE FROM word_review_items;
+			DELETE FROM word_review_states;
+			DELETE FROM study_sessions;
+			DELETE FROM class_assignments;
+			DELETE FROM word_groups;
+			DELETE FROM words;
+			DELETE FROM groups;
+			DELETE FROM study_activities;
+		`)
+		return err
+	})
+}
 
-		// Update words_count
-		_, err = db.Exec("UPDATE groups SET words_count = ? WHERE id = ?", len(seedData.Words), groupID)
+// Backup takes a snapshot of the database
+func Backup() error {
+	return withBackups(func(db *sql.DB, backups *service.BackupService) error {
+		snapshot, err := backups.Snapshot(service.SnapshotManual)
 		if err != nil {
-			return fmt.Errorf("error updating words_count: %v", err)
+			return fmt.Errorf("error taking a snapshot: %v", err)
 		}
-	}
 
-	// Process study activities
-	activityFiles, err := filepath.Glob("db/seeds/study_activities.json")
-	if err != nil {
-		return fmt.Errorf("error finding activity seed files: %v", err)
-	}
+		fmt.Printf("Saved snapshot %s (%d bytes)\n", snapshot.Name, snapshot.Size)
+		return nil
+	})
+}
 
-	for _, file := range activityFiles {
-		content, err := os.ReadFile(file)
+// Backups lists the snapshots of the database, newest first
+func Backups() error {
+	return withBackups(func(db *sql.DB, backups *service.BackupService) error {
+		snapshots, err := backups.List()
 		if err != nil {
-			return fmt.Errorf("error reading activity seed file %s: %v", file, err)
+			return fmt.Errorf("error listing snapshots: %v", err)
 		}
 
-		var activityData struct {
-			Activities []struct {
-				Name         string `json:"name"`
-				URL          string `json:"url"`
-				ThumbnailURL string `json:"thumbnail_url"`
-				Description  string `json:"description"`
-			} `json:"activities"`
-		}
-
-		if err := json.Unmarshal(content, &activityData); err != nil {
-			return fmt.Errorf("error parsing activity seed file %s: %v", file, err)
+		for _, snapshot := range snapshots {
+			fmt.Printf("%s\t%s\t%d bytes\n", snapshot.Name, snapshot.Reason, snapshot.Size)
 		}
+		return nil
+	})
+}
 
-		for _, activity := range activityData.Activities {
-			_, err := db.Exec(`
-				INSERT INTO study_activities (name, url, thumbnail_url, description)
-				VALUES (?, ?, ?, ?)
-			`, activity.Name, activity.URL, activity.ThumbnailURL, activity.Description)
-			if err != nil {
-				return fmt.Errorf("error creating activity: %v", err)
-			}
+// Restore replaces the database with the named snapshot, after taking a
+// snapshot of the database it replaces
+func Restore(name string) error {
+	return withBackups(func(db *sql.DB, backups *service.BackupService) error {
+		previous, err := backups.RestoreSnapshot(name)
+		if err != nil {
+			return fmt.Errorf("error restoring %s: %v", name, err)
 		}
-	}
 
-	return nil
+		fmt.Printf("Restored %s; the previous database is snapshot %s\n", name, previous.Name)
+		return nil
+	})
 }
 
-// Reset resets all data in the database
-func Reset() error {
-	db, err := sql.Open("sqlite3", dbName)
+func withBackups(fn func(db *sql.DB, backups *service.BackupService) error) error {
+	db, err := storage.Open(dbName)
 	if err != nil {
 		return fmt.Errorf("error opening database: %v", err)
 	}
 	defer db.Close()
 
-	_, err = db.Exec(`
-		DELETE FROM word_review_items;
-		DELETE FROM study_sessions;
-		DELETE FROM word_groups;
-		DELETE FROM words;
-		DELETE FROM groups;
-		DELETE FROM study_activities;
-	`)
+	dir := os.Getenv("BACKUP_DIR")
+	if dir == "" {
+		dir = backupDir
+	}
+	storage.SetDB(db)
+	storage.SetBackupDir(dir)
 
-	return err
+	return fn(db, service.NewBackupService())
 }